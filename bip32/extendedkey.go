@@ -383,32 +383,13 @@ func (k *ExtendedKey) ECPrivKey() (*ec.PrivateKey, error) {
 // Address converts the extended key to a standard bitcoin pay-to-pubkey-hash
 // address for the passed network.
 func (k *ExtendedKey) Address(net *chaincfg.Params) string {
-	return k.addressFromPublicKeyHash(crypto.Hash160(k.pubKeyBytes()), net.Name == chaincfg.NetworkMain)
+	return k.addressFromPublicKeyHash(crypto.Hash160(k.pubKeyBytes()), net.LegacyPubKeyHashAddrID)
 }
 
-// addressFromPublicKeyHash is copied from the bt.bscript package to remove a small
-// dependency from bk -> bt. Adding this means bk has no dependency on bt.
-func (k *ExtendedKey) addressFromPublicKeyHash(hash []byte, mainnet bool) string {
-	// regtest := 111
-	// mainnet: 0
-
-	bb := make([]byte, 1)
-	if !mainnet {
-		bb[0] = 111
-	}
-	// nolint:makezero // ignore
-	bb = append(bb, hash...)
-	b := make([]byte, 0, len(bb)+4)
-	b = append(b, bb[:]...)
-	ckSum := k.checksum(b)
-	b = append(b, ckSum[:]...)
-	return base58.Encode(b)
-}
-
-func (k *ExtendedKey) checksum(input []byte) (ckSum [4]byte) {
-	h := crypto.Sha256d(input)
-	copy(ckSum[:], h[:4])
-	return
+// addressFromPublicKeyHash base58check encodes a P2PKH address without
+// depending on the bscript package.
+func (k *ExtendedKey) addressFromPublicKeyHash(hash []byte, addrID byte) string {
+	return base58.CheckEncode(hash, addrID)
 }
 
 // paddedAppend appends the src byte slice to dst, returning the new slice.
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/chaincfg"
 	"github.com/bitcoin-sv/go-sdk/ec"
 	"github.com/stretchr/testify/assert"
 )
@@ -1114,3 +1115,35 @@ func BenchmarkGetHDKeyFromExtendedPublicKey(b *testing.B) {
 		_, _ = GetHDKeyFromExtendedPublicKey(xPub)
 	}
 }
+
+// TestNewMasterForNet will test NewMaster() and Address() across networks
+func TestNewMasterForNet(t *testing.T) {
+	t.Parallel()
+
+	seed := make([]byte, RecommendedSeedLength)
+
+	tests := map[string]struct {
+		net       *chaincfg.Params
+		expPrefix string
+		mainnet   bool
+	}{
+		"mainnet": {net: &chaincfg.MainNet, expPrefix: "xprv", mainnet: true},
+		"testnet": {net: &chaincfg.TestNet, expPrefix: "tprv"},
+		"regtest": {net: &chaincfg.RegTest, expPrefix: "tprv"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, err := NewMaster(seed, test.net)
+			assert.NoError(t, err)
+			assert.True(t, key.IsForNet(test.net))
+			assert.Equal(t, test.expPrefix, key.String()[:4])
+
+			pubKey, err := key.ECPubKey()
+			assert.NoError(t, err)
+			addr, err := bscript.NewAddressFromPublicKey(pubKey, test.mainnet)
+			assert.NoError(t, err)
+			assert.Equal(t, addr.AddressString, key.Address(test.net))
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/bitcoin-sv/go-sdk/base58"
+	"github.com/bitcoin-sv/go-sdk/chaincfg"
 	"github.com/bitcoin-sv/go-sdk/crypto"
 	"github.com/bitcoin-sv/go-sdk/ec"
 	"github.com/bitcoin-sv/go-sdk/util"
@@ -77,19 +78,18 @@ func NewAddressFromPublicKeyString(pubKey string, mainnet bool) (*Address, error
 // If mainnet parameter is true it will return a mainnet address (starting with a 1).
 // Otherwise, (mainnet is false) it will return a testnet address (starting with an m or n).
 func NewAddressFromPublicKeyHash(hash []byte, mainnet bool) (*Address, error) {
+	return NewAddressFromPublicKeyHashForNet(hash, netParams(mainnet))
+}
 
-	// regtest := 111
-	// mainnet: 0
-
-	bb := make([]byte, 1)
-	if !mainnet {
-		bb[0] = 111
+// NewAddressFromPublicKeyHashForNet takes a public key hash in bytes and returns an Address
+// struct pointer encoded with the P2PKH version byte of the passed network.
+func NewAddressFromPublicKeyHashForNet(hash []byte, net *chaincfg.Params) (*Address, error) {
+	if net == nil {
+		return nil, ErrNoNetwork
 	}
-	//nolint: makezero // we need to set up the array with 1
-	bb = append(bb, hash...)
 
 	return &Address{
-		AddressString: Base58EncodeMissingChecksum(bb),
+		AddressString: base58.CheckEncode(hash, net.LegacyPubKeyHashAddrID),
 		PublicKeyHash: hash,
 	}, nil
 }
@@ -98,22 +98,21 @@ func NewAddressFromPublicKeyHash(hash []byte, mainnet bool) (*Address, error) {
 // If mainnet parameter is true it will return a mainnet address (starting with a 1).
 // Otherwise, (mainnet is false) it will return a testnet address (starting with an m or n).
 func NewAddressFromPublicKey(pubKey *ec.PublicKey, mainnet bool) (*Address, error) {
-	hash := crypto.Hash160(pubKey.SerialiseCompressed())
+	return NewAddressFromPublicKeyForNet(pubKey, netParams(mainnet))
+}
 
-	// regtest := 111
-	// mainnet: 0
+// NewAddressFromPublicKeyForNet takes a bec public key and returns an Address struct pointer
+// encoded for the passed network.
+func NewAddressFromPublicKeyForNet(pubKey *ec.PublicKey, net *chaincfg.Params) (*Address, error) {
+	return NewAddressFromPublicKeyHashForNet(crypto.Hash160(pubKey.SerialiseCompressed()), net)
+}
 
-	bb := make([]byte, 1)
-	if !mainnet {
-		bb[0] = 111
+// netParams maps the legacy mainnet flag onto network parameters.
+func netParams(mainnet bool) *chaincfg.Params {
+	if mainnet {
+		return &chaincfg.MainNet
 	}
-	//nolint: makezero // we need to set up the array with 1
-	bb = append(bb, hash...)
-
-	return &Address{
-		AddressString: Base58EncodeMissingChecksum(bb),
-		PublicKeyHash: hash,
-	}, nil
+	return &chaincfg.TestNet
 }
 
 // Base58EncodeMissingChecksum appends a checksum to a byte sequence
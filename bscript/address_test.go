@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/chaincfg"
 	"github.com/bitcoin-sv/go-sdk/ec"
 	"github.com/stretchr/testify/assert"
 )
@@ -102,6 +103,45 @@ func TestNewAddressFromPublicKey(t *testing.T) {
 	assert.Equal(t, "114ZWApV4EEU8frr7zygqQcB1V2BodGZuS", addr.AddressString)
 }
 
+func TestNewAddressFromPublicKeyHashForNet(t *testing.T) {
+	t.Parallel()
+
+	hash, err := hex.DecodeString(testPublicKeyHash)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		net     *chaincfg.Params
+		expAddr string
+	}{
+		"mainnet": {
+			net:     &chaincfg.MainNet,
+			expAddr: "114ZWApV4EEU8frr7zygqQcB1V2BodGZuS",
+		},
+		"testnet": {
+			net:     &chaincfg.TestNet,
+			expAddr: "mfaWoDuTsFfiunLTqZx4fKpVsUctiDV9jk",
+		},
+		"regtest": {
+			net:     &chaincfg.RegTest,
+			expAddr: "mfaWoDuTsFfiunLTqZx4fKpVsUctiDV9jk",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			addr, err := bscript.NewAddressFromPublicKeyHashForNet(hash, test.net)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expAddr, addr.AddressString)
+			assert.Equal(t, testPublicKeyHash, addr.PublicKeyHash.String())
+		})
+	}
+
+	t.Run("nil network", func(t *testing.T) {
+		_, err := bscript.NewAddressFromPublicKeyHashForNet(hash, nil)
+		assert.ErrorIs(t, err, bscript.ErrNoNetwork)
+	})
+}
+
 func TestBase58EncodeMissingChecksum(t *testing.T) {
 	t.Parallel()
 
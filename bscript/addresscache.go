@@ -0,0 +1,93 @@
+package bscript
+
+import "sync"
+
+// AddressCache caches the addresses and public key hash derived from locking
+// scripts, keyed by the exact script bytes, so that scanning many outputs paying
+// to the same scripts does not repeat the derivation.
+//
+// The zero value is ready to use, and it is safe for concurrent use. Entries
+// are never evicted, so long running callers scanning unbounded sets of scripts
+// should call Clear periodically.
+type AddressCache struct {
+	mu      sync.RWMutex
+	entries map[string]*addressCacheEntry
+}
+
+type addressCacheEntry struct {
+	addresses    []string
+	addressesErr error
+	pkh          []byte
+	pkhErr       error
+}
+
+// NewAddressCache returns an empty AddressCache.
+func NewAddressCache() *AddressCache {
+	return &AddressCache{entries: make(map[string]*addressCacheEntry)}
+}
+
+// Addresses returns the result of s.Addresses, computing it only the first time
+// the script bytes are seen.
+func (c *AddressCache) Addresses(s *Script) ([]string, error) {
+	if s == nil {
+		return nil, ErrEmptyScript
+	}
+	e := c.entry(s)
+	if e.addressesErr != nil {
+		return nil, e.addressesErr
+	}
+	return append([]string(nil), e.addresses...), nil
+}
+
+// PublicKeyHash returns the result of s.PublicKeyHash, computing it only the
+// first time the script bytes are seen.
+func (c *AddressCache) PublicKeyHash(s *Script) ([]byte, error) {
+	if s == nil {
+		return nil, ErrEmptyScript
+	}
+	e := c.entry(s)
+	if e.pkhErr != nil {
+		return nil, e.pkhErr
+	}
+	return append([]byte(nil), e.pkh...), nil
+}
+
+// Len returns the number of scripts cached.
+func (c *AddressCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Clear removes every cached entry.
+func (c *AddressCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*addressCacheEntry)
+}
+
+func (c *AddressCache) entry(s *Script) *addressCacheEntry {
+	c.mu.RLock()
+	e, ok := c.entries[string(*s)]
+	c.mu.RUnlock()
+	if ok {
+		return e
+	}
+
+	// Derive outside the lock, as concurrent callers deriving the same script
+	// produce the same entry.
+	e = &addressCacheEntry{}
+	e.addresses, e.addressesErr = s.Addresses()
+	e.pkh, e.pkhErr = s.PublicKeyHash()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*addressCacheEntry)
+	}
+	if existing, ok := c.entries[string(*s)]; ok {
+		return existing
+	}
+	c.entries[string(*s)] = e
+	return e
+}
@@ -0,0 +1,126 @@
+package bscript_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressCache(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	p2pkh, err := bscript.NewP2PKHFromPubKeyEC(priv.PubKey())
+	assert.NoError(t, err)
+	data, err := bscript.NewFromASM("OP_FALSE OP_RETURN 68656c6c6f")
+	assert.NoError(t, err)
+
+	t.Run("matches uncached derivation", func(t *testing.T) {
+		var c bscript.AddressCache
+		for i := 0; i < 2; i++ {
+			addrs, err := c.Addresses(p2pkh)
+			assert.NoError(t, err)
+			want, err := p2pkh.Addresses()
+			assert.NoError(t, err)
+			assert.Equal(t, want, addrs)
+
+			pkh, err := c.PublicKeyHash(p2pkh)
+			assert.NoError(t, err)
+			assert.Equal(t, crypto.Hash160(priv.PubKey().SerialiseCompressed()), pkh)
+
+			addrs, err = c.Addresses(data)
+			assert.NoError(t, err)
+			assert.Empty(t, addrs)
+			_, err = c.PublicKeyHash(data)
+			assert.ErrorIs(t, err, bscript.ErrNotP2PKH)
+		}
+		assert.Equal(t, 2, c.Len())
+
+		_, err := c.Addresses(nil)
+		assert.ErrorIs(t, err, bscript.ErrEmptyScript)
+	})
+
+	t.Run("returned values are copies", func(t *testing.T) {
+		c := bscript.NewAddressCache()
+		pkh, err := c.PublicKeyHash(p2pkh)
+		assert.NoError(t, err)
+		pkh[0] ^= 0xff
+
+		pkh, err = c.PublicKeyHash(p2pkh)
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.Hash160(priv.PubKey().SerialiseCompressed()), pkh)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		c := bscript.NewAddressCache()
+		_, err := c.Addresses(p2pkh)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, c.Len())
+
+		c.Clear()
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		c := bscript.NewAddressCache()
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_, err := c.Addresses(p2pkh)
+					assert.NoError(t, err)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 1, c.Len())
+	})
+}
+
+func BenchmarkAddressCache(b *testing.B) {
+	// 10,000 outputs paying to 100 distinct scripts, as when scanning a wallet.
+	scripts := make([]*bscript.Script, 100)
+	for i := range scripts {
+		priv, err := ec.NewPrivateKey()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if scripts[i], err = bscript.NewP2PKHFromPubKeyEC(priv.PubKey()); err != nil {
+			b.Fatal(err)
+		}
+	}
+	outputs := make([]*bscript.Script, 10_000)
+	for i := range outputs {
+		outputs[i] = scripts[i%len(scripts)]
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, s := range outputs {
+				if _, err := s.Addresses(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		c := bscript.NewAddressCache()
+		for i := 0; i < b.N; i++ {
+			for _, s := range outputs {
+				if _, err := c.Addresses(s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -13,6 +13,7 @@ var (
 var (
 	ErrInvalidAddressLength = errors.New("invalid address length")
 	ErrUnsupportedAddress   = errors.New("address not supported")
+	ErrNoNetwork            = errors.New("no network")
 )
 
 // Sentinel errors raised by inscriptions.
@@ -36,5 +37,8 @@ var (
 	ErrInvalidOpCode     = errors.New("invalid opcode data")
 	ErrEmptyScript       = errors.New("script is empty")
 	ErrNotP2PKH          = errors.New("not a P2PKH")
+	ErrNotMultiSig       = errors.New("not a multisig")
 	ErrInvalidOpcodeType = errors.New("use AppendPushData for push data funcs")
+	ErrNonMinimalPush    = errors.New("data push is not minimally encoded")
+	ErrInvalidScriptHash = errors.New("script hash must be 20 bytes")
 )
@@ -995,3 +995,77 @@ func TestExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckDataSig(t *testing.T) {
+	t.Parallel()
+
+	const (
+		pubKey = "2102165b8cbf1f44f31955f28dc8e8ed489fc8e968f8873ef93cefd229c0954f9b3f"
+		sig    = "473045022100e8935e68ba16e80f32a3e9269764ccd18d1d9f74c45a4bf9446795660c9b6488022011b0a6e9333670489718f9cbd05680a20041a6c67b2fb52a84a07c71fe47aa13"
+		msg    = "0d4254432f555344203638303030" // "BTC/USD 68000"
+		badMsg = "0d4254432f555344203130303030" // "BTC/USD 10000"
+	)
+
+	tests := map[string]struct {
+		lscript string
+		uscript string
+		flags   scriptflag.Flag
+		expErr  errs.ErrorCode
+	}{
+		"valid signature": {
+			lscript: pubKey + "ba",
+			uscript: sig + msg,
+			flags:   scriptflag.EnableCheckDataSig,
+			expErr:  errs.ErrOK,
+		},
+		"valid signature with verify": {
+			lscript: pubKey + "bb51",
+			uscript: sig + msg,
+			flags:   scriptflag.EnableCheckDataSig,
+			expErr:  errs.ErrOK,
+		},
+		"wrong message": {
+			lscript: pubKey + "ba",
+			uscript: sig + badMsg,
+			flags:   scriptflag.EnableCheckDataSig,
+			expErr:  errs.ErrEvalFalse,
+		},
+		"wrong message with verify": {
+			lscript: pubKey + "bb51",
+			uscript: sig + badMsg,
+			flags:   scriptflag.EnableCheckDataSig,
+			expErr:  errs.ErrCheckDataSigVerify,
+		},
+		"wrong message with null fail": {
+			lscript: pubKey + "ba",
+			uscript: sig + badMsg,
+			flags:   scriptflag.EnableCheckDataSig | scriptflag.VerifyNullFail,
+			expErr:  errs.ErrNullFail,
+		},
+		"not enabled": {
+			lscript: pubKey + "ba",
+			uscript: sig + msg,
+			expErr:  errs.ErrReservedOpcode,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			lscript, err := bscript.NewFromHex(test.lscript)
+			require.NoError(t, err)
+			uscript, err := bscript.NewFromHex(test.uscript)
+			require.NoError(t, err)
+
+			err = NewEngine().Execute(
+				WithScripts(lscript, uscript),
+				WithAfterGenesis(),
+				WithFlags(test.flags),
+			)
+			if test.expErr == errs.ErrOK {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, errs.IsErrorCode(err, test.expErr), "expected %s, got %v", test.expErr, err)
+		})
+	}
+}
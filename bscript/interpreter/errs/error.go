@@ -325,6 +325,11 @@ const (
 	// set, but the ScriptEnableSighashForkID flag is not set.
 	ErrIllegalForkID
 
+	// ErrCheckDataSigVerify is returned when OP_CHECKDATASIGVERIFY is
+	// encountered in a script and the top item on the data stack does not
+	// evaluate to true.
+	ErrCheckDataSigVerify
+
 	// numErrorCodes is the maximum error code number used in tests.  This
 	// entry MUST be the last entry in the enum.
 	numErrorCodes
@@ -394,6 +399,7 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrNegativeLockTime:         "ErrNegativeLockTime",
 	ErrUnsatisfiedLockTime:      "ErrUnsatisfiedLockTime",
 	ErrIllegalForkID:            "ErrIllegalForkID",
+	ErrCheckDataSigVerify:       "ErrCheckDataSigVerify",
 }
 
 // String returns the ErrorCode as a human-readable name.
@@ -78,6 +78,7 @@ func TestErrorCodeStringer(t *testing.T) {
 		{ErrNegativeLockTime, "ErrNegativeLockTime"},
 		{ErrUnsatisfiedLockTime, "ErrUnsatisfiedLockTime"},
 		{ErrIllegalForkID, "ErrIllegalForkID"},
+		{ErrCheckDataSigVerify, "ErrCheckDataSigVerify"},
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
 
@@ -240,9 +240,11 @@ var opcodeArray = [256]opcode{
 	bscript.OpNOP9:  {bscript.OpNOP9, "OP_NOP9", 1, opcodeNop},
 	bscript.OpNOP10: {bscript.OpNOP10, "OP_NOP10", 1, opcodeNop},
 
+	// Data signature opcodes.
+	bscript.OpCHECKDATASIG:       {bscript.OpCHECKDATASIG, "OP_CHECKDATASIG", 1, opcodeCheckDataSig},
+	bscript.OpCHECKDATASIGVERIFY: {bscript.OpCHECKDATASIGVERIFY, "OP_CHECKDATASIGVERIFY", 1, opcodeCheckDataSigVerify},
+
 	// Undefined opcodes.
-	bscript.OpUNKNOWN186: {bscript.OpUNKNOWN186, "OP_UNKNOWN186", 1, opcodeInvalid},
-	bscript.OpUNKNOWN187: {bscript.OpUNKNOWN187, "OP_UNKNOWN187", 1, opcodeInvalid},
 	bscript.OpUNKNOWN188: {bscript.OpUNKNOWN188, "OP_UNKNOWN188", 1, opcodeInvalid},
 	bscript.OpUNKNOWN189: {bscript.OpUNKNOWN189, "OP_UNKNOWN189", 1, opcodeInvalid},
 	bscript.OpUNKNOWN190: {bscript.OpUNKNOWN190, "OP_UNKNOWN190", 1, opcodeInvalid},
@@ -2022,6 +2024,89 @@ func opcodeCheckSigVerify(op *ParsedOpcode, t *thread) error {
 	return abstractVerify(op, t, errs.ErrCheckSigVerify)
 }
 
+// opcodeCheckDataSig treats the top 3 items on the data stack as a signature,
+// a message and a public key.  It hashes the message with SHA-256 and pushes
+// true onto the data stack if the signature is a valid signature of that
+// hash by the public key, or false otherwise.
+//
+// Unlike OP_CHECKSIG, the signature commits to arbitrary data rather than the
+// spending transaction, so it carries no hash type byte.  The opcode is only
+// executed when the EnableCheckDataSig flag is set and is otherwise invalid.
+//
+// Stack transformation: [... signature message pubkey] -> [... bool]
+func opcodeCheckDataSig(op *ParsedOpcode, t *thread) error {
+	if !t.hasFlag(scriptflag.EnableCheckDataSig) {
+		return opcodeInvalid(op, t)
+	}
+
+	pkBytes, err := t.dstack.PopByteArray()
+	if err != nil {
+		return err
+	}
+
+	msg, err := t.dstack.PopByteArray()
+	if err != nil {
+		return err
+	}
+
+	sigBytes, err := t.dstack.PopByteArray()
+	if err != nil {
+		return err
+	}
+
+	if len(sigBytes) == 0 {
+		t.dstack.PushBool(false)
+		return nil
+	}
+
+	if err = t.checkSignatureEncoding(sigBytes); err != nil {
+		return err
+	}
+	if err = t.checkPubKeyEncoding(pkBytes); err != nil {
+		return err
+	}
+
+	pubKey, err := ec.ParsePubKey(pkBytes)
+	if err != nil {
+		t.dstack.PushBool(false)
+		return nil //nolint:nilerr // only need a false push in this case
+	}
+
+	var signature *ec.Signature
+	if t.hasAny(scriptflag.VerifyStrictEncoding, scriptflag.VerifyDERSignatures) {
+		signature, err = ec.ParseDERSignature(sigBytes)
+	} else {
+		signature, err = ec.ParseSignature(sigBytes)
+	}
+	if err != nil {
+		t.dstack.PushBool(false)
+		return nil //nolint:nilerr // only need a false push in this case
+	}
+
+	hash := sha256.Sum256(msg)
+	ok := signature.Verify(hash[:], pubKey)
+	if !ok && t.hasFlag(scriptflag.VerifyNullFail) {
+		return errs.NewError(errs.ErrNullFail, "signature not empty on failed checkdatasig")
+	}
+
+	t.dstack.PushBool(ok)
+	return nil
+}
+
+// opcodeCheckDataSigVerify is a combination of opcodeCheckDataSig and
+// opcodeVerify.  The opcodeCheckDataSig function is invoked followed by
+// opcodeVerify.  See the documentation for each of those opcodes for more
+// details.
+//
+// Stack transformation: [... signature message pubkey] -> [... bool] -> [...]
+func opcodeCheckDataSigVerify(op *ParsedOpcode, t *thread) error {
+	if err := opcodeCheckDataSig(op, t); err != nil {
+		return err
+	}
+
+	return abstractVerify(op, t, errs.ErrCheckDataSigVerify)
+}
+
 // parsedSigInfo houses a raw signature along with its parsed form and a flag
 // for whether or not it has already been parsed.  It is used to prevent parsing
 // the same signature multiple times when verifying a multisig.
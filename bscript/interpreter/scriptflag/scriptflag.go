@@ -78,6 +78,11 @@ const (
 	// VerifyMinimalIf defines the enforcement of any conditional statement using the
 	// minimum required data.
 	VerifyMinimalIf
+
+	// EnableCheckDataSig defines whether to allow execution of the
+	// OP_CHECKDATASIG and OP_CHECKDATASIGVERIFY opcodes.  When not set,
+	// these opcodes are treated as invalid.
+	EnableCheckDataSig
 )
 
 // HasFlag returns whether the Flags has the passed flag set.
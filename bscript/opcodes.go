@@ -197,7 +197,9 @@ const (
 	OpNOP9                byte = 0xb8 // 184
 	OpNOP10               byte = 0xb9 // 185
 	OpUNKNOWN186          byte = 0xba // 186
+	OpCHECKDATASIG        byte = 0xba // 186
 	OpUNKNOWN187          byte = 0xbb // 187
+	OpCHECKDATASIGVERIFY  byte = 0xbb // 187
 	OpUNKNOWN188          byte = 0xbc // 188
 	OpUNKNOWN189          byte = 0xbd // 189
 	OpUNKNOWN190          byte = 0xbe // 190
@@ -103,6 +103,24 @@ func NewP2PKHFromPubKeyHash(pubKeyHash []byte) (*Script, error) {
 	return &s, nil
 }
 
+// NewP2SH takes the hash160 of a redeem script and creates a P2SH
+// (OP_HASH160 <hash> OP_EQUAL) script from it.
+//
+// P2SH is not evaluated for outputs created after the Genesis upgrade, so
+// this is only useful for matching and spending historical outputs.
+func NewP2SH(redeemScriptHash []byte) (*Script, error) {
+	if len(redeemScriptHash) != 20 {
+		return nil, ErrInvalidScriptHash
+	}
+	b := make([]byte, 0, 23)
+	b = append(b, OpHASH160, OpDATA20)
+	b = append(b, redeemScriptHash...)
+	b = append(b, OpEQUAL)
+
+	s := Script(b)
+	return &s, nil
+}
+
 // NewP2PKHFromPubKeyHashStr takes a public key hex string (in
 // compressed format) and creates a P2PKH script from it.
 func NewP2PKHFromPubKeyHashStr(pubKeyHash string) (*Script, error) {
@@ -383,6 +401,43 @@ func (s *Script) IsMultiSigOut() bool {
 		parts[len(parts)-1][0] == OpCHECKMULTISIG
 }
 
+// MultisigInfo parses a bare multisig locking script of the form
+// OP_m <pubkey>... OP_n OP_CHECKMULTISIG and returns the number of
+// signatures required along with the public keys, in script order.
+// ErrNotMultiSig is returned if the script is not of that form.
+func (s *Script) MultisigInfo() (int, [][]byte, error) {
+	if !s.IsMultiSigOut() {
+		return 0, nil, ErrNotMultiSig
+	}
+
+	parts, err := DecodeParts(*s)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	threshold := smallIntValue(parts[0][0])
+	total := smallIntValue(parts[len(parts)-2][0])
+	pubKeys := parts[1 : len(parts)-2]
+	if total != len(pubKeys) || threshold > total {
+		return 0, nil, fmt.Errorf("%w: %d-of-%d with %d public keys", ErrNotMultiSig, threshold, total, len(pubKeys))
+	}
+
+	for _, pk := range pubKeys {
+		if len(pk) != 33 && len(pk) != 65 {
+			return 0, nil, fmt.Errorf("%w: %w", ErrNotMultiSig, ErrInvalidPKLen)
+		}
+	}
+
+	return threshold, pubKeys, nil
+}
+
+func smallIntValue(opcode byte) int {
+	if opcode == OpZERO {
+		return 0
+	}
+	return int(opcode-OpONE) + 1
+}
+
 func isSmallIntOp(opcode byte) bool {
 	return opcode == OpZERO || (opcode >= OpONE && opcode <= Op16)
 }
@@ -462,6 +517,54 @@ func (s *Script) EqualsHex(h string) bool {
 	return s.String() == h
 }
 
+// CheckMinimalPushes checks that every data push in the script uses the smallest
+// encoding possible, as required for standard scripts: OP_0 for empty data,
+// OP_1 to OP_16 and OP_1NEGATE for their single byte values, OP_DATA_X up to
+// 75 bytes, and then OP_PUSHDATA1, 2 and 4 in turn.
+//
+// Anything after an OP_RETURN is not executed, so is not checked. An error
+// matching ErrNonMinimalPush, giving the offending push, is returned otherwise.
+func (s *Script) CheckMinimalPushes() error {
+	pos := 0
+	for pos < len(*s) {
+		start := pos
+		op, err := s.ReadOp(&pos)
+		if err != nil {
+			return err
+		}
+		if op.OpCode == OpRETURN {
+			return nil
+		}
+		if op.OpCode < OpDATA1 || op.OpCode > OpPUSHDATA4 {
+			continue
+		}
+
+		var want byte
+		switch l := len(op.Data); {
+		case l == 0:
+			want = Op0
+		case l == 1 && op.Data[0] >= 1 && op.Data[0] <= 16:
+			want = Op1 + op.Data[0] - 1
+		case l == 1 && op.Data[0] == 0x81:
+			want = Op1NEGATE
+		case l <= 75:
+			want = byte(l)
+		case l <= 0xff:
+			want = OpPUSHDATA1
+		case l <= 0xffff:
+			want = OpPUSHDATA2
+		default:
+			want = OpPUSHDATA4
+		}
+		if op.OpCode != want {
+			return fmt.Errorf("%w: %d bytes at position %d pushed with %s instead of %s",
+				ErrNonMinimalPush, len(op.Data), start, OpCodeValues[op.OpCode], OpCodeValues[want])
+		}
+	}
+
+	return nil
+}
+
 // MinPushSize returns the minimum size of a push operation of the given data.
 func MinPushSize(bb []byte) int {
 	l := len(bb)
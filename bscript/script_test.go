@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
@@ -49,6 +50,20 @@ func TestNewP2PKHFromPubKey(t *testing.T) {
 	)
 }
 
+func TestNewP2SH(t *testing.T) {
+	t.Parallel()
+
+	hash, err := hex.DecodeString("9de5aeaff9c48431ba4dd6e8af73d51f38e451cb")
+	assert.NoError(t, err)
+	s, err := bscript.NewP2SH(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, "a9149de5aeaff9c48431ba4dd6e8af73d51f38e451cb87", s.String())
+	assert.True(t, s.IsP2SH())
+
+	_, err = bscript.NewP2SH(hash[:19])
+	assert.ErrorIs(t, err, bscript.ErrInvalidScriptHash)
+}
+
 func TestNewFromHex(t *testing.T) {
 	t.Parallel()
 
@@ -171,6 +186,56 @@ func TestScript_IsMultisigOut(t *testing.T) { // TODO: check this
 	})
 }
 
+func TestScript_MultisigInfo(t *testing.T) {
+	t.Parallel()
+
+	pubKeys := make([][]byte, 3)
+	for i := range pubKeys {
+		priv, err := ec.NewPrivateKey()
+		assert.NoError(t, err)
+		pubKeys[i] = priv.PubKey().SerialiseCompressed()
+	}
+
+	t.Run("2-of-3", func(t *testing.T) {
+		s := &bscript.Script{}
+		assert.NoError(t, s.AppendOpcodes(bscript.Op2))
+		assert.NoError(t, s.AppendPushDataArray(pubKeys))
+		assert.NoError(t, s.AppendOpcodes(bscript.Op3, bscript.OpCHECKMULTISIG))
+
+		threshold, keys, err := s.MultisigInfo()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, threshold)
+		assert.Equal(t, pubKeys, keys)
+	})
+
+	t.Run("threshold above key count", func(t *testing.T) {
+		s := &bscript.Script{}
+		assert.NoError(t, s.AppendOpcodes(bscript.Op3))
+		assert.NoError(t, s.AppendPushDataArray(pubKeys[:2]))
+		assert.NoError(t, s.AppendOpcodes(bscript.Op2, bscript.OpCHECKMULTISIG))
+
+		_, _, err := s.MultisigInfo()
+		assert.ErrorIs(t, err, bscript.ErrNotMultiSig)
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		b, err := hex.DecodeString("5201110122013353ae")
+		assert.NoError(t, err)
+
+		_, _, err = bscript.NewFromBytes(b).MultisigInfo()
+		assert.ErrorIs(t, err, bscript.ErrNotMultiSig)
+		assert.ErrorIs(t, err, bscript.ErrInvalidPKLen)
+	})
+
+	t.Run("p2pkh", func(t *testing.T) {
+		s, err := bscript.NewP2PKHFromPubKeyBytes(pubKeys[0])
+		assert.NoError(t, err)
+
+		_, _, err = s.MultisigInfo()
+		assert.ErrorIs(t, err, bscript.ErrNotMultiSig)
+	})
+}
+
 func TestScript_PublicKeyHash(t *testing.T) {
 	t.Parallel()
 
@@ -679,3 +744,44 @@ func TestSpendValid(t *testing.T) {
 		})
 	}
 }
+
+func TestScript_CheckMinimalPushes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		hex     string
+		minimal bool
+	}{
+		"p2pkh":                      {"76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac", true},
+		"empty":                      {"", true},
+		"op_0":                       {"00", true},
+		"op_1":                       {"51", true},
+		"zero byte with op_data_1":   {"0100", true},
+		"empty with op_pushdata1":    {"4c00", false},
+		"small int with op_data_1":   {"0105", false},
+		"negative one with data":     {"0181", false},
+		"short with op_pushdata1":    {"4c0401020304", false},
+		"data after op_return":       {"006a4c0401020304", true},
+		"short with op_pushdata2":    {"4d0400" + "01020304", false},
+		"pushdata1 for 76 bytes":     {"4c4c" + strings.Repeat("ab", 76), true},
+		"op_data for 75 bytes":       {"4b" + strings.Repeat("ab", 75), true},
+		"op_pushdata1 for 75 bytes":  {"4c4b" + strings.Repeat("ab", 75), false},
+		"op_pushdata2 for 255 bytes": {"4dff00" + strings.Repeat("ab", 255), false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := bscript.NewFromHex(test.hex)
+			assert.NoError(t, err)
+			err = s.CheckMinimalPushes()
+			if test.minimal {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, bscript.ErrNonMinimalPush)
+			}
+		})
+	}
+
+	s, err := bscript.NewFromHex("4c05010203")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, s.CheckMinimalPushes(), bscript.ErrDataTooSmall)
+}
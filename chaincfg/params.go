@@ -8,6 +8,10 @@ import (
 const (
 	NetworkMain = "mainnet"
 	NetworkTest = "regtest"
+
+	// NetworkTestNet is an alias of TestNet accepted by ParamsForName.
+	NetworkTestNet = "testnet"
+	NetworkRegTest = "regtest"
 )
 
 var (
@@ -44,6 +48,7 @@ var MainNet = Params{
 
 	// Address encoding magics
 	LegacyPubKeyHashAddrID: 0x00, // starts with 1
+	LegacyScriptHashAddrID: 0x05, // starts with 3
 	PrivateKeyID:           0x80, // starts with 5 (uncompressed) or K (compressed)
 
 	// BIP32 hierarchical deterministic extended key magics
@@ -51,14 +56,14 @@ var MainNet = Params{
 	HDPublicKeyID:  [4]byte{0x04, 0x88, 0xb2, 0x1e}, // starts with xpub
 }
 
-// TestNet defines the network parameters for the regression test
-// Bitcoin network.  Not to be confused with the test Bitcoin network (version
-// 3), this network is sometimes simply called "testnet".
+// TestNet defines the network parameters for the test Bitcoin network
+// (version 3), commonly called "testnet".
 var TestNet = Params{
 	Name: NetworkTest,
 
 	// Address encoding magics
 	LegacyPubKeyHashAddrID: 0x6f, // starts with m or n
+	LegacyScriptHashAddrID: 0xc4, // starts with 2
 	PrivateKeyID:           0xef, // starts with 9 (uncompressed) or c (compressed)
 
 	// BIP32 hierarchical deterministic extended key magics
@@ -66,6 +71,39 @@ var TestNet = Params{
 	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
 }
 
+// RegTest defines the network parameters for the regression test Bitcoin
+// network.  It shares its address, WIF and extended key magics with TestNet,
+// so keys and addresses are interchangeable between the two.
+var RegTest = Params{
+	Name: NetworkRegTest,
+
+	// Address encoding magics
+	LegacyPubKeyHashAddrID: 0x6f, // starts with m or n
+	LegacyScriptHashAddrID: 0xc4, // starts with 2
+	PrivateKeyID:           0xef, // starts with 9 (uncompressed) or c (compressed)
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+}
+
+// ParamsForName returns the parameters of the default network with the given
+// name, as stored from Params.Name, and false if there is no such network.
+// NetworkTestNet is accepted as an alias of TestNet.
+//
+// TestNet is named NetworkTest, which is the same as NetworkRegTest, so that name
+// returns TestNet. As RegTest shares the magics of TestNet, keys and addresses
+// encode and decode the same with either.
+func ParamsForName(name string) (*Params, bool) {
+	switch name {
+	case NetworkMain:
+		return &MainNet, true
+	case NetworkTest, NetworkTestNet:
+		return &TestNet, true
+	}
+	return nil, false
+}
+
 // HDPrivateKeyToPublicKeyID accepts a private hierarchical deterministic
 // extended key id and returns the associated public key id.  When the provided
 // id is not registered, the ErrUnknownHDKeyID error will be returned.
@@ -112,4 +150,5 @@ func init() {
 	// Register all default networks when the package is initialised.
 	mustRegister(&MainNet)
 	mustRegister(&TestNet)
+	mustRegister(&RegTest)
 }
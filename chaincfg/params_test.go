@@ -0,0 +1,35 @@
+package chaincfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamsForName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name      string
+		expParams *Params
+	}{
+		"mainnet":       {name: NetworkMain, expParams: &MainNet},
+		"testnet":       {name: NetworkTest, expParams: &TestNet},
+		"testnet alias": {name: NetworkTestNet, expParams: &TestNet},
+		"regtest":       {name: NetworkRegTest, expParams: &TestNet},
+		"unknown":       {name: "stn"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			params, ok := ParamsForName(test.name)
+			assert.Equal(t, test.expParams != nil, ok)
+			assert.Equal(t, test.expParams, params)
+		})
+	}
+}
+
+func TestTestNet_Name(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, NetworkTest, TestNet.Name)
+}
@@ -0,0 +1,45 @@
+package ec
+
+import e "crypto/ecdsa"
+
+// curveBackend performs the ECDSA operations which dominate signing and verifying.
+// The pure Go goBackend is used unless the package is built with cgo and the
+// libsecp256k1 build tag, when libsecp256k1Backend is used instead:
+//
+//	go build -tags libsecp256k1 ./...
+//
+// which requires libsecp256k1 and its headers to be installed, such as from the
+// libsecp256k1-dev package, with CGO_CFLAGS and CGO_LDFLAGS set if they are not
+// in the default search paths. Both backends give identical results.
+type curveBackend interface {
+	// sign returns the RFC 6979 signature of hash, normalised to low S, passing
+	// extra, if not nil, to RFC 6979 as additional data.
+	sign(privkey *PrivateKey, hash []byte, extra []byte) (*Signature, error)
+	// verify reports whether sig is a valid signature of hash by pubKey. High S
+	// signatures are accepted.
+	verify(sig *Signature, hash []byte, pubKey *PublicKey) bool
+}
+
+// Names of the backends returned by Backend.
+const (
+	BackendGo           = "go"
+	BackendLibsecp256k1 = "libsecp256k1"
+)
+
+// Backend returns the name of the backend used for signing and verifying, either
+// BackendGo or, when built with cgo and the libsecp256k1 build tag,
+// BackendLibsecp256k1.
+func Backend() string {
+	return activeBackendName
+}
+
+// goBackend is the pure Go curveBackend.
+type goBackend struct{}
+
+func (goBackend) sign(privkey *PrivateKey, hash []byte, extra []byte) (*Signature, error) {
+	return signWithNonce(privkey, hash, nonceRFC6979WithExtra(privkey.D, hash, extra))
+}
+
+func (goBackend) verify(sig *Signature, hash []byte, pubKey *PublicKey) bool {
+	return e.Verify(pubKey.ToECDSA(), hash, sig.R, sig.S)
+}
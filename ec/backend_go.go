@@ -0,0 +1,7 @@
+//go:build !cgo || !libsecp256k1
+
+package ec
+
+var activeBackend curveBackend = goBackend{}
+
+const activeBackendName = BackendGo
@@ -0,0 +1,88 @@
+//go:build cgo && libsecp256k1
+
+package ec
+
+/*
+#cgo LDFLAGS: -lsecp256k1
+#include <secp256k1.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"math/big"
+	"unsafe"
+)
+
+var activeBackend curveBackend = libsecp256k1Backend{}
+
+const activeBackendName = BackendLibsecp256k1
+
+// secp256k1Context is used for every call. It is never modified after creation,
+// so is safe for concurrent use.
+var secp256k1Context = C.secp256k1_context_create(C.SECP256K1_CONTEXT_SIGN | C.SECP256K1_CONTEXT_VERIFY)
+
+// libsecp256k1Backend is the curveBackend using libsecp256k1 through cgo. Hashes
+// which are not 32 bytes, and additional data which is not 32 bytes, are not
+// supported by libsecp256k1, so are handled by goBackend.
+type libsecp256k1Backend struct{}
+
+func (libsecp256k1Backend) sign(privkey *PrivateKey, hash []byte, extra []byte) (*Signature, error) {
+	if len(hash) != 32 || (extra != nil && len(extra) != 32) || privkey.D.BitLen() > 256 {
+		return goBackend{}.sign(privkey, hash, extra)
+	}
+
+	seckey := make([]byte, 32)
+	privkey.D.FillBytes(seckey)
+	defer func() {
+		for i := range seckey {
+			seckey[i] = 0
+		}
+	}()
+	var ndata unsafe.Pointer
+	if extra != nil {
+		ndata = unsafe.Pointer(&extra[0])
+	}
+
+	// A nil nonce function selects RFC 6979, which reduces the hash modulo N as
+	// goBackend does, and the signature is low S, so both backends produce the
+	// same signature.
+	var sig C.secp256k1_ecdsa_signature
+	if C.secp256k1_ecdsa_sign(secp256k1Context, &sig, (*C.uchar)(&hash[0]), (*C.uchar)(&seckey[0]), nil, ndata) != 1 {
+		return nil, errors.New("libsecp256k1 failed to sign")
+	}
+	compact := make([]byte, 64)
+	C.secp256k1_ecdsa_signature_serialize_compact(secp256k1Context, (*C.uchar)(&compact[0]), &sig)
+
+	return &Signature{
+		R: new(big.Int).SetBytes(compact[:32]),
+		S: new(big.Int).SetBytes(compact[32:]),
+	}, nil
+}
+
+func (libsecp256k1Backend) verify(sig *Signature, hash []byte, pubKey *PublicKey) bool {
+	if len(hash) != 32 {
+		return goBackend{}.verify(sig, hash, pubKey)
+	}
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.BitLen() > 256 || sig.S.BitLen() > 256 {
+		return false
+	}
+
+	compact := make([]byte, 64)
+	sig.R.FillBytes(compact[:32])
+	sig.S.FillBytes(compact[32:])
+	var csig C.secp256k1_ecdsa_signature
+	if C.secp256k1_ecdsa_signature_parse_compact(secp256k1Context, &csig, (*C.uchar)(&compact[0])) != 1 {
+		return false
+	}
+	// libsecp256k1 only accepts low S signatures, unlike goBackend
+	C.secp256k1_ecdsa_signature_normalize(secp256k1Context, &csig, &csig)
+
+	serialised := pubKey.SerialiseUncompressed()
+	var cpub C.secp256k1_pubkey
+	if C.secp256k1_ec_pubkey_parse(secp256k1Context, &cpub, (*C.uchar)(&serialised[0]), C.size_t(len(serialised))) != 1 {
+		return false
+	}
+
+	return C.secp256k1_ecdsa_verify(secp256k1Context, &csig, (*C.uchar)(&hash[0]), &cpub) == 1
+}
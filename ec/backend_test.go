@@ -0,0 +1,69 @@
+package ec
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBackend_CrossCheck checks the active backend gives the same results as the
+// pure Go backend. It is only meaningful when run with the libsecp256k1 build tag:
+//
+//	go test -tags libsecp256k1 ./ec/
+//
+// CI sets REQUIRE_LIBSECP256K1 so the test fails if the tag did not take effect.
+func TestBackend_CrossCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Logf("backend: %s", Backend())
+	if os.Getenv("REQUIRE_LIBSECP256K1") != "" && Backend() != BackendLibsecp256k1 {
+		t.Fatalf("REQUIRE_LIBSECP256K1 is set but the %s backend is in use", Backend())
+	}
+	reference := goBackend{}
+	for i := 0; i < 64; i++ {
+		seed := sha256.Sum256(binary.BigEndian.AppendUint32([]byte("key"), uint32(i)))
+		priv, pub := PrivateKeyFromBytes(seed[:])
+		hash := sha256.Sum256(binary.BigEndian.AppendUint32([]byte("msg"), uint32(i)))
+		extra := sha256.Sum256(hash[:])
+
+		for _, data := range [][]byte{nil, extra[:]} {
+			want, err := reference.sign(priv, hash[:], data)
+			assert.NoError(t, err)
+			got, err := activeBackend.sign(priv, hash[:], data)
+			assert.NoError(t, err)
+			assert.True(t, want.IsEqual(got), "key %d", i)
+			assert.Equal(t, want.Serialise(), got.Serialise(), "key %d", i)
+		}
+
+		want, err := reference.sign(priv, hash[:], nil)
+		assert.NoError(t, err)
+		highS := &Signature{R: want.R, S: new(big.Int).Sub(S256().N, want.S)}
+		tampered := &Signature{R: want.R, S: new(big.Int).Add(want.S, one)}
+		_, otherPub := PrivateKeyFromBytes(hash[:])
+
+		for name, check := range map[string]struct {
+			sig  *Signature
+			hash []byte
+			pub  *PublicKey
+		}{
+			"valid":       {want, hash[:], pub},
+			"high s":      {highS, hash[:], pub},
+			"tampered":    {tampered, hash[:], pub},
+			"other key":   {want, hash[:], otherPub},
+			"short hash":  {want, hash[:20], pub},
+			"zero r":      {&Signature{R: new(big.Int), S: want.S}, hash[:], pub},
+			"r above n":   {&Signature{R: new(big.Int).Add(want.R, S256().N), S: want.S}, hash[:], pub},
+			"negative s":  {&Signature{R: want.R, S: new(big.Int).Neg(want.S)}, hash[:], pub},
+			"oversized s": {&Signature{R: want.R, S: new(big.Int).Lsh(one, 300)}, hash[:], pub},
+		} {
+			assert.Equal(t, reference.verify(check.sig, check.hash, check.pub),
+				activeBackend.verify(check.sig, check.hash, check.pub), "key %d: %s", i, name)
+		}
+		assert.True(t, activeBackend.verify(want, hash[:], pub))
+		assert.True(t, activeBackend.verify(highS, hash[:], pub))
+	}
+}
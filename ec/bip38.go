@@ -0,0 +1,137 @@
+package ec
+
+import (
+	"bytes"
+	"crypto/aes"
+	"errors"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-sdk/base58"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The scrypt parameters of BIP-38, taking around a second per key on typical
+// hardware to slow down guessing passphrases.
+const (
+	bip38ScryptN = 16384
+	bip38ScryptR = 8
+	bip38ScryptP = 8
+)
+
+const (
+	bip38Prefix           = 0x42 // second byte of the 0x01 0x42 prefix
+	bip38FlagCompressed   = 0xe0
+	bip38FlagUncompressed = 0xc0
+	bip38PayloadLen       = 38 // excluding the 0x01 version byte
+)
+
+var (
+	// ErrInvalidEncryptedKey is returned by DecryptPrivateKey when the string is
+	// not a BIP-38 encrypted private key, or uses EC multiplication, which is not
+	// supported.
+	ErrInvalidEncryptedKey = errors.New("invalid encrypted private key")
+
+	// ErrWrongPassphrase is returned by DecryptPrivateKey when the decrypted key
+	// does not match the address hash stored with it.
+	ErrWrongPassphrase = errors.New("wrong passphrase for encrypted private key")
+)
+
+// EncryptPrivateKey encrypts the private key with the passphrase as BIP-38
+// (non EC-multiplied), returning a printable base58 string starting with "6P",
+// which can be stored at rest and later decrypted with DecryptPrivateKey.
+//
+// The key is derived from the passphrase with scrypt (N=16384, r=8, p=8), salted
+// with a hash of the key's compressed mainnet address, and the private key is
+// encrypted with AES-256. The passphrase should be NFC normalised by the caller
+// if it may contain non-ASCII characters.
+func EncryptPrivateKey(priv *PrivateKey, passphrase string) (string, error) {
+	if priv == nil {
+		return "", ErrInvalidPrivateKey
+	}
+	addrHash := bip38AddressHash(priv.PubKey(), true)
+	derived, err := scrypt.Key([]byte(passphrase), addrHash, bip38ScryptN, bip38ScryptR, bip38ScryptP, 64)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return "", err
+	}
+	key := priv.Serialise()
+	for i := range key {
+		key[i] ^= derived[i]
+	}
+	encrypted := make([]byte, 32)
+	block.Encrypt(encrypted[:16], key[:16])
+	block.Encrypt(encrypted[16:], key[16:])
+
+	payload := make([]byte, 0, bip38PayloadLen)
+	payload = append(payload, bip38Prefix, bip38FlagCompressed)
+	payload = append(payload, addrHash...)
+	payload = append(payload, encrypted...)
+
+	return base58.CheckEncode(payload, 0x01), nil
+}
+
+// DecryptPrivateKey decrypts a BIP-38 encrypted private key, as returned by
+// EncryptPrivateKey or other wallets, with the passphrase.
+//
+// An ErrInvalidEncryptedKey error is returned if the string is not a valid
+// non EC-multiplied BIP-38 key, and ErrWrongPassphrase if the decrypted key does
+// not hash to the address stored with it, as when the passphrase is wrong.
+func DecryptPrivateKey(encrypted, passphrase string) (*PrivateKey, error) {
+	payload, version, err := base58.CheckDecode(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncryptedKey, err)
+	}
+	if version != 0x01 || len(payload) != bip38PayloadLen || payload[0] != bip38Prefix {
+		return nil, ErrInvalidEncryptedKey
+	}
+	var compressed bool
+	switch payload[1] {
+	case bip38FlagCompressed:
+		compressed = true
+	case bip38FlagUncompressed:
+	default:
+		return nil, fmt.Errorf("%w: unsupported flag %#02x", ErrInvalidEncryptedKey, payload[1])
+	}
+	addrHash := payload[2:6]
+
+	derived, err := scrypt.Key([]byte(passphrase), addrHash, bip38ScryptN, bip38ScryptR, bip38ScryptP, 64)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, 32)
+	block.Decrypt(key[:16], payload[6:22])
+	block.Decrypt(key[16:], payload[22:38])
+	for i := range key {
+		key[i] ^= derived[i]
+	}
+
+	priv, err := ParsePrivateKey(key)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	if !bytes.Equal(bip38AddressHash(priv.PubKey(), compressed), addrHash) {
+		return nil, ErrWrongPassphrase
+	}
+
+	return priv, nil
+}
+
+// bip38AddressHash returns the first four bytes of the double SHA-256 of the
+// mainnet P2PKH address of the key, as a string.
+func bip38AddressHash(pub *PublicKey, compressed bool) []byte {
+	serialised := pub.SerialiseUncompressed()
+	if compressed {
+		serialised = pub.SerialiseCompressed()
+	}
+	addr := base58.CheckEncode(crypto.Hash160(serialised), 0x00)
+	return crypto.Sha256d([]byte(addr))[:4]
+}
@@ -0,0 +1,72 @@
+package ec
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBIP38(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors from BIP-38, no EC multiplication.
+	tests := map[string]struct {
+		encrypted  string
+		passphrase string
+		keyHex     string
+	}{
+		"uncompressed": {
+			encrypted:  "6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGg",
+			passphrase: "TestingOneTwoThree",
+			keyHex:     "cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+		},
+		"compressed": {
+			encrypted:  "6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo",
+			passphrase: "TestingOneTwoThree",
+			keyHex:     "cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			priv, err := DecryptPrivateKey(test.encrypted, test.passphrase)
+			assert.NoError(t, err)
+			assert.Equal(t, test.keyHex, hex.EncodeToString(priv.Serialise()))
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		priv, err := NewPrivateKey()
+		assert.NoError(t, err)
+
+		encrypted, err := EncryptPrivateKey(priv, "correct horse battery staple")
+		assert.NoError(t, err)
+		assert.Equal(t, "6P", encrypted[:2])
+
+		decrypted, err := DecryptPrivateKey(encrypted, "correct horse battery staple")
+		assert.NoError(t, err)
+		assert.Equal(t, priv.Serialise(), decrypted.Serialise())
+
+		_, err = DecryptPrivateKey(encrypted, "wrong horse battery staple")
+		assert.ErrorIs(t, err, ErrWrongPassphrase)
+	})
+
+	t.Run("matches the compressed vector", func(t *testing.T) {
+		b, err := hex.DecodeString(tests["compressed"].keyHex)
+		assert.NoError(t, err)
+		priv, err := ParsePrivateKey(b)
+		assert.NoError(t, err)
+		encrypted, err := EncryptPrivateKey(priv, tests["compressed"].passphrase)
+		assert.NoError(t, err)
+		assert.Equal(t, tests["compressed"].encrypted, encrypted)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := DecryptPrivateKey("5KN7MzqK5wt2TP1fQCYyHBtDrXdJuXbUzm4A9rKAteGu3Qi5CVR", "TestingOneTwoThree")
+		assert.ErrorIs(t, err, ErrInvalidEncryptedKey)
+		_, err = DecryptPrivateKey("not base58!", "TestingOneTwoThree")
+		assert.ErrorIs(t, err, ErrInvalidEncryptedKey)
+		_, err = EncryptPrivateKey(nil, "TestingOneTwoThree")
+		assert.ErrorIs(t, err, ErrInvalidPrivateKey)
+	})
+}
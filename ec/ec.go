@@ -972,6 +972,10 @@ func initS256() {
 }
 
 // S256 returns a Curve which implements secp256k1.
+//
+// The curve, including the pre-computed table used for scalar base
+// multiplication, is initialised once on first use and shared by every caller.
+// It is never modified afterwards, so is safe for concurrent use.
 func S256() *KoblitzCurve {
 	initonce.Do(initAll)
 	return &secp256k1
@@ -4,6 +4,7 @@ import (
 	e "crypto/ecdsa"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -30,6 +31,28 @@ func PrivateKeyFromBytes(pk []byte) (*PrivateKey, *PublicKey) {
 	return (*PrivateKey)(priv), (*PublicKey)(&priv.PublicKey)
 }
 
+// ErrInvalidPrivateKey is returned by ParsePrivateKey when the key is not
+// 32 bytes or is not a valid scalar, that is zero or not less than the curve order.
+var ErrInvalidPrivateKey = errors.New("invalid private key")
+
+// ParsePrivateKey validates a raw 32 byte big-endian private key, as stored by
+// systems that keep the network separately rather than using WIF, and returns
+// the private key. Unlike PrivateKeyFromBytes, keys of the wrong length and
+// scalars of zero or not less than the curve order are rejected with
+// ErrInvalidPrivateKey.
+func ParsePrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != PrivateKeyBytesLen {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidPrivateKey, PrivateKeyBytesLen, len(b))
+	}
+	d := new(big.Int).SetBytes(b)
+	if d.Sign() == 0 || d.Cmp(S256().N) >= 0 {
+		return nil, fmt.Errorf("%w: scalar out of range", ErrInvalidPrivateKey)
+	}
+
+	priv, _ := PrivateKeyFromBytes(b)
+	return priv, nil
+}
+
 // NewPrivateKey is a wrapper for ecdsa.GenerateKey that returns a PrivateKey
 // instead of the normal ecdsa.PrivateKey.
 func NewPrivateKey() (*PrivateKey, error) {
@@ -56,10 +79,104 @@ func (p *PrivateKey) PubKey() *PublicKey {
 // of hashing a larger message) using the private key. Produced signature
 // is deterministic (same message and same key yield the same signature) and canonical
 // in accordance with RFC6979 and BIP0062.
+//
+// An ErrZeroPrivateKey error is returned if the key has been wiped with Zero.
 func (p *PrivateKey) Sign(hash []byte) (*Signature, error) {
+	if p.D == nil || p.D.Sign() == 0 {
+		return nil, ErrZeroPrivateKey
+	}
 	return signRFC6979(p, hash)
 }
 
+// SignLowR signs the hash as Sign, but grinds the nonce until the signature's R
+// value has its high bit clear. R then DER encodes in 32 bytes rather than 33,
+// making the signature a byte shorter, 71 bytes rather than 72 with the sighash
+// byte, which saves fees when signing many inputs.
+//
+// The signature is still deterministic. Half of all nonces give a low R, so on
+// average it costs twice as much as Sign.
+//
+// An ErrZeroPrivateKey error is returned if the key has been wiped with Zero.
+func (p *PrivateKey) SignLowR(hash []byte) (*Signature, error) {
+	if p.D == nil || p.D.Sign() == 0 {
+		return nil, ErrZeroPrivateKey
+	}
+	return signLowR(p, hash)
+}
+
+// ErrZeroPrivateKey is returned when signing with a private key of zero, such
+// as one wiped with Zero.
+var ErrZeroPrivateKey = errors.New("private key is zero")
+
+// Zero overwrites the private key scalar in memory and sets it to zero.
+// After Zero the key is unusable: signing returns ErrZeroPrivateKey and any
+// derivation from it is invalid. The public key is left in place.
+//
+// Go gives no guarantee that no other copies of the key exist in memory, for
+// example made by the garbage collector or by earlier serialisation, so this is
+// a best effort for callers wanting to wipe keys they no longer need. It must be
+// called explicitly; no finalizer is set.
+func (p *PrivateKey) Zero() {
+	if p.D == nil {
+		return
+	}
+	words := p.D.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	p.D.SetInt64(0)
+}
+
+// ErrInvalidTweak is returned when a key tweak is out of range or would
+// produce an invalid key (a zero private key or the point at infinity).
+var ErrInvalidTweak = errors.New("invalid key tweak")
+
+// parseTweak interprets tweak as a big-endian scalar, rejecting values
+// that are not less than the curve order.
+func parseTweak(tweak []byte) (*big.Int, error) {
+	t := new(big.Int).SetBytes(tweak)
+	if t.Cmp(S256().N) >= 0 {
+		return nil, fmt.Errorf("%w: tweak is not less than the curve order", ErrInvalidTweak)
+	}
+	return t, nil
+}
+
+// Add returns a new private key of (d + tweak) mod n, where tweak is a
+// big-endian scalar. The public key of the result is equal to the result of
+// adding the same tweak to the public key with PublicKey.Add.
+func (p *PrivateKey) Add(tweak []byte) (*PrivateKey, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+
+	d := new(big.Int).Add(p.D, t)
+	d.Mod(d, S256().N)
+	if d.Sign() == 0 {
+		return nil, fmt.Errorf("%w: tweaked private key is zero", ErrInvalidTweak)
+	}
+	priv, _ := PrivateKeyFromBytes(d.Bytes())
+	return priv, nil
+}
+
+// MulTweak returns a new private key of (d * tweak) mod n, where tweak is a
+// non-zero big-endian scalar. The public key of the result is equal to the
+// result of multiplying the public key by the same tweak with PublicKey.MulTweak.
+func (p *PrivateKey) MulTweak(tweak []byte) (*PrivateKey, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	if t.Sign() == 0 {
+		return nil, fmt.Errorf("%w: tweak is zero", ErrInvalidTweak)
+	}
+
+	d := new(big.Int).Mul(p.D, t)
+	d.Mod(d, S256().N)
+	priv, _ := PrivateKeyFromBytes(d.Bytes())
+	return priv, nil
+}
+
 // PrivateKeyBytesLen defines the length in bytes of a serialised private key.
 const PrivateKeyBytesLen = 32
 
@@ -70,11 +187,51 @@ func (p *PrivateKey) Serialise() []byte {
 	return paddedAppend(PrivateKeyBytesLen, b, p.D.Bytes())
 }
 
+// DeriveSharedSecret computes the ECDH shared point of this private key and
+// the given public key (d * P). As with SharedSecret, the multiplication is
+// constant time.
 func (p *PrivateKey) DeriveSharedSecret(key *PublicKey) (*PublicKey, error) {
-	if !key.Validate() {
-		return nil, fmt.Errorf("public key is not on the curve")
+	return sharedSecretPoint(p, key)
+}
+
+// SharedSecret computes the ECDH shared secret of this private key and the
+// given public key. By default the 32-byte x-coordinate of the shared point is
+// returned, as per RFC5903 Section 9. If fullPoint is true the shared point is
+// returned in the 33-byte compressed format instead, as used by BRC-42.
+//
+// The result is symmetric: Alice's secret with Bob's public key equals Bob's
+// secret with Alice's public key.
+//
+// The multiplication runs in constant time, so its timing does not leak the
+// private key.
+func (p *PrivateKey) SharedSecret(key *PublicKey, fullPoint bool) ([]byte, error) {
+	point, err := sharedSecretPoint(p, key)
+	if err != nil {
+		return nil, err
+	}
+	if fullPoint {
+		return point.SerialiseCompressed(), nil
+	}
+	return paddedAppend(32, make([]byte, 0, 32), point.X.Bytes()), nil
+}
+
+// sharedSecretPoint multiplies pub by the scalar of priv, rejecting public
+// keys which are not on the curve and results at the point at infinity.
+func sharedSecretPoint(priv *PrivateKey, pub *PublicKey) (*PublicKey, error) {
+	if pub == nil || pub.X == nil || pub.Y == nil || !S256().IsOnCurve(pub.X, pub.Y) {
+		return nil, errors.New("public key not valid for secret derivation")
+	}
+	if priv == nil || priv.D == nil || priv.D.Sign() == 0 {
+		return nil, errors.New("private key not valid for secret derivation")
+	}
+
+	var k [32]byte
+	priv.D.FillBytes(k[:])
+	x, y := S256().scalarMultConstantTime(pub.X, pub.Y, &k)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("shared secret is the point at infinity")
 	}
-	return key.Mul(p.D), nil
+	return &PublicKey{Curve: S256(), X: x, Y: y}, nil
 }
 
 // Derives a child key with BRC-42
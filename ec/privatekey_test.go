@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"math/big"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -103,3 +105,126 @@ func TestBRC42PrivateVectors(t *testing.T) {
 		})
 	}
 }
+
+func TestPrivateKeyTweak(t *testing.T) {
+	priv, pub := PrivateKeyFromBytes(bytes.Repeat([]byte{0x11}, 32))
+	tweak := bytes.Repeat([]byte{0x07}, 32)
+
+	addPriv, err := priv.Add(tweak)
+	if err != nil {
+		t.Fatalf("private add: %v", err)
+	}
+	addPub, err := pub.Add(tweak)
+	if err != nil {
+		t.Fatalf("public add: %v", err)
+	}
+	if !addPriv.PubKey().IsEqual(addPub) {
+		t.Error("tweaked public key does not match public key of tweaked private key")
+	}
+
+	mulPriv, err := priv.MulTweak(tweak)
+	if err != nil {
+		t.Fatalf("private mul: %v", err)
+	}
+	mulPub, err := pub.MulTweak(tweak)
+	if err != nil {
+		t.Fatalf("public mul: %v", err)
+	}
+	if !mulPriv.PubKey().IsEqual(mulPub) {
+		t.Error("multiplied public key does not match public key of multiplied private key")
+	}
+
+	if _, err = priv.MulTweak([]byte{0}); !errors.Is(err, ErrInvalidTweak) {
+		t.Errorf("expected ErrInvalidTweak for zero tweak, got %v", err)
+	}
+	if _, err = priv.Add(S256().N.Bytes()); !errors.Is(err, ErrInvalidTweak) {
+		t.Errorf("expected ErrInvalidTweak for tweak >= n, got %v", err)
+	}
+
+	negated := new(big.Int).Sub(S256().N, priv.D).Bytes()
+	if _, err = priv.Add(negated); !errors.Is(err, ErrInvalidTweak) {
+		t.Errorf("expected ErrInvalidTweak for zero private key, got %v", err)
+	}
+	if _, err = pub.Add(negated); !errors.Is(err, ErrInvalidTweak) {
+		t.Errorf("expected ErrInvalidTweak for point at infinity, got %v", err)
+	}
+}
+
+func TestPrivateKeySharedSecret(t *testing.T) {
+	alice, alicePub := PrivateKeyFromBytes(bytes.Repeat([]byte{0x01}, 32))
+	bob, bobPub := PrivateKeyFromBytes(bytes.Repeat([]byte{0x02}, 32))
+
+	for _, fullPoint := range []bool{false, true} {
+		aliceSecret, err := alice.SharedSecret(bobPub, fullPoint)
+		if err != nil {
+			t.Fatalf("alice shared secret: %v", err)
+		}
+		bobSecret, err := bob.SharedSecret(alicePub, fullPoint)
+		if err != nil {
+			t.Fatalf("bob shared secret: %v", err)
+		}
+		if !bytes.Equal(aliceSecret, bobSecret) {
+			t.Errorf("shared secrets differ (fullPoint=%v): %x != %x", fullPoint, aliceSecret, bobSecret)
+		}
+
+		expLen := 32
+		if fullPoint {
+			expLen = PubKeyBytesLenCompressed
+		}
+		if len(aliceSecret) != expLen {
+			t.Errorf("expected %d byte secret, got %d", expLen, len(aliceSecret))
+		}
+	}
+
+	infinity := &PublicKey{Curve: S256(), X: new(big.Int), Y: new(big.Int)}
+	if _, err := alice.SharedSecret(infinity, false); err == nil {
+		t.Error("expected point at infinity to be rejected")
+	}
+}
+
+func TestPrivateKeyZero(t *testing.T) {
+	priv, _ := PrivateKeyFromBytes(bytes.Repeat([]byte{0x11}, 32))
+	words := priv.D.Bits()
+
+	priv.Zero()
+	if priv.D.Sign() != 0 {
+		t.Fatal("private key scalar not zero")
+	}
+	for i, w := range words {
+		if w != 0 {
+			t.Fatalf("word %d of the scalar was not overwritten", i)
+		}
+	}
+	if _, err := priv.Sign(bytes.Repeat([]byte{0x01}, 32)); !errors.Is(err, ErrZeroPrivateKey) {
+		t.Fatalf("expected ErrZeroPrivateKey signing with zeroed key, got %v", err)
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	raw := bytes.Repeat([]byte{0x11}, 32)
+	priv, err := ParsePrivateKey(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv.Serialise(), raw) {
+		t.Fatalf("unexpected key %x", priv.Serialise())
+	}
+	expPriv, expPub := PrivateKeyFromBytes(raw)
+	if priv.D.Cmp(expPriv.D) != 0 || !priv.PubKey().IsEqual(expPub) {
+		t.Fatal("parsed key does not match PrivateKeyFromBytes")
+	}
+
+	invalid := map[string][]byte{
+		"empty":       nil,
+		"short":       raw[:31],
+		"long":        append(raw, 0x00),
+		"zero":        make([]byte, 32),
+		"curve order": S256().N.Bytes(),
+		"max":         bytes.Repeat([]byte{0xff}, 32),
+	}
+	for name, b := range invalid {
+		if _, err := ParsePrivateKey(b); !errors.Is(err, ErrInvalidPrivateKey) {
+			t.Errorf("%s: expected ErrInvalidPrivateKey, got %v", name, err)
+		}
+	}
+}
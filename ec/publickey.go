@@ -137,6 +137,37 @@ func ParsePubKey(pubKeyStr []byte) (key *PublicKey, err error) {
 // serialise in uncompressed, compressed, and hybrid formats.
 type PublicKey ecdsa.PublicKey
 
+// PublicKeyFromBytes parses a public key serialised in either the 33-byte
+// compressed or the 65-byte uncompressed format, verifying that the resulting
+// point is on the secp256k1 curve. Hybrid encoded keys are rejected.
+func PublicKeyFromBytes(b []byte) (*PublicKey, error) {
+	switch {
+	case len(b) == PubKeyBytesLenCompressed && b[0]&^byte(0x1) == pubkeyCompressed:
+	case len(b) == PubKeyBytesLenUncompressed && b[0] == pubkeyUncompressed:
+	default:
+		return nil, fmt.Errorf("public key must be compressed or uncompressed, got %d bytes", len(b))
+	}
+
+	pubKey, err := ParsePubKey(b)
+	if err != nil {
+		return nil, err
+	}
+	if !pubKey.Validate() {
+		return nil, errors.New("pubkey isn't on secp256k1 curve")
+	}
+	return pubKey, nil
+}
+
+// Compressed returns the public key in the 33-byte compressed format.
+func (p *PublicKey) Compressed() []byte {
+	return p.SerialiseCompressed()
+}
+
+// Uncompressed returns the public key in the 65-byte uncompressed format.
+func (p *PublicKey) Uncompressed() []byte {
+	return p.SerialiseUncompressed()
+}
+
 // ToECDSA returns the public key as a *ecdsa.PublicKey.
 func (p *PublicKey) ToECDSA() *ecdsa.PublicKey {
 	return (*ecdsa.PublicKey)(p)
@@ -220,6 +251,37 @@ func (p *PublicKey) Mul(k *big.Int) *PublicKey {
 	}
 }
 
+// Add returns a new public key of P + tweak*G, where tweak is a big-endian
+// scalar. This is the public counterpart of PrivateKey.Add.
+func (p *PublicKey) Add(tweak []byte) (*PublicKey, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, ty := S256().ScalarBaseMult(t.Bytes())
+	x, y := S256().Add(p.X, p.Y, tx, ty)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, fmt.Errorf("%w: tweaked public key is the point at infinity", ErrInvalidTweak)
+	}
+	return &PublicKey{Curve: S256(), X: x, Y: y}, nil
+}
+
+// MulTweak returns a new public key of tweak*P, where tweak is a non-zero
+// big-endian scalar. This is the public counterpart of PrivateKey.MulTweak.
+func (p *PublicKey) MulTweak(tweak []byte) (*PublicKey, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	if t.Sign() == 0 {
+		return nil, fmt.Errorf("%w: tweak is zero", ErrInvalidTweak)
+	}
+
+	x, y := S256().ScalarMult(p.X, p.Y, t.Bytes())
+	return &PublicKey{Curve: S256(), X: x, Y: y}, nil
+}
+
 func (p *PublicKey) encode(compact bool) []byte {
 	byteLen := (p.Curve.Params().BitSize + 7) >> 3
 
@@ -269,14 +331,10 @@ func (p *PublicKey) DeriveChild(privateKey *PrivateKey, invoiceNumber string) (*
 	}, nil
 }
 
-// TODO: refactor to have 1 function for both private and public key
-// call it multiply point with scalar or something and pass in private key
-// and public key
+// DeriveSharedSecret computes the ECDH shared point of this public key and
+// the given private key (d * P).
 func (p *PublicKey) DeriveSharedSecret(priv *PrivateKey) (*PublicKey, error) {
-	if !p.IsOnCurve(p.X, p.Y) {
-		return nil, errors.New("public key not valid for secret derivation")
-	}
-	return p.Mul(priv.D), nil
+	return sharedSecretPoint(priv, p)
 }
 
 // Verify a signature of a message using this public key.
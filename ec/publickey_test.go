@@ -348,3 +348,33 @@ func TestBRC42PublicVectors(t *testing.T) {
 		})
 	}
 }
+
+func TestPublicKeyFromBytes(t *testing.T) {
+	_, pub := PrivateKeyFromBytes(bytes.Repeat([]byte{0x2a}, 32))
+
+	for _, b := range [][]byte{pub.Compressed(), pub.Uncompressed()} {
+		parsed, err := PublicKeyFromBytes(b)
+		if err != nil {
+			t.Fatalf("failed to parse %x: %v", b, err)
+		}
+		if !parsed.IsEqual(pub) {
+			t.Errorf("parsed key %x does not match original", b)
+		}
+		if !bytes.Equal(parsed.Compressed(), pub.Compressed()) {
+			t.Errorf("compressed form mismatch for %x", b)
+		}
+		if !bytes.Equal(parsed.Uncompressed(), pub.Uncompressed()) {
+			t.Errorf("uncompressed form mismatch for %x", b)
+		}
+	}
+
+	if _, err := PublicKeyFromBytes(pub.SerialiseHybrid()); err == nil {
+		t.Error("expected hybrid public key to be rejected")
+	}
+
+	offCurve := pub.Uncompressed()
+	offCurve[64] ^= 0x01
+	if _, err := PublicKeyFromBytes(offCurve); err == nil {
+		t.Error("expected off-curve public key to be rejected")
+	}
+}
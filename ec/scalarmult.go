@@ -0,0 +1,138 @@
+package ec
+
+import (
+	"crypto/subtle"
+	"math/big"
+)
+
+// curveB3 is 3*b for the secp256k1 curve equation y^2 = x^3 + 7, as used by the
+// complete addition formulas.
+const curveB3 = 21
+
+// projectivePoint is a point in homogeneous projective coordinates, where the
+// affine point is (X/Z, Y/Z) and the point at infinity is (0, 1, 0). All field
+// values are kept normalised.
+type projectivePoint struct {
+	x, y, z fieldVal
+}
+
+// setInfinity sets p to the point at infinity.
+func (p *projectivePoint) setInfinity() *projectivePoint {
+	p.x.Zero()
+	p.y.SetInt(1)
+	p.z.Zero()
+	return p
+}
+
+// condAssign sets p to q if mask is all ones and leaves it unchanged if mask is
+// zero, without branching on mask.
+func (p *projectivePoint) condAssign(q *projectivePoint, mask uint32) {
+	p.x.condAssign(&q.x, mask)
+	p.y.condAssign(&q.y, mask)
+	p.z.condAssign(&q.z, mask)
+}
+
+// condAssign sets f to val if mask is all ones and leaves it unchanged if mask
+// is zero, without branching on mask.
+func (f *fieldVal) condAssign(val *fieldVal, mask uint32) {
+	for i := range f.n {
+		f.n[i] ^= mask & (f.n[i] ^ val.n[i])
+	}
+}
+
+// fieldSub sets f to a-b, where a and b are normalised, and normalises it.
+func fieldSub(f, a, b *fieldVal) {
+	var negB fieldVal
+	negB.NegateVal(b, 1)
+	f.Add2(a, &negB).Normalise()
+}
+
+// addProjective sets r to p+q using the complete addition formulas for prime
+// order short Weierstrass curves with a = 0, algorithm 7 of "Complete addition
+// formulas for prime order elliptic curves" by Renes, Costello and Batina:
+//
+//	https://eprint.iacr.org/2015/1060
+//
+// The formulas have no exceptional cases, so doubling and the point at infinity
+// take the same path as any other addition and no branches depend on the points.
+// r may alias p or q.
+func addProjective(r, p, q *projectivePoint) {
+	var t0, t1, t2, t3, t4, x3, y3, z3 fieldVal
+	t0.Mul2(&p.x, &q.x)            // t0 = X1*X2
+	t1.Mul2(&p.y, &q.y)            // t1 = Y1*Y2
+	t2.Mul2(&p.z, &q.z)            // t2 = Z1*Z2
+	t3.Add2(&p.x, &p.y)            // t3 = X1+Y1
+	t4.Add2(&q.x, &q.y)            // t4 = X2+Y2
+	t3.Mul(&t4)                    // t3 = t3*t4
+	t4.Add2(&t0, &t1).Normalise()  // t4 = t0+t1
+	fieldSub(&t3, &t3, &t4)        // t3 = t3-t4
+	t4.Add2(&p.y, &p.z)            // t4 = Y1+Z1
+	x3.Add2(&q.y, &q.z)            // X3 = Y2+Z2
+	t4.Mul(&x3)                    // t4 = t4*X3
+	x3.Add2(&t1, &t2).Normalise()  // X3 = t1+t2
+	fieldSub(&t4, &t4, &x3)        // t4 = t4-X3
+	x3.Add2(&p.x, &p.z)            // X3 = X1+Z1
+	y3.Add2(&q.x, &q.z)            // Y3 = X2+Z2
+	x3.Mul(&y3)                    // X3 = X3*Y3
+	y3.Add2(&t0, &t2).Normalise()  // Y3 = t0+t2
+	fieldSub(&y3, &x3, &y3)        // Y3 = X3-Y3
+	x3.Add2(&t0, &t0)              // X3 = t0+t0
+	t0.Add(&x3).Normalise()        // t0 = X3+t0
+	t2.MulInt(curveB3).Normalise() // t2 = b3*t2
+	z3.Add2(&t1, &t2).Normalise()  // Z3 = t1+t2
+	fieldSub(&t1, &t1, &t2)        // t1 = t1-t2
+	y3.MulInt(curveB3).Normalise() // Y3 = b3*Y3
+	x3.Mul2(&t4, &y3)              // X3 = t4*Y3
+	t2.Mul2(&t3, &t1)              // t2 = t3*t1
+	fieldSub(&x3, &t2, &x3)        // X3 = t2-X3
+	y3.Mul(&t0)                    // Y3 = Y3*t0
+	t1.Mul(&z3)                    // t1 = t1*Z3
+	y3.Add(&t1).Normalise()        // Y3 = t1+Y3
+	t0.Mul(&t3)                    // t0 = t0*t3
+	z3.Mul(&t4)                    // Z3 = Z3*t4
+	z3.Add(&t0).Normalise()        // Z3 = Z3+t0
+	r.x, r.y, r.z = x3, y3, z3
+}
+
+// scalarMultConstantTime returns k*(Bx, By) where k is a 32-byte big endian
+// integer. Unlike ScalarMult its running time and memory access pattern do not
+// depend on k, so it is used where k is secret, such as for ECDH.
+//
+// It uses a fixed 4-bit window: the multiples 0*P to 15*P are precomputed, then
+// for each of the 64 windows of k the accumulator is doubled four times and the
+// multiple for the window, read with a scan of the whole table, is added.
+// (0, 0) is returned for the point at infinity.
+func (curve *KoblitzCurve) scalarMultConstantTime(Bx, By *big.Int, k *[32]byte) (*big.Int, *big.Int) {
+	var table [16]projectivePoint
+	table[0].setInfinity()
+	bx, by := curve.bigAffineToField(Bx, By)
+	table[1].x.Set(bx).Normalise()
+	table[1].y.Set(by).Normalise()
+	table[1].z.SetInt(1)
+	for i := 2; i < len(table); i++ {
+		addProjective(&table[i], &table[i-1], &table[1])
+	}
+
+	var q, sel projectivePoint
+	q.setInfinity()
+	for _, b := range k {
+		for _, window := range [2]byte{b >> 4, b & 0x0f} {
+			for i := 0; i < 4; i++ {
+				addProjective(&q, &q, &q)
+			}
+			sel.setInfinity()
+			for i := range table {
+				mask := -uint32(subtle.ConstantTimeByteEq(byte(i), window))
+				sel.condAssign(&table[i], mask)
+			}
+			addProjective(&q, &q, &sel)
+		}
+	}
+
+	// (X/Z, Y/Z), where the inverse of zero is zero so the point at infinity
+	// becomes (0, 0).
+	zInv := new(fieldVal).Set(&q.z).Inverse()
+	x := new(fieldVal).Mul2(&q.x, zInv).Normalise()
+	y := new(fieldVal).Mul2(&q.y, zInv).Normalise()
+	return new(big.Int).SetBytes(x.Bytes()[:]), new(big.Int).SetBytes(y.Bytes()[:])
+}
@@ -0,0 +1,49 @@
+package ec
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestScalarMultConstantTime(t *testing.T) {
+	curve := S256()
+	_, pub := PrivateKeyFromBytes(bytes.Repeat([]byte{0x07}, 32))
+	nMinus1 := new(big.Int).Sub(curve.N, big.NewInt(1))
+
+	tests := map[string]*big.Int{
+		"one":         big.NewInt(1),
+		"two":         big.NewInt(2),
+		"last window": big.NewInt(15),
+		"two windows": big.NewInt(16),
+		"n-1":         nMinus1,
+		"high bits":   new(big.Int).Lsh(big.NewInt(0xf), 252),
+		"mixed":       new(big.Int).SetBytes(bytes.Repeat([]byte{0xa5, 0x00, 0x3c}, 10)),
+	}
+	points := map[string][2]*big.Int{
+		"generator":  {curve.Gx, curve.Gy},
+		"public key": {pub.X, pub.Y},
+	}
+	for name, k := range tests {
+		for pname, p := range points {
+			t.Run(name+" "+pname, func(t *testing.T) {
+				var kb [32]byte
+				k.FillBytes(kb[:])
+				x, y := curve.scalarMultConstantTime(p[0], p[1], &kb)
+				expX, expY := curve.ScalarMult(p[0], p[1], kb[:])
+				if x.Cmp(expX) != 0 || y.Cmp(expY) != 0 {
+					t.Errorf("got (%x, %x), expected (%x, %x)", x, y, expX, expY)
+				}
+			})
+		}
+	}
+
+	t.Run("order gives the point at infinity", func(t *testing.T) {
+		var kb [32]byte
+		curve.N.FillBytes(kb[:])
+		x, y := curve.scalarMultConstantTime(curve.Gx, curve.Gy, &kb)
+		if x.Sign() != 0 || y.Sign() != 0 {
+			t.Errorf("expected (0, 0), got (%x, %x)", x, y)
+		}
+	})
+}
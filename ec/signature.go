@@ -7,6 +7,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/asn1"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -96,7 +97,7 @@ func (sig *Signature) Serialise() []byte {
 // Verify calls ecdsa.Verify to verify the signature of hash using the public
 // key.  It returns true if the signature is valid, false otherwise.
 func (sig *Signature) Verify(hash []byte, pubKey *PublicKey) bool {
-	return e.Verify(pubKey.ToECDSA(), hash, sig.R, sig.S)
+	return activeBackend.verify(sig, hash, pubKey)
 }
 
 // IsEqual compares this Signature instance to the one passed, returning true
@@ -462,20 +463,58 @@ func RecoverCompact(signature,
 }
 
 // signRFC6979 generates a deterministic ECDSA signature according to RFC 6979 and BIP 62.
+//
+// The shared package level curve is always used, rather than privkey.Curve, so
+// that k*G is computed from the pre-computed byte points table even when the
+// key was built with a different elliptic.Curve value for secp256k1.
 func signRFC6979(privkey *PrivateKey, hash []byte) (*Signature, error) {
+	return activeBackend.sign(privkey, hash, nil)
+}
+
+// lowRMaxAttempts bounds the grinding of signLowR. Each attempt has a one in two
+// chance of a low R, so it is only reached if something is badly wrong.
+const lowRMaxAttempts = 256
+
+// signLowR generates a deterministic signature as signRFC6979, grinding the nonce
+// until R has its high bit clear, so R is encoded in 32 bytes rather than 33 in
+// DER. As Bitcoin Core does, the first attempt is plain RFC 6979, and each later
+// attempt n passes n as 32 little endian bytes of additional data to RFC 6979.
+func signLowR(privkey *PrivateKey, hash []byte) (*Signature, error) {
+	extra := make([]byte, 32)
+	for attempt := uint32(0); attempt < lowRMaxAttempts; attempt++ {
+		var sig *Signature
+		var err error
+		if attempt == 0 {
+			sig, err = activeBackend.sign(privkey, hash, nil)
+		} else {
+			binary.LittleEndian.PutUint32(extra, attempt)
+			sig, err = activeBackend.sign(privkey, hash, extra)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sig.R.BitLen() < 256 {
+			return sig, nil
+		}
+	}
+	return nil, errors.New("failed to grind a low R signature")
+}
 
-	N := S256().N
-	halfOrder := S256().halfOrder
-	k := nonceRFC6979(privkey.D, hash)
+// signWithNonce generates an ECDSA signature with the nonce k, normalised to low S
+// according to BIP 62.
+func signWithNonce(privkey *PrivateKey, hash []byte, k *big.Int) (*Signature, error) {
+	curve := S256()
+	N := curve.N
+	halfOrder := curve.halfOrder
 	inv := new(big.Int).ModInverse(k, N)
-	r, _ := privkey.Curve.ScalarBaseMult(k.Bytes())
+	r, _ := curve.ScalarBaseMult(k.Bytes())
 	r.Mod(r, N)
 
 	if r.Sign() == 0 {
 		return nil, errors.New("calculated R is zero")
 	}
 
-	e := hashToInt(hash, privkey.Curve)
+	e := hashToInt(hash, curve)
 	s := new(big.Int).Mul(privkey.D, r)
 	s.Add(s, e)
 	s.Mul(s, inv)
@@ -493,7 +532,12 @@ func signRFC6979(privkey *PrivateKey, hash []byte) (*Signature, error) {
 // nonceRFC6979 generates an ECDSA nonce (`k`) deterministically according to RFC 6979.
 // It takes a 32-byte hash as an input and returns 32-byte nonce to be used in ECDSA algorithm.
 func nonceRFC6979(privkey *big.Int, hash []byte) *big.Int {
+	return nonceRFC6979WithExtra(privkey, hash, nil)
+}
 
+// nonceRFC6979WithExtra generates an ECDSA nonce as nonceRFC6979, with the
+// additional data of RFC 6979 section 3.6 appended to the key and hash.
+func nonceRFC6979WithExtra(privkey *big.Int, hash []byte, extra []byte) *big.Int {
 	curve := S256()
 	q := curve.Params().N
 	x := privkey
@@ -503,6 +547,7 @@ func nonceRFC6979(privkey *big.Int, hash []byte) *big.Int {
 	holen := alg().Size()
 	rolen := (qlen + 7) >> 3
 	bx := append(int2octets(x, rolen), bits2octets(hash, rolen)...)
+	bx = append(bx, extra...)
 
 	// Step B
 	v := bytes.Repeat(oneInitializer, holen)
@@ -707,6 +707,53 @@ func TestRFC6979(t *testing.T) {
 	}
 }
 
+// TestSignLowR ensures SignLowR always produces valid, deterministic signatures
+// with a 32 byte R, and matches Sign when Sign already gives a low R.
+func TestSignLowR(t *testing.T) {
+	privKey, _ := PrivateKeyFromBytes(decodeHex("fad9c8855b740a0b7ed4c221dbad0f33a83a49cad6b3fe8d5817ac83d38b6a19"))
+
+	var ground int
+	for i := 0; i < 64; i++ {
+		hash := crypto.Sha256([]byte{byte(i)})
+
+		sig, err := privKey.SignLowR(hash)
+		if err != nil {
+			t.Fatalf("hash %d: unexpected error: %v", i, err)
+		}
+		if sig.R.BitLen() >= 256 {
+			t.Errorf("hash %d: R has its high bit set", i)
+		}
+		if !sig.Verify(hash, privKey.PubKey()) {
+			t.Errorf("hash %d: signature does not verify", i)
+		}
+		if l := len(sig.Serialise()); l > 70 {
+			t.Errorf("hash %d: DER signature is %d bytes, want at most 70", i, l)
+		}
+
+		again, _ := privKey.SignLowR(hash)
+		if !sig.IsEqual(again) {
+			t.Errorf("hash %d: signature is not deterministic", i)
+		}
+
+		plain, _ := privKey.Sign(hash)
+		if plain.R.BitLen() < 256 {
+			if !sig.IsEqual(plain) {
+				t.Errorf("hash %d: low R signature differs from Sign", i)
+			}
+			continue
+		}
+		ground++
+	}
+	if ground == 0 {
+		t.Error("no signature needed grinding, the test is not exercising it")
+	}
+
+	privKey.Zero()
+	if _, err := privKey.SignLowR(crypto.Sha256([]byte{0})); err != ErrZeroPrivateKey {
+		t.Errorf("expected ErrZeroPrivateKey, got %v", err)
+	}
+}
+
 func TestSignatureIsEqual(t *testing.T) {
 	sig1 := &Signature{
 		R: fromHex("0082235e21a2300022738dabb8e1bbd9d19cfb1e7ab8c30a23b0afbb8d178abcf3"),
@@ -727,3 +774,46 @@ func TestSignatureIsEqual(t *testing.T) {
 			"equal to %v", sig1, sig2)
 	}
 }
+
+// BenchmarkSign signs 10,000 distinct hashes per iteration with the same key.
+func BenchmarkSign(b *testing.B) {
+	privKey, err := NewPrivateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	hashes := make([][]byte, 10000)
+	for i := range hashes {
+		h := sha256.Sum256([]byte(fmt.Sprintf("hash %d", i)))
+		hashes[i] = h[:]
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, h := range hashes {
+			if _, err := privKey.Sign(h); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkSignParallel signs from many goroutines sharing the curve.
+func BenchmarkSignParallel(b *testing.B) {
+	privKey, err := NewPrivateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	hash := sha256.Sum256([]byte("hash"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := privKey.Sign(hash[:]); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
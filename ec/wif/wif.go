@@ -5,7 +5,7 @@
 package wif
 
 import (
-	"bytes"
+	"crypto/subtle"
 	"errors"
 
 	"github.com/bitcoin-sv/go-sdk/base58"
@@ -62,6 +62,17 @@ func NewWIF(privKey *ec.PrivateKey, net *chaincfg.Params, compress bool) (*WIF,
 	return &WIF{privKey, compress, net.PrivateKeyID}, nil
 }
 
+// NewWIFFromBytes creates a new WIF structure from a raw 32 byte private key,
+// validated with ec.ParsePrivateKey, for the network given. The compress
+// argument is as for NewWIF.
+func NewWIFFromBytes(raw []byte, net *chaincfg.Params, compress bool) (*WIF, error) {
+	privKey, err := ec.ParsePrivateKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	return NewWIF(privKey, net, compress)
+}
+
 // IsForNet returns whether or not the decoded WIF structure is associated
 // with the passed bitcoin network.
 func (w *WIF) IsForNet(net *chaincfg.Params) bool {
@@ -116,14 +127,22 @@ func DecodeWIF(wif string) (*WIF, error) {
 	} else {
 		tosum = decoded[:1+ec.PrivateKeyBytesLen]
 	}
+	// The comparison is constant time so as not to leak, through timing,
+	// how much of a candidate checksum matched.
 	cksum := crypto.Sha256d(tosum)[:4]
-	if !bytes.Equal(cksum, decoded[decodedLen-4:]) {
+	if subtle.ConstantTimeCompare(cksum, decoded[decodedLen-4:]) != 1 {
 		return nil, ErrChecksumMismatch
 	}
 
 	netID := decoded[0]
 	privKeyBytes := decoded[1 : 1+ec.PrivateKeyBytesLen]
 	privKey, _ := ec.PrivateKeyFromBytes(privKeyBytes)
+
+	// The key has been copied, wipe the decoded bytes holding it.
+	for i := range decoded {
+		decoded[i] = 0
+	}
+
 	return &WIF{privKey, compress, netID}, nil
 }
 
@@ -164,6 +183,21 @@ func (w *WIF) SerialisePubKey() []byte {
 	return pk.SerialiseUncompressed()
 }
 
+// Address returns the P2PKH address of the associated public key, hashing the
+// compressed or uncompressed serialisation depending on w.CompressPubKey.  A
+// mainnet address is returned if mainnet is true, otherwise a testnet address.
+func (w *WIF) Address(mainnet bool) (string, error) {
+	if w.PrivKey == nil {
+		return "", ErrMalformedPrivateKey
+	}
+
+	net := &chaincfg.TestNet
+	if mainnet {
+		net = &chaincfg.MainNet
+	}
+	return base58.CheckEncode(crypto.Hash160(w.SerialisePubKey()), net.LegacyPubKeyHashAddrID), nil
+}
+
 // paddedAppend appends the src byte slice to dst, returning the new slice.
 // If the length of the source is smaller than the passed size, leading zero
 // bytes are appended to the dst slice before appending src.
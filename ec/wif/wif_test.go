@@ -5,6 +5,7 @@
 package wif_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/bitcoin-sv/go-sdk/chaincfg"
@@ -69,3 +70,80 @@ func TestEncodeDecodeWIF(t *testing.T) {
 		}
 	}
 }
+
+func TestDecodeWIFChecksumMismatch(t *testing.T) {
+	// Valid WIF with the final checksum character altered.
+	_, err := wif.DecodeWIF("5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTk")
+	if !errors.Is(err, wif.ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+}
+
+func TestWIFAddress(t *testing.T) {
+	tests := []struct {
+		wif     string
+		mainnet bool
+		address string
+	}{
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", true, "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S"},
+		{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", true, "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK"},
+		{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", false, "n1KSZGmQgB8iSZqv6UVhGkCGUbEdw8Lm3Q"},
+	}
+
+	for _, test := range tests {
+		w, err := wif.DecodeWIF(test.wif)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := w.Address(test.mainnet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != test.address {
+			t.Errorf("Address failed for %s: want '%s', got '%s'", test.wif, test.address, addr)
+		}
+	}
+}
+
+func TestNewWIFFromBytes(t *testing.T) {
+	raw := []byte{
+		0x0c, 0x28, 0xfc, 0xa3, 0x86, 0xc7, 0xa2, 0x27,
+		0x60, 0x0b, 0x2f, 0xe5, 0x0b, 0x7c, 0xae, 0x11,
+		0xec, 0x86, 0xd3, 0xbf, 0x1f, 0xbe, 0x47, 0x1b,
+		0xe8, 0x98, 0x27, 0xe1, 0x9d, 0x72, 0xaa, 0x1d,
+	}
+
+	w, err := wif.NewWIFFromBytes(raw, &chaincfg.MainNet, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := w.String(); got != "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ" {
+		t.Fatalf("unexpected WIF %s", got)
+	}
+
+	for _, net := range []*chaincfg.Params{&chaincfg.MainNet, &chaincfg.TestNet} {
+		for _, compress := range []bool{false, true} {
+			w, err := wif.NewWIFFromBytes(raw, net, compress)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := wif.DecodeWIF(w.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !decoded.IsForNet(net) || decoded.CompressPubKey != compress {
+				t.Errorf("round trip lost network or compression for %s", w.String())
+			}
+			if got := decoded.PrivKey.Serialise(); string(got) != string(raw) {
+				t.Errorf("round trip returned key %x", got)
+			}
+		}
+	}
+
+	if _, err := wif.NewWIFFromBytes(make([]byte, 32), &chaincfg.MainNet, true); !errors.Is(err, ec.ErrInvalidPrivateKey) {
+		t.Errorf("expected ErrInvalidPrivateKey for zero key, got %v", err)
+	}
+	if _, err := wif.NewWIFFromBytes(raw[:31], &chaincfg.MainNet, true); !errors.Is(err, ec.ErrInvalidPrivateKey) {
+		t.Errorf("expected ErrInvalidPrivateKey for short key, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+// Package keyderiv implements BRC-42 peer-to-peer key derivation and the
+// BRC-43 invoice number format.
+//
+// Two parties, a sender and a recipient, can each derive the same child key
+// for a given invoice number without sharing any private data. The sender
+// derives the recipient's child public key from their own private key and the
+// recipient's public key, while the recipient derives the matching child
+// private key from their own private key and the sender's public key.
+//
+// See BRC-42 spec here: https://github.com/bitcoin-sv/BRCs/blob/master/key-derivation/0042.md
+// See BRC-43 spec here: https://github.com/bitcoin-sv/BRCs/blob/master/key-derivation/0043.md
+package keyderiv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bitcoin-sv/go-sdk/ec"
+)
+
+// Sentinel errors raised when building BRC-43 invoice numbers.
+var (
+	ErrInvalidSecurityLevel = errors.New("security level must be 0, 1 or 2")
+	ErrInvalidProtocolID    = errors.New("invalid protocol ID")
+	ErrInvalidKeyID         = errors.New("invalid key ID")
+)
+
+// DerivePublicKey derives the recipient's child public key for the given
+// invoice number, using the sender's private key and the recipient's public key.
+// It is ec.PublicKey.DeriveChild, named for the sender's side of the exchange.
+func DerivePublicKey(senderPriv *ec.PrivateKey, recipientPub *ec.PublicKey, invoiceNumber string) (*ec.PublicKey, error) {
+	return recipientPub.DeriveChild(senderPriv, invoiceNumber)
+}
+
+// DerivePrivateKey derives the recipient's child private key for the given
+// invoice number, using the recipient's private key and the sender's public key.
+// It is ec.PrivateKey.DeriveChild, named for the recipient's side of the exchange.
+//
+// The public key of the result is equal to the key returned by DerivePublicKey
+// when called by the sender with the same invoice number.
+func DerivePrivateKey(recipientPriv *ec.PrivateKey, senderPub *ec.PublicKey, invoiceNumber string) (*ec.PrivateKey, error) {
+	return recipientPriv.DeriveChild(senderPub, invoiceNumber)
+}
+
+// InvoiceNumber builds a BRC-43 invoice number of the form
+// `<securityLevel>-<protocolID>-<keyID>`, normalising and validating the
+// protocol ID and key ID.
+func InvoiceNumber(securityLevel int, protocolID, keyID string) (string, error) {
+	if securityLevel < 0 || securityLevel > 2 {
+		return "", ErrInvalidSecurityLevel
+	}
+
+	protocolID = strings.ToLower(strings.TrimSpace(protocolID))
+	switch {
+	case len(protocolID) < 5 || len(protocolID) > 280:
+		return "", fmt.Errorf("%w: must be between 5 and 280 characters", ErrInvalidProtocolID)
+	case strings.Contains(protocolID, "  "):
+		return "", fmt.Errorf("%w: must not contain consecutive spaces", ErrInvalidProtocolID)
+	case strings.HasSuffix(protocolID, " protocol"):
+		return "", fmt.Errorf("%w: must not end with \" protocol\"", ErrInvalidProtocolID)
+	}
+	for _, r := range protocolID {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != ' ' {
+			return "", fmt.Errorf("%w: must only contain letters, numbers and spaces", ErrInvalidProtocolID)
+		}
+	}
+
+	if len(keyID) < 1 || len(keyID) > 800 {
+		return "", fmt.Errorf("%w: must be between 1 and 800 bytes", ErrInvalidKeyID)
+	}
+
+	return fmt.Sprintf("%d-%s-%s", securityLevel, protocolID, keyID), nil
+}
@@ -0,0 +1,105 @@
+package keyderiv
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readVectors(t *testing.T, name string, v interface{}) {
+	bb, err := os.ReadFile(filepath.Join("..", "ec", "testdata", name))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(bb, v))
+}
+
+func TestDerivePrivateKey(t *testing.T) {
+	var vectors []struct {
+		SenderPublicKey     string `json:"senderPublicKey"`
+		RecipientPrivateKey string `json:"recipientPrivateKey"`
+		InvoiceNumber       string `json:"invoiceNumber"`
+		PrivateKey          string `json:"privateKey"`
+	}
+	readVectors(t, "BRC42.private.vectors.json", &vectors)
+
+	for i, v := range vectors {
+		t.Run("BRC42 private vector #"+strconv.Itoa(i+1), func(t *testing.T) {
+			senderPub, err := ec.PublicKeyFromString(v.SenderPublicKey)
+			require.NoError(t, err)
+			recipientPriv, err := ec.PrivateKeyFromString(v.RecipientPrivateKey)
+			require.NoError(t, err)
+
+			derived, err := DerivePrivateKey(recipientPriv, senderPub, v.InvoiceNumber)
+			require.NoError(t, err)
+			assert.Equal(t, v.PrivateKey, hex.EncodeToString(derived.Serialise()))
+		})
+	}
+}
+
+func TestDerivePublicKey(t *testing.T) {
+	var vectors []struct {
+		SenderPrivateKey   string `json:"senderPrivateKey"`
+		RecipientPublicKey string `json:"recipientPublicKey"`
+		InvoiceNumber      string `json:"invoiceNumber"`
+		PublicKey          string `json:"publicKey"`
+	}
+	readVectors(t, "BRC42.public.vectors.json", &vectors)
+
+	for i, v := range vectors {
+		t.Run("BRC42 public vector #"+strconv.Itoa(i+1), func(t *testing.T) {
+			senderPriv, err := ec.PrivateKeyFromString(v.SenderPrivateKey)
+			require.NoError(t, err)
+			recipientPub, err := ec.PublicKeyFromString(v.RecipientPublicKey)
+			require.NoError(t, err)
+
+			derived, err := DerivePublicKey(senderPriv, recipientPub, v.InvoiceNumber)
+			require.NoError(t, err)
+			assert.Equal(t, v.PublicKey, hex.EncodeToString(derived.Compressed()))
+		})
+	}
+}
+
+func TestDeriveKeyPair(t *testing.T) {
+	sender, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	recipient, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	invoice, err := InvoiceNumber(2, "Payment Channel", "42")
+	require.NoError(t, err)
+	assert.Equal(t, "2-payment channel-42", invoice)
+
+	pub, err := DerivePublicKey(sender, recipient.PubKey(), invoice)
+	require.NoError(t, err)
+	priv, err := DerivePrivateKey(recipient, sender.PubKey(), invoice)
+	require.NoError(t, err)
+	assert.True(t, priv.PubKey().IsEqual(pub))
+}
+
+func TestInvoiceNumber(t *testing.T) {
+	tests := map[string]struct {
+		level      int
+		protocolID string
+		keyID      string
+		expErr     error
+	}{
+		"invalid security level": {level: 3, protocolID: "hello world", keyID: "1", expErr: ErrInvalidSecurityLevel},
+		"protocol too short":     {level: 0, protocolID: "abc", keyID: "1", expErr: ErrInvalidProtocolID},
+		"consecutive spaces":     {level: 0, protocolID: "hello  world", keyID: "1", expErr: ErrInvalidProtocolID},
+		"protocol suffix":        {level: 0, protocolID: "hello protocol", keyID: "1", expErr: ErrInvalidProtocolID},
+		"invalid character":      {level: 0, protocolID: "hello-world", keyID: "1", expErr: ErrInvalidProtocolID},
+		"empty key id":           {level: 0, protocolID: "hello world", keyID: "", expErr: ErrInvalidKeyID},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := InvoiceNumber(test.level, test.protocolID, test.keyID)
+			assert.ErrorIs(t, err, test.expErr)
+		})
+	}
+}
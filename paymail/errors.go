@@ -0,0 +1,41 @@
+package paymail
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors reported by paymail requests.
+var (
+	// ErrInvalidAddress is returned when a paymail handle is not of the form alias@domain.
+	ErrInvalidAddress = errors.New("invalid paymail address")
+
+	// ErrCapabilityNotSupported is returned when the paymail host does not advertise
+	// a capability needed for the request.
+	ErrCapabilityNotSupported = errors.New("paymail capability not supported")
+
+	// ErrHostUnreachable is returned, wrapping the underlying error, when a request
+	// to the paymail host fails before a response is received.
+	ErrHostUnreachable = errors.New("paymail host unreachable")
+
+	// ErrUnexpectedResponse is returned when the paymail host returns a response
+	// which cannot be used, such as malformed JSON or an invalid script.
+	ErrUnexpectedResponse = errors.New("unexpected paymail response")
+)
+
+// ResponseError is returned when the paymail host responds with a non 2xx status.
+// It matches ErrUnexpectedResponse with errors.Is.
+type ResponseError struct {
+	URL        string
+	StatusCode int
+}
+
+// Error returns the status code and URL.
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s: status %d from %s", ErrUnexpectedResponse, e.StatusCode, e.URL)
+}
+
+// Unwrap returns ErrUnexpectedResponse.
+func (e *ResponseError) Unwrap() error {
+	return ErrUnexpectedResponse
+}
@@ -0,0 +1,414 @@
+// Package paymail resolves paymail handles, of the form alias@domain, to the
+// locking scripts used to pay them and to their identity public keys.
+//
+// Capability discovery is done by fetching https://<domain>/.well-known/bsvalias.
+// SRV record lookup is not performed, so hosts which delegate their paymail
+// service to another domain through SRV records are not supported.
+//
+// See https://bsvalias.org for the paymail specifications.
+package paymail
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+)
+
+// Capability keys, as BRFC IDs or their aliases, used in capability documents.
+const (
+	// CapabilityPaymentDestination is the basic address resolution capability.
+	CapabilityPaymentDestination = "paymentDestination"
+	// CapabilityP2PPaymentDestination is the P2P payment destination capability.
+	CapabilityP2PPaymentDestination = "2a40af698840"
+	// CapabilityPKI is the public key infrastructure capability, returning the
+	// identity key of a paymail.
+	CapabilityPKI = "pki"
+	// CapabilityVerifyPublicKey is the public key owner verification capability.
+	CapabilityVerifyPublicKey = "a9f510c16bde"
+)
+
+// DefaultCapabilityTTL is how long a Client caches capability documents for
+// if its CapabilityTTL is not set.
+const DefaultCapabilityTTL = 10 * time.Minute
+
+// maxResponseSize limits the size of responses read from paymail hosts.
+const maxResponseSize = 1 << 20
+
+// HTTPClient sends http requests. It is satisfied by *http.Client and allows
+// requests to be intercepted, for example in tests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client performs paymail requests, caching the capability documents of the
+// hosts it has contacted. It is safe for concurrent use.
+type Client struct {
+	httpClient HTTPClient
+	// CapabilityTTL is how long capability documents are cached for. If zero,
+	// DefaultCapabilityTTL is used.
+	CapabilityTTL time.Duration
+
+	mu           sync.Mutex
+	capabilities map[string]*capabilitiesEntry
+	now          func() time.Time
+}
+
+type capabilitiesEntry struct {
+	capabilities map[string]interface{}
+	expires      time.Time
+}
+
+// NewClient returns a Client sending requests with the HTTPClient given, or
+// http.DefaultClient if it is nil.
+func NewClient(httpClient HTTPClient) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		httpClient:   httpClient,
+		capabilities: make(map[string]*capabilitiesEntry),
+		now:          time.Now,
+	}
+}
+
+// DefaultClient is the Client used by the package level functions.
+var DefaultClient = NewClient(nil)
+
+// ResolveOutputScript resolves a paymail handle to a locking script using the
+// DefaultClient, see Client.ResolveOutputScript.
+func ResolveOutputScript(ctx context.Context, paymailAddr string, amount uint64, purpose string) (*bscript.Script, string, error) {
+	return DefaultClient.ResolveOutputScript(ctx, paymailAddr, amount, purpose)
+}
+
+// PayTo adds an output paying amount to a paymail handle using the DefaultClient,
+// see Client.PayTo.
+func PayTo(ctx context.Context, tx *transaction.Tx, paymailAddr string, amount uint64, purpose string) (string, error) {
+	return DefaultClient.PayTo(ctx, tx, paymailAddr, amount, purpose)
+}
+
+// GetPublicKey returns the identity public key of a paymail handle using the
+// DefaultClient, see Client.GetPublicKey.
+func GetPublicKey(ctx context.Context, paymailAddr string) (*ec.PublicKey, error) {
+	return DefaultClient.GetPublicKey(ctx, paymailAddr)
+}
+
+// VerifyPublicKey checks a public key belongs to a paymail handle using the
+// DefaultClient, see Client.VerifyPublicKey.
+func VerifyPublicKey(ctx context.Context, paymailAddr string, pub *ec.PublicKey) (bool, error) {
+	return DefaultClient.VerifyPublicKey(ctx, paymailAddr, pub)
+}
+
+// ResolveOutputScript resolves a paymail handle to the locking script that amount
+// should be paid to.
+//
+// The P2P payment destination capability is used if the host supports it, in which
+// case it must return a single output. The reference the host returns with it is
+// also returned, and must be sent with the tx to complete a P2P payment. Otherwise
+// the basic payment destination capability is used, passing the purpose given, and
+// the reference is empty. ErrCapabilityNotSupported is returned if the host
+// supports neither.
+func (c *Client) ResolveOutputScript(ctx context.Context, paymailAddr string, amount uint64, purpose string) (*bscript.Script, string, error) {
+	alias, domain, err := parseAddress(paymailAddr)
+	if err != nil {
+		return nil, "", err
+	}
+	capabilities, err := c.Capabilities(ctx, domain)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if url, ok := capabilityURL(capabilities, CapabilityP2PPaymentDestination); ok {
+		var resp struct {
+			Outputs []struct {
+				Script   string `json:"script"`
+				Satoshis uint64 `json:"satoshis"`
+			} `json:"outputs"`
+			Reference string `json:"reference"`
+		}
+		body := map[string]interface{}{"satoshis": amount}
+		if err = c.do(ctx, http.MethodPost, expandURL(url, alias, domain, ""), body, &resp); err != nil {
+			return nil, "", err
+		}
+		if len(resp.Outputs) != 1 {
+			return nil, "", fmt.Errorf("%w: expected 1 output, got %d", ErrUnexpectedResponse, len(resp.Outputs))
+		}
+		if resp.Reference == "" {
+			return nil, "", fmt.Errorf("%w: no reference for p2p payment destination", ErrUnexpectedResponse)
+		}
+		s, err := parseScript(resp.Outputs[0].Script)
+		if err != nil {
+			return nil, "", err
+		}
+		return s, resp.Reference, nil
+	}
+
+	if url, ok := capabilityURL(capabilities, CapabilityPaymentDestination); ok {
+		var resp struct {
+			Output string `json:"output"`
+		}
+		body := map[string]interface{}{
+			"dt":      c.now().UTC().Format(time.RFC3339),
+			"amount":  amount,
+			"purpose": purpose,
+		}
+		if err = c.do(ctx, http.MethodPost, expandURL(url, alias, domain, ""), body, &resp); err != nil {
+			return nil, "", err
+		}
+		s, err := parseScript(resp.Output)
+		if err != nil {
+			return nil, "", err
+		}
+		return s, "", nil
+	}
+
+	return nil, "", fmt.Errorf("%w: %s does not support payment destinations", ErrCapabilityNotSupported, domain)
+}
+
+// PayTo resolves the paymail handle with ResolveOutputScript and adds an output
+// paying amount to the script returned. The P2P payment reference, if any, is
+// returned.
+func (c *Client) PayTo(ctx context.Context, tx *transaction.Tx, paymailAddr string, amount uint64, purpose string) (string, error) {
+	s, reference, err := c.ResolveOutputScript(ctx, paymailAddr, amount, purpose)
+	if err != nil {
+		return "", err
+	}
+	tx.AddOutput(&transaction.Output{Satoshis: amount, LockingScript: s})
+	return reference, nil
+}
+
+// GetPublicKey returns the identity public key of a paymail handle, using the
+// pki capability. ErrCapabilityNotSupported is returned if the host does not
+// support it.
+func (c *Client) GetPublicKey(ctx context.Context, paymailAddr string) (*ec.PublicKey, error) {
+	alias, domain, err := parseAddress(paymailAddr)
+	if err != nil {
+		return nil, err
+	}
+	capabilities, err := c.Capabilities(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	url, ok := capabilityURL(capabilities, CapabilityPKI)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support %s", ErrCapabilityNotSupported, domain, CapabilityPKI)
+	}
+
+	var resp struct {
+		Handle string `json:"handle"`
+		PubKey string `json:"pubkey"`
+	}
+	if err = c.do(ctx, http.MethodGet, expandURL(url, alias, domain, ""), nil, &resp); err != nil {
+		return nil, err
+	}
+	return parsePubKey(resp.PubKey)
+}
+
+// VerifyPublicKey checks that pub is a public key of the paymail handle, for
+// confirming the identity of a counterparty.
+//
+// The public key owner verification capability is used if the host supports it.
+// Otherwise pub is compared with the identity key returned by GetPublicKey, in
+// which case ErrCapabilityNotSupported is returned if the host supports neither.
+func (c *Client) VerifyPublicKey(ctx context.Context, paymailAddr string, pub *ec.PublicKey) (bool, error) {
+	if pub == nil {
+		return false, fmt.Errorf("no public key to verify for %s", paymailAddr)
+	}
+	alias, domain, err := parseAddress(paymailAddr)
+	if err != nil {
+		return false, err
+	}
+	capabilities, err := c.Capabilities(ctx, domain)
+	if err != nil {
+		return false, err
+	}
+
+	url, ok := capabilityURL(capabilities, CapabilityVerifyPublicKey)
+	if !ok {
+		identity, err := c.GetPublicKey(ctx, paymailAddr)
+		if err != nil {
+			return false, err
+		}
+		return identity.IsEqual(pub), nil
+	}
+
+	pubHex := hex.EncodeToString(pub.SerialiseCompressed())
+	var resp struct {
+		Handle string `json:"handle"`
+		PubKey string `json:"pubkey"`
+		Match  bool   `json:"match"`
+	}
+	if err = c.do(ctx, http.MethodGet, expandURL(url, alias, domain, pubHex), nil, &resp); err != nil {
+		return false, err
+	}
+	return resp.Match, nil
+}
+
+// Capabilities returns the capabilities advertised by the paymail host at domain,
+// keyed by BRFC ID or alias. Documents are cached for the Client's CapabilityTTL,
+// and each call returns a copy that the caller may change.
+func (c *Client) Capabilities(ctx context.Context, domain string) (map[string]interface{}, error) {
+	c.mu.Lock()
+	entry, ok := c.capabilities[domain]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return copyJSONObject(entry.capabilities), nil
+	}
+
+	var doc struct {
+		BsvAlias     string                 `json:"bsvalias"`
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	if err := c.do(ctx, http.MethodGet, "https://"+domain+"/.well-known/bsvalias", nil, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Capabilities == nil {
+		return nil, fmt.Errorf("%w: no capabilities in document from %s", ErrUnexpectedResponse, domain)
+	}
+
+	ttl := c.CapabilityTTL
+	if ttl == 0 {
+		ttl = DefaultCapabilityTTL
+	}
+	c.mu.Lock()
+	c.capabilities[domain] = &capabilitiesEntry{capabilities: doc.Capabilities, expires: c.now().Add(ttl)}
+	c.mu.Unlock()
+
+	return copyJSONObject(doc.Capabilities), nil
+}
+
+// copyJSONObject returns a deep copy of a decoded JSON object, so the cached
+// capabilities cannot be changed through the map returned to callers.
+func copyJSONObject(m map[string]interface{}) map[string]interface{} {
+	cp := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		cp[k] = copyJSONValue(v)
+	}
+	return cp
+}
+
+func copyJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return copyJSONObject(v)
+	case []interface{}:
+		cp := make([]interface{}, len(v))
+		for i, e := range v {
+			cp[i] = copyJSONValue(e)
+		}
+		return cp
+	}
+	return v
+}
+
+// do sends a request, with body encoded as JSON if it is not nil, and decodes the
+// JSON response into out.
+//
+// Capability URLs come from the host's capability document, so only https URLs
+// are requested, and a response reached by a redirect to another scheme is
+// rejected, both with an ErrUnexpectedResponse error.
+func (c *Client) do(ctx context.Context, method, url string, body, out interface{}) error {
+	if u, err := neturl.Parse(url); err != nil || u.Scheme != "https" {
+		return fmt.Errorf("%w: URL %q is not https", ErrUnexpectedResponse, url)
+	}
+
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHostUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Request != nil && resp.Request.URL.Scheme != "https" {
+		return fmt.Errorf("%w: %s redirected to %s, which is not https", ErrUnexpectedResponse, url, resp.Request.URL)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &ResponseError{URL: url, StatusCode: resp.StatusCode}
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(out); err != nil {
+		return fmt.Errorf("%w: decoding response from %s: %w", ErrUnexpectedResponse, url, err)
+	}
+
+	return nil
+}
+
+// unsafeAddressChars are rejected in paymail handles, as they would change the
+// meaning of the capability URLs the alias and domain are placed in.
+const unsafeAddressChars = "@/\\?#%"
+
+// parseAddress splits a paymail handle into its alias and domain, lower cased.
+func parseAddress(paymailAddr string) (string, string, error) {
+	alias, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(paymailAddr)), "@")
+	if !ok || alias == "" || alias == "." || alias == ".." || domain == "" ||
+		strings.ContainsAny(alias, unsafeAddressChars) || strings.ContainsAny(domain, unsafeAddressChars) ||
+		strings.IndexFunc(alias+domain, func(r rune) bool { return r <= ' ' || r == 0x7f }) != -1 {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidAddress, paymailAddr)
+	}
+	return alias, domain, nil
+}
+
+// capabilityURL returns the URL template of a capability, if it is advertised.
+func capabilityURL(capabilities map[string]interface{}, key string) (string, bool) {
+	url, ok := capabilities[key].(string)
+	return url, ok && url != ""
+}
+
+// expandURL fills in the templated parts of a capability URL, escaping the alias.
+func expandURL(url, alias, domain, pubKey string) string {
+	return strings.NewReplacer(
+		"{alias}", neturl.PathEscape(alias),
+		"{domain.tld}", domain,
+		"{pubkey}", pubKey,
+	).Replace(url)
+}
+
+func parsePubKey(s string) (*ec.PublicKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public key: %w", ErrUnexpectedResponse, err)
+	}
+	pub, err := ec.ParsePubKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public key: %w", ErrUnexpectedResponse, err)
+	}
+	return pub, nil
+}
+
+func parseScript(s string) (*bscript.Script, error) {
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty output script", ErrUnexpectedResponse)
+	}
+	script, err := bscript.NewFromHex(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid output script: %w", ErrUnexpectedResponse, err)
+	}
+	return script, nil
+}
@@ -0,0 +1,270 @@
+package paymail
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+const testScript = "76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac"
+
+// newTestHost starts a paymail host advertising the capabilities given, where
+// each value is the path of the capability on the host. It returns the Client,
+// the domain of the host and a count of capability document requests.
+func newTestHost(t *testing.T, capabilities map[string]string, mux *http.ServeMux) (*Client, string, *int32) {
+	var discoveries int32
+	var srv *httptest.Server
+	mux.HandleFunc("/.well-known/bsvalias", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&discoveries, 1)
+		caps := make(map[string]string, len(capabilities))
+		for k, path := range capabilities {
+			caps[k] = srv.URL + path
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"bsvalias": "1.0", "capabilities": caps})
+	})
+	srv = httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	return NewClient(srv.Client()), strings.TrimPrefix(srv.URL, "https://"), &discoveries
+}
+
+func TestClient_ResolveOutputScript(t *testing.T) {
+	t.Parallel()
+
+	t.Run("p2p payment destination", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/p2p-payment-destination/alice@", func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Satoshis uint64 `json:"satoshis"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, uint64(1000), body.Satoshis)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"outputs":   []map[string]interface{}{{"script": testScript, "satoshis": 1000}},
+				"reference": "ref",
+			})
+		})
+		c, domain, _ := newTestHost(t, map[string]string{
+			CapabilityP2PPaymentDestination: "/p2p-payment-destination/{alias}@",
+			CapabilityPaymentDestination:    "/unused",
+		}, mux)
+
+		s, reference, err := c.ResolveOutputScript(context.Background(), "Alice@"+domain, 1000, "test")
+		assert.NoError(t, err)
+		assert.Equal(t, testScript, s.String())
+		assert.Equal(t, "ref", reference)
+	})
+
+	t.Run("p2p payment destination without reference", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/p2p", func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"outputs": []map[string]interface{}{{"script": testScript, "satoshis": 1000}},
+			})
+		})
+		c, domain, _ := newTestHost(t, map[string]string{CapabilityP2PPaymentDestination: "/p2p"}, mux)
+
+		_, _, err := c.ResolveOutputScript(context.Background(), "alice@"+domain, 1000, "")
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+
+	t.Run("basic payment destination", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/address/alice", func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "invoice 1", body["purpose"])
+			_ = json.NewEncoder(w).Encode(map[string]string{"output": testScript})
+		})
+		c, domain, _ := newTestHost(t, map[string]string{CapabilityPaymentDestination: "/address/{alias}"}, mux)
+
+		tx := transaction.NewTx()
+		reference, err := c.PayTo(context.Background(), tx, "alice@"+domain, 500, "invoice 1")
+		assert.NoError(t, err)
+		assert.Empty(t, reference)
+		assert.Equal(t, 1, tx.OutputCount())
+		assert.Equal(t, uint64(500), tx.Outputs[0].Satoshis)
+		assert.Equal(t, testScript, tx.Outputs[0].LockingScript.String())
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		c, domain, _ := newTestHost(t, map[string]string{"pki": "/pki"}, http.NewServeMux())
+
+		_, _, err := c.ResolveOutputScript(context.Background(), "alice@"+domain, 500, "")
+		assert.ErrorIs(t, err, ErrCapabilityNotSupported)
+	})
+
+	t.Run("bad responses", func(t *testing.T) {
+		newMux := func() *http.ServeMux {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/missing", http.NotFound)
+			mux.HandleFunc("/invalid", func(w http.ResponseWriter, _ *http.Request) {
+				_ = json.NewEncoder(w).Encode(map[string]string{"output": "zz"})
+			})
+			return mux
+		}
+		c, domain, _ := newTestHost(t, map[string]string{CapabilityPaymentDestination: "/missing"}, newMux())
+
+		_, _, err := c.ResolveOutputScript(context.Background(), "alice@"+domain, 500, "")
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+		var respErr *ResponseError
+		assert.ErrorAs(t, err, &respErr)
+		assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+
+		c2, domain2, _ := newTestHost(t, map[string]string{CapabilityPaymentDestination: "/invalid"}, newMux())
+		_, _, err = c2.ResolveOutputScript(context.Background(), "alice@"+domain2, 500, "")
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+
+	t.Run("capability url not https", func(t *testing.T) {
+		var requested int32
+		mux := http.NewServeMux()
+		mux.HandleFunc("/.well-known/bsvalias", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"bsvalias":     "1.0",
+				"capabilities": map[string]string{CapabilityPaymentDestination: "http://" + r.Host + "/address"},
+			})
+		})
+		mux.HandleFunc("/address", func(w http.ResponseWriter, _ *http.Request) {
+			atomic.AddInt32(&requested, 1)
+			_ = json.NewEncoder(w).Encode(map[string]string{"output": testScript})
+		})
+		srv := httptest.NewTLSServer(mux)
+		t.Cleanup(srv.Close)
+		c := NewClient(srv.Client())
+
+		_, _, err := c.ResolveOutputScript(context.Background(), "alice@"+strings.TrimPrefix(srv.URL, "https://"), 500, "")
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&requested))
+	})
+
+	t.Run("unreachable host", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.NewServeMux())
+		domain := strings.TrimPrefix(srv.URL, "https://")
+		c := NewClient(srv.Client())
+		srv.Close()
+
+		_, _, err := c.ResolveOutputScript(context.Background(), "alice@"+domain, 500, "")
+		assert.ErrorIs(t, err, ErrHostUnreachable)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		for _, addr := range []string{"", "alice", "@example.com", "alice@", "alice@example.com/x",
+			"../admin@example.com", "alice?x=1@example.com", "alice#@example.com", "al%2fice@example.com",
+			"al ice@example.com", "alice@example.com?x", "..@example.com"} {
+			_, _, err := NewClient(nil).ResolveOutputScript(context.Background(), addr, 500, "")
+			assert.ErrorIs(t, err, ErrInvalidAddress, addr)
+		}
+	})
+}
+
+func TestClient_Capabilities_Cache(t *testing.T) {
+	t.Parallel()
+
+	c, domain, discoveries := newTestHost(t, map[string]string{"pki": "/pki"}, http.NewServeMux())
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.CapabilityTTL = time.Minute
+
+	for i := 0; i < 3; i++ {
+		caps, err := c.Capabilities(context.Background(), domain)
+		assert.NoError(t, err)
+		assert.Contains(t, caps, "pki")
+		delete(caps, "pki")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(discoveries))
+
+	now = now.Add(time.Minute)
+	_, err := c.Capabilities(context.Background(), domain)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(discoveries))
+}
+
+func TestClient_PublicKey(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	pubHex := hex.EncodeToString(priv.PubKey().SerialiseCompressed())
+	other, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+
+	newMux := func() *http.ServeMux {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/id/", func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]string{"bsvalias": "1.0", "handle": "alice", "pubkey": pubHex})
+		})
+		mux.HandleFunc("/verify/", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"handle": "alice",
+				"match":  strings.HasSuffix(r.URL.Path, "/"+pubHex),
+			})
+		})
+		return mux
+	}
+
+	t.Run("get public key", func(t *testing.T) {
+		c, domain, _ := newTestHost(t, map[string]string{CapabilityPKI: "/id/{alias}@{domain.tld}"}, newMux())
+
+		pub, err := c.GetPublicKey(context.Background(), "alice@"+domain)
+		assert.NoError(t, err)
+		assert.True(t, pub.IsEqual(priv.PubKey()))
+	})
+
+	t.Run("verify with capability", func(t *testing.T) {
+		c, domain, _ := newTestHost(t, map[string]string{
+			CapabilityVerifyPublicKey: "/verify/{alias}@{domain.tld}/{pubkey}",
+		}, newMux())
+
+		ok, err := c.VerifyPublicKey(context.Background(), "alice@"+domain, priv.PubKey())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = c.VerifyPublicKey(context.Background(), "alice@"+domain, other.PubKey())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("verify falls back to pki", func(t *testing.T) {
+		c, domain, _ := newTestHost(t, map[string]string{CapabilityPKI: "/id/{alias}@{domain.tld}"}, newMux())
+
+		ok, err := c.VerifyPublicKey(context.Background(), "alice@"+domain, priv.PubKey())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = c.VerifyPublicKey(context.Background(), "alice@"+domain, other.PubKey())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		c, domain, _ := newTestHost(t, map[string]string{CapabilityPaymentDestination: "/address"}, newMux())
+
+		_, err := c.GetPublicKey(context.Background(), "alice@"+domain)
+		assert.ErrorIs(t, err, ErrCapabilityNotSupported)
+		assert.NotErrorIs(t, err, ErrHostUnreachable)
+		_, err = c.VerifyPublicKey(context.Background(), "alice@"+domain, priv.PubKey())
+		assert.ErrorIs(t, err, ErrCapabilityNotSupported)
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/id", func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]string{"pubkey": "02ff"})
+		})
+		c, domain, _ := newTestHost(t, map[string]string{CapabilityPKI: "/id"}, mux)
+
+		_, err := c.GetPublicKey(context.Background(), "alice@"+domain)
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+}
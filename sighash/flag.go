@@ -1,6 +1,12 @@
 // Package sighash comment
 package sighash
 
+import "errors"
+
+// ErrSignatureTooShort is returned by FromSignature when the signature is
+// too short to contain a sighash flag.
+var ErrSignatureTooShort = errors.New("signature too short to contain a sighash flag")
+
 // Flag represents hash type bits at the end of a signature.
 type Flag uint8
 
@@ -70,3 +76,41 @@ func (f Flag) String() string {
 
 	return "ALL"
 }
+
+// FromSignature returns the sighash flag from the trailing byte of a
+// signature as found in an unlocking script, that is DER encoded and followed
+// by the flag. The DER encoding itself is not checked.
+func FromSignature(sig []byte) (Flag, error) {
+	if len(sig) == 0 {
+		return 0, ErrSignatureTooShort
+	}
+	return Flag(sig[len(sig)-1]), nil
+}
+
+// Explain describes which parts of the transaction a signature with this flag
+// commits to, for example "commits to all inputs and all outputs". Signatures
+// always commit to the input being signed.
+func (f Flag) Explain() string {
+	inputs := "all inputs"
+	if f.Has(AnyOneCanPay) {
+		inputs = "only this input"
+	}
+
+	var outputs string
+	switch f & Mask {
+	case None:
+		outputs = "no outputs"
+	case Single:
+		outputs = "only the output at the same index as this input"
+	default:
+		// unknown base types are hashed as ALL
+		outputs = "all outputs"
+	}
+
+	explanation := "commits to " + inputs + " and " + outputs
+	if !f.Has(ForkID) {
+		explanation += ", without the FORKID replay protection required by BSV"
+	}
+
+	return explanation
+}
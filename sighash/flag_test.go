@@ -0,0 +1,40 @@
+package sighash_test
+
+import (
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/sighash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromSignature(t *testing.T) {
+	t.Parallel()
+
+	f, err := sighash.FromSignature([]byte{0x30, 0x44, 0x02, byte(sighash.AllForkID)})
+	assert.NoError(t, err)
+	assert.Equal(t, sighash.AllForkID, f)
+
+	f, err = sighash.FromSignature([]byte{0xc3})
+	assert.NoError(t, err)
+	assert.Equal(t, sighash.SingleForkID|sighash.AnyOneCanPay, f)
+
+	_, err = sighash.FromSignature(nil)
+	assert.ErrorIs(t, err, sighash.ErrSignatureTooShort)
+}
+
+func TestFlag_Explain(t *testing.T) {
+	t.Parallel()
+
+	tests := map[sighash.Flag]string{
+		sighash.AllForkID:                           "commits to all inputs and all outputs",
+		sighash.NoneForkID:                          "commits to all inputs and no outputs",
+		sighash.SingleForkID:                        "commits to all inputs and only the output at the same index as this input",
+		sighash.AllForkID | sighash.AnyOneCanPay:    "commits to only this input and all outputs",
+		sighash.NoneForkID | sighash.AnyOneCanPay:   "commits to only this input and no outputs",
+		sighash.SingleForkID | sighash.AnyOneCanPay: "commits to only this input and only the output at the same index as this input",
+		sighash.All: "commits to all inputs and all outputs, without the FORKID replay protection required by BSV",
+	}
+	for f, exp := range tests {
+		assert.Equal(t, exp, f.Explain(), f.String())
+	}
+}
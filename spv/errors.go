@@ -0,0 +1,14 @@
+package spv
+
+import "errors"
+
+// Sentinel errors reported by SPV verification.
+var (
+	// ErrMissingProof is returned when a tx has no merkle path and an input spends
+	// a tx which is neither in the BEEF nor itself verified.
+	ErrMissingProof = errors.New("tx has no merkle path and spends unverified txs")
+
+	// ErrScriptVerification is returned, wrapping the interpreter error, when an
+	// input's unlocking script fails against the output it spends.
+	ErrScriptVerification = errors.New("input script verification failed")
+)
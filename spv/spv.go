@@ -0,0 +1,142 @@
+// Package spv verifies txs by Simplified Payment Verification, checking their
+// merkle paths against block headers held by a chain tracker rather than
+// downloading full blocks.
+package spv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bitcoin-sv/go-sdk/bscript/interpreter"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/chaintracker"
+)
+
+// VerifyStream reads and verifies entries one after another from r until it is
+// exhausted, calling yield with the txid and result of each as it goes, so a
+// large stream can be verified without holding it in memory.
+//
+// Each entry is either a BEEF (BRC-62), recognised by its version, or a raw tx
+// followed by its BUMP. A raw tx is verified if its BUMP proves it against the
+// tracker, and the last tx of a BEEF as VerifyBEEF does.
+//
+// The result of each entry is passed to yield: ok is false, with a nil err, if a
+// merkle root is not valid for its block height according to the tracker, and err
+// is set if the entry could not be verified for another reason, such as a failing
+// input script. An error is returned, without calling yield, if an entry cannot
+// be parsed, as the rest of the stream cannot be read, and ctx's error if it is
+// cancelled between entries.
+func VerifyStream(ctx context.Context, r io.Reader, tracker chaintracker.ChainTracker, yield func(txid string, ok bool, err error)) error {
+	br := bufio.NewReader(r)
+	for entry := 0; ; entry++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		version, err := br.Peek(4)
+		if errors.Is(err, io.EOF) && len(version) == 0 {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", entry, err)
+		}
+
+		if binary.LittleEndian.Uint32(version) == transaction.BEEFVersion {
+			txs, err := transaction.ReadBEEF(br)
+			if err != nil {
+				return fmt.Errorf("entry %d: %w", entry, err)
+			}
+			ok, err := verifyBEEFTxs(txs, tracker)
+			yield(txs[len(txs)-1].TxID(), ok, err)
+			continue
+		}
+
+		tx := &transaction.Tx{}
+		if _, err = tx.ReadFrom(br); err != nil {
+			return fmt.Errorf("entry %d: %w", entry, err)
+		}
+		if tx.MerklePath, err = transaction.NewMerklePathFromReader(br); err != nil {
+			return fmt.Errorf("entry %d: %w", entry, err)
+		}
+		txid := tx.TxID()
+		ok, err := tx.MerklePath.Verify(txid, tracker)
+		yield(txid, ok, err)
+	}
+}
+
+// VerifyBEEF checks the last tx of a BEEF (BRC-62) is valid by SPV. It is verified
+// if its merkle path proves it against the tracker, or, where it is not yet mined,
+// if each tx it spends is verified in the same way, recursively, and each of its
+// inputs' unlocking scripts succeeds against the output it spends.
+//
+// False is returned, with a nil error, if a merkle root is not valid for its
+// block height according to the tracker. An ErrMissingProof error is returned if
+// an unmined tx spends an output not in the BEEF, and an ErrScriptVerification
+// error if an input script fails.
+func VerifyBEEF(beef []byte, tracker chaintracker.ChainTracker) (bool, error) {
+	txs, err := transaction.ReadBEEF(bytes.NewReader(beef))
+	if err != nil {
+		return false, err
+	}
+	return verifyBEEFTxs(txs, tracker)
+}
+
+// verifyBEEFTxs verifies the last of txs, as returned by ReadBEEF, as VerifyBEEF.
+// The txs before it which it does not depend on are not verified.
+func verifyBEEFTxs(txs []*transaction.Tx, tracker chaintracker.ChainTracker) (bool, error) {
+	byTxid := make(map[string]*transaction.Tx, len(txs))
+	for _, tx := range txs {
+		byTxid[tx.TxID()] = tx
+	}
+
+	verified := make(map[string]bool, len(txs))
+	var verify func(tx *transaction.Tx) (bool, error)
+	verify = func(tx *transaction.Tx) (bool, error) {
+		txid := tx.TxID()
+		if ok, done := verified[txid]; done {
+			return ok, nil
+		}
+
+		if tx.MerklePath != nil {
+			ok, err := tx.MerklePath.Verify(txid, tracker)
+			verified[txid] = ok && err == nil
+			return ok, err
+		}
+
+		if len(tx.Inputs) == 0 {
+			return false, fmt.Errorf("%w: tx %s has no inputs", ErrMissingProof, txid)
+		}
+		for i, input := range tx.Inputs {
+			source, found := byTxid[input.PreviousTxIDStr()]
+			if !found {
+				return false, fmt.Errorf("%w: tx %s input %d", ErrMissingProof, txid, i)
+			}
+			ok, err := verify(source)
+			if err != nil || !ok {
+				return false, err
+			}
+
+			prevOutput := &transaction.Output{
+				LockingScript: input.PreviousTxScript,
+				Satoshis:      input.PreviousTxSatoshis,
+			}
+			if err = interpreter.NewEngine().Execute(
+				interpreter.WithTx(tx, i, prevOutput),
+				interpreter.WithForkID(),
+				interpreter.WithAfterGenesis(),
+			); err != nil {
+				return false, fmt.Errorf("%w: tx %s input %d: %w", ErrScriptVerification, txid, i, err)
+			}
+		}
+		verified[txid] = true
+
+		return true, nil
+	}
+
+	return verify(txs[len(txs)-1])
+}
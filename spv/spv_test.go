@@ -0,0 +1,139 @@
+package spv_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec/wif"
+	"github.com/bitcoin-sv/go-sdk/spv"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+// headers is a chain tracker holding the merkle root of each block height.
+type headers map[uint32][]byte
+
+func (h headers) IsValidRootForHeight(root []byte, height uint32) bool {
+	return bytes.Equal(h[height], root)
+}
+
+// beef serialises a BEEF of a proven parent and the unproven child spending it.
+func beef(parent *transaction.Tx, child *transaction.Tx) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, transaction.BEEFVersion)
+	b = append(b, transaction.VarInt(1).Bytes()...)
+	b = append(b, parent.MerklePath.Bytes()...)
+	b = append(b, transaction.VarInt(2).Bytes()...)
+	b = append(b, parent.Bytes()...)
+	b = append(b, 1)
+	b = append(b, transaction.VarInt(0).Bytes()...)
+	b = append(b, child.Bytes()...)
+	b = append(b, 0)
+	return b
+}
+
+func TestVerifyStream(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	script, err := bscript.NewP2PKHFromPubKeyEC(w.PrivKey.PubKey())
+	assert.NoError(t, err)
+
+	// parent is mined at height 100 in a block with two other txs
+	parent := transaction.NewTx()
+	assert.NoError(t, parent.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, script.String(), 2000))
+	assert.NoError(t, parent.PayTo(script, 1000))
+	assert.NoError(t, parent.PayTo(script, 900))
+	block := [][]byte{crypto.Sha256d([]byte("coinbase")), parent.TxIDBytes(), crypto.Sha256d([]byte("other"))}
+	parent.MerklePath, err = transaction.NewMerklePathFromBlock(block, 1, 100)
+	assert.NoError(t, err)
+	root, err := parent.MerklePath.ComputeRootBin(nil)
+	assert.NoError(t, err)
+	tracker := headers{100: root}
+
+	spend := func(t *testing.T, vout uint32) *transaction.Tx {
+		child := transaction.NewTx()
+		assert.NoError(t, child.From(parent.TxID(), vout, script.String(), parent.Outputs[vout].Satoshis))
+		assert.NoError(t, child.PayTo(script, 800))
+		assert.NoError(t, child.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: w.PrivKey}))
+		return child
+	}
+
+	type result struct {
+		txid string
+		ok   bool
+		err  error
+	}
+	verify := func(ctx context.Context, stream []byte) ([]result, error) {
+		var results []result
+		err := spv.VerifyStream(ctx, bytes.NewReader(stream), tracker, func(txid string, ok bool, err error) {
+			results = append(results, result{txid, ok, err})
+		})
+		return results, err
+	}
+
+	t.Run("raw and beef entries", func(t *testing.T) {
+		child := spend(t, 0)
+		tampered := spend(t, 1)
+		tampered.Outputs[0].Satoshis = 700
+		movedPath, err := transaction.NewMerklePathFromBinary(parent.MerklePath.Bytes())
+		assert.NoError(t, err)
+		movedPath.BlockHeight = 101
+
+		var stream []byte
+		stream = append(stream, parent.Bytes()...)
+		stream = append(stream, parent.MerklePath.Bytes()...)
+		stream = append(stream, beef(parent, child)...)
+		stream = append(stream, parent.Bytes()...)
+		stream = append(stream, movedPath.Bytes()...)
+		stream = append(stream, beef(parent, tampered)...)
+
+		results, err := verify(context.Background(), stream)
+		assert.NoError(t, err)
+		assert.Len(t, results, 4)
+
+		assert.Equal(t, result{parent.TxID(), true, nil}, results[0])
+		assert.Equal(t, result{child.TxID(), true, nil}, results[1])
+		assert.Equal(t, result{parent.TxID(), false, nil}, results[2])
+		assert.Equal(t, tampered.TxID(), results[3].txid)
+		assert.False(t, results[3].ok)
+		assert.ErrorIs(t, results[3].err, spv.ErrScriptVerification)
+	})
+
+	t.Run("verify beef", func(t *testing.T) {
+		ok, err := spv.VerifyBEEF(beef(parent, spend(t, 0)), tracker)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = spv.VerifyBEEF(beef(parent, spend(t, 0)), headers{})
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := verify(ctx, beef(parent, spend(t, 0)))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, results)
+	})
+
+	t.Run("truncated entry", func(t *testing.T) {
+		stream := append(parent.Bytes(), parent.MerklePath.Bytes()...)
+		stream = append(stream, beef(parent, spend(t, 0))[:50]...)
+		results, err := verify(context.Background(), stream)
+		assert.Error(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		results, err := verify(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
@@ -0,0 +1,148 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/chaincfg"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
+)
+
+const (
+	// AIPPrefix is the Bitcom protocol prefix of the Author Identity Protocol.
+	AIPPrefix = "15PciHG22SNLQJXMoSUaWVi7WSqc7hCfva"
+
+	// AIPAlgorithm is the only AIP signing algorithm supported, a Bitcoin
+	// signed message signature.
+	AIPAlgorithm = "BITCOIN_ECDSA"
+
+	// AIPSeparator is the Bitcom protocol separator placed before the AIP fields.
+	AIPSeparator = "|"
+
+	bsmMagic = "Bitcoin Signed Message:\n"
+)
+
+// AIPOptions configure optional behaviour of AddSignedOpReturn and VerifyAIPData.
+type AIPOptions struct {
+	// Net is the network of the signing address, mainnet if nil.
+	Net *chaincfg.Params
+}
+
+func aipNet(opts []AIPOptions) *chaincfg.Params {
+	if len(opts) > 0 && opts[0].Net != nil {
+		return opts[0].Net
+	}
+	return &chaincfg.MainNet
+}
+
+// AddSignedOpReturn adds an OP_FALSE OP_RETURN output holding the data parts
+// signed using the Author Identity Protocol (AIP). The output has the form
+//
+//	OP_FALSE OP_RETURN <parts...> | <AIPPrefix> BITCOIN_ECDSA <address> <signature>
+//
+// where address is the P2PKH address of the signer, on mainnet unless another
+// network is set in the optional AIPOptions, and signature is the base64 encoded
+// compact Bitcoin signed message signature over the fields as AIP defines them:
+// the OP_RETURN byte followed by the parts and the separator, concatenated.
+func (tx *Tx) AddSignedOpReturn(parts [][]byte, signer *ec.PrivateKey, opts ...AIPOptions) error {
+	addr, err := bscript.NewAddressFromPublicKeyForNet(signer.PubKey(), aipNet(opts))
+	if err != nil {
+		return err
+	}
+
+	sig, err := ec.SignCompact(ec.S256(), signer, bsmHash(aipMessage(parts)), true)
+	if err != nil {
+		return err
+	}
+
+	data := make([][]byte, 0, len(parts)+5)
+	data = append(data, parts...)
+	data = append(data,
+		[]byte(AIPSeparator),
+		[]byte(AIPPrefix),
+		[]byte(AIPAlgorithm),
+		[]byte(addr.AddressString),
+		[]byte(base64.StdEncoding.EncodeToString(sig)),
+	)
+
+	return tx.AddOpReturnPartsOutput(data)
+}
+
+// VerifyAIPData verifies the AIP signature of an output created by AddSignedOpReturn,
+// or by other AIP tools, returning the address which claims to have signed the
+// data and whether the signature was made by that address. The address is
+// expected on mainnet unless another network is set in the optional AIPOptions.
+//
+// ErrNoAIPData is returned if the output does not hold AIP signed data, and
+// ErrInvalidAIPSignature if the signature cannot be decoded.
+func VerifyAIPData(out *Output, opts ...AIPOptions) (string, bool, error) {
+	if !out.IsDataCarrier() {
+		return "", false, ErrNoAIPData
+	}
+
+	ls := *out.LockingScript
+	if ls[0] == bscript.OpFALSE {
+		ls = ls[1:]
+	}
+	parts, err := bscript.DecodeParts(ls[1:])
+	if err != nil {
+		return "", false, fmt.Errorf("%w: %w", ErrNoAIPData, err)
+	}
+
+	sep := -1
+	for i := len(parts) - 5; i >= 0; i-- {
+		if string(parts[i]) == AIPSeparator && string(parts[i+1]) == AIPPrefix {
+			sep = i
+			break
+		}
+	}
+	if sep == -1 {
+		return "", false, ErrNoAIPData
+	}
+	if algo := string(parts[sep+2]); algo != AIPAlgorithm {
+		return "", false, fmt.Errorf("%w: unsupported algorithm %s", ErrNoAIPData, algo)
+	}
+
+	addr := string(parts[sep+3])
+	sig, err := base64.StdEncoding.DecodeString(string(parts[sep+4]))
+	if err != nil {
+		return addr, false, fmt.Errorf("%w: %w", ErrInvalidAIPSignature, err)
+	}
+
+	pubKey, compressed, err := ec.RecoverCompact(sig, bsmHash(aipMessage(parts[:sep])))
+	if err != nil {
+		return addr, false, fmt.Errorf("%w: %w", ErrInvalidAIPSignature, err)
+	}
+
+	pubKeyBytes := pubKey.SerialiseUncompressed()
+	if compressed {
+		pubKeyBytes = pubKey.SerialiseCompressed()
+	}
+	signer, err := bscript.NewAddressFromPublicKeyHashForNet(crypto.Hash160(pubKeyBytes), aipNet(opts))
+	if err != nil {
+		return addr, false, err
+	}
+
+	return addr, signer.AddressString == addr, nil
+}
+
+// aipMessage returns the message AIP signs for the fields preceding the AIP
+// separator: the OP_RETURN byte, the fields and the separator, concatenated.
+func aipMessage(parts [][]byte) []byte {
+	msg := []byte{bscript.OpRETURN}
+	msg = append(msg, bytes.Join(parts, nil)...)
+	return append(msg, AIPSeparator...)
+}
+
+// bsmHash returns the double sha256 of msg in the Bitcoin signed message format.
+func bsmHash(msg []byte) []byte {
+	b := make([]byte, 0, len(bsmMagic)+len(msg)+10)
+	b = append(b, VarInt(len(bsmMagic)).Bytes()...)
+	b = append(b, bsmMagic...)
+	b = append(b, VarInt(len(msg)).Bytes()...)
+	b = append(b, msg...)
+	return crypto.Sha256d(b)
+}
@@ -0,0 +1,120 @@
+package transaction_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/chaincfg"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/ec/wif"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_AddSignedOpReturn(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	addr, err := bscript.NewAddressFromPublicKey(priv.PubKey(), true)
+	assert.NoError(t, err)
+
+	parts := [][]byte{[]byte("19HxigV4QyBv3tHpQVcUEQyq1pzZVdoAut"), []byte("hello world"), []byte("text/plain")}
+
+	t.Run("round trip", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.AddSignedOpReturn(parts, priv))
+		assert.True(t, tx.Outputs[0].IsDataCarrier())
+
+		signer, ok, err := transaction.VerifyAIPData(tx.Outputs[0])
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, addr.AddressString, signer)
+	})
+
+	t.Run("tampered data", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.AddSignedOpReturn(parts, priv))
+
+		tampered := append([][]byte{[]byte("19HxigV4QyBv3tHpQVcUEQyq1pzZVdoAut"), []byte("goodbye world")}, parts[2:]...)
+		out, err := transaction.CreateOpReturnOutput(tampered)
+		assert.NoError(t, err)
+		b, _ := bscript.DecodeParts((*tx.Outputs[0].LockingScript)[2:])
+		assert.NoError(t, out.LockingScript.AppendPushDataArray(b[len(parts):]))
+
+		signer, ok, err := transaction.VerifyAIPData(out)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, addr.AddressString, signer)
+	})
+
+	t.Run("testnet signer", func(t *testing.T) {
+		testnetAddr, err := bscript.NewAddressFromPublicKey(priv.PubKey(), false)
+		assert.NoError(t, err)
+		opts := transaction.AIPOptions{Net: &chaincfg.TestNet}
+
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.AddSignedOpReturn(parts, priv, opts))
+
+		signer, ok, err := transaction.VerifyAIPData(tx.Outputs[0], opts)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, testnetAddr.AddressString, signer)
+
+		_, ok, err = transaction.VerifyAIPData(tx.Outputs[0])
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("no aip data", func(t *testing.T) {
+		out, err := transaction.CreateOpReturnOutput(parts)
+		assert.NoError(t, err)
+
+		_, _, err = transaction.VerifyAIPData(out)
+		assert.ErrorIs(t, err, transaction.ErrNoAIPData)
+	})
+}
+
+func TestVerifyAIPData_Vector(t *testing.T) {
+	t.Parallel()
+
+	// the signature is over the OP_RETURN byte, the fields and the separator,
+	// as specified by AIP, rather than the fields alone
+	parts := [][]byte{[]byte("19HxigV4QyBv3tHpQVcUEQyq1pzZVdoAut"), []byte("hello world"), []byte("text/plain")}
+	const (
+		addr = "1JZaumCzrYoM8KRn4SPmUGXKAgx6BTh17U"
+		sig  = "HwR/MhTcEPzvA8XANgrzTtH95LQJAEZXpgCUz7UxNHE2PkAQizXwj4aCfSSM3o6YTR6V5b0FnnXizA6s3hBckoc="
+	)
+
+	msg := append([]byte{bscript.OpRETURN}, bytes.Join(parts, nil)...)
+	msg = append(msg, '|')
+	magic := "Bitcoin Signed Message:\n"
+	b := append([]byte{byte(len(magic))}, magic...)
+	b = append(append(b, byte(len(msg))), msg...)
+	decoded, err := base64.StdEncoding.DecodeString(sig)
+	assert.NoError(t, err)
+	pub, compressed, err := ec.RecoverCompact(decoded, crypto.Sha256d(b))
+	assert.NoError(t, err)
+	assert.True(t, compressed)
+	recovered, err := bscript.NewAddressFromPublicKey(pub, true)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, recovered.AddressString)
+
+	out, err := transaction.CreateOpReturnOutput(append(parts,
+		[]byte(transaction.AIPSeparator), []byte(transaction.AIPPrefix), []byte(transaction.AIPAlgorithm),
+		[]byte(addr), []byte(sig)))
+	assert.NoError(t, err)
+	signer, ok, err := transaction.VerifyAIPData(out)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, addr, signer)
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	tx := transaction.NewTx()
+	assert.NoError(t, tx.AddSignedOpReturn(parts, w.PrivKey))
+	assert.Equal(t, out.LockingScript, tx.Outputs[0].LockingScript)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 )
 
 type BeefTx struct {
@@ -11,98 +12,111 @@ type BeefTx struct {
 	tx        *Tx
 }
 
+// BEEFVersion is the version number at the start of a BEEF (BRC-62) serialisation,
+// serialised little endian as 0100BEEF.
+const BEEFVersion uint32 = 4022206465
+
+// NewTxFromBEEF parses a BEEF (BRC-62) and returns its last tx, which the others
+// are the ancestors of, with its MerklePath set if it is proven and its inputs'
+// previous scripts and satoshis populated from the BEEF.
 func NewTxFromBEEF(beef []byte) (*Tx, error) {
-	reader := bytes.NewReader(beef)
+	txs, err := ReadBEEF(bytes.NewReader(beef))
+	if err != nil {
+		return nil, err
+	}
+	return txs[len(txs)-1], nil
+}
 
+// ReadBEEF reads a single BEEF (BRC-62) from r and returns all of its txs, in the
+// order given, with each tx's ancestors before it and the tx they support last.
+// Nothing after the BEEF is read, so a reader holding several can be read from
+// again.
+//
+// Each tx with a BUMP has its MerklePath set, and each input spending a tx earlier
+// in the BEEF has its previous script and satoshis populated. An ErrInvalidBEEF
+// error is returned if the version is wrong, there are no txs, a BUMP index is out
+// of range, or a tx without a BUMP spends an output not in the BEEF.
+func ReadBEEF(r io.Reader) ([]*Tx, error) {
 	var version uint32
-	err := binary.Read(reader, binary.LittleEndian, &version)
+	err := binary.Read(r, binary.LittleEndian, &version)
 	if err != nil {
 		return nil, err
 	}
-	if version != 4022206465 {
-		return nil, fmt.Errorf("invalid BEEF version. expected 4022206465, received %d", version)
+	if version != BEEFVersion {
+		return nil, fmt.Errorf("%w: expected version %d, received %d", ErrInvalidBEEF, BEEFVersion, version)
 	}
 
 	// Read the BUMPs
 	var numberOfBUMPs VarInt
-	_, err = numberOfBUMPs.ReadFrom(reader)
+	_, err = numberOfBUMPs.ReadFrom(r)
 	if err != nil {
 		return nil, err
 	}
 
-	BUMPs := make([]*MerklePath, numberOfBUMPs)
-	for i := 0; i < int(numberOfBUMPs); i++ {
-		BUMPs[i], err = NewMerklePathFromReader(reader)
+	BUMPs := make([]*MerklePath, 0)
+	for i := uint64(0); i < uint64(numberOfBUMPs); i++ {
+		bump, err := NewMerklePathFromReader(r)
 		if err != nil {
 			return nil, err
 		}
+		BUMPs = append(BUMPs, bump)
 	}
 
-	// Read all transactions into an object
+	// Read the transactions, which come after the txs they spend
 	var numberOfTransactions VarInt
-	_, err = numberOfTransactions.ReadFrom(reader)
+	_, err = numberOfTransactions.ReadFrom(r)
 	if err != nil {
 		return nil, err
 	}
+	if numberOfTransactions == 0 {
+		return nil, fmt.Errorf("%w: no transactions", ErrInvalidBEEF)
+	}
 
-	transactions := make(map[string]*BeefTx, 0)
-	lastTxid := ""
-	for i := 0; i < int(numberOfTransactions); i++ {
+	txs := make([]*Tx, 0)
+	byTxid := make(map[string]*Tx)
+	for i := uint64(0); i < uint64(numberOfTransactions); i++ {
 		tx := &Tx{}
-		_, err = tx.ReadFrom(reader)
-		if err != nil {
+		if _, err = tx.ReadFrom(r); err != nil {
 			return nil, err
 		}
-		beefTx := &BeefTx{tx: tx}
 		txid := tx.TxID()
-		if i+1 == int(numberOfTransactions) {
-			lastTxid = txid
-		}
+
 		hasBump := make([]byte, 1)
-		_, err = reader.Read(hasBump)
-		if err != nil {
+		if _, err = io.ReadFull(r, hasBump); err != nil {
 			return nil, err
 		}
 		if hasBump[0] != 0 {
 			var pathIndex VarInt
-			_, err = pathIndex.ReadFrom(reader)
-			if err != nil {
+			if _, err = pathIndex.ReadFrom(r); err != nil {
 				return nil, err
 			}
-			val := uint64(pathIndex)
-			beefTx.pathIndex = &val
+			if uint64(pathIndex) >= uint64(len(BUMPs)) {
+				return nil, fmt.Errorf("%w: tx %s has BUMP index %d of %d", ErrInvalidBEEF, txid, pathIndex, len(BUMPs))
+			}
+			tx.MerklePath = BUMPs[pathIndex]
 		}
-		transactions[txid] = beefTx
-	}
-
-	populateInputsFromBeef(transactions[lastTxid], BUMPs, transactions)
-	return transactions[lastTxid].tx, nil
-}
 
-func populateInputsFromBeef(beefTx *BeefTx, bumps []*MerklePath, transactions map[string]*BeefTx) {
-	if beefTx.pathIndex != nil {
-		path := bumps[*beefTx.pathIndex]
-		if path == nil {
-			panic("Invalid merkle path index found in BEEF!")
-		}
-		beefTx.tx.MerklePath = path
-	} else {
-		for _, input := range beefTx.tx.Inputs {
+		for _, input := range tx.Inputs {
 			sourceTxid := input.PreviousTxIDStr()
-			if sourceObj, ok := transactions[sourceTxid]; !ok {
-				panic(fmt.Sprintf("Reference to unknown TXID in BUMP: %s", sourceTxid))
-			} else {
-				input.PreviousTxScript = sourceObj.tx.Outputs[input.PreviousTxOutIndex].LockingScript
-				input.PreviousTxSatoshis = sourceObj.tx.Outputs[input.PreviousTxOutIndex].Satoshis
-				populateInputsFromBeef(sourceObj, bumps, transactions)
+			source, ok := byTxid[sourceTxid]
+			if ok && int(input.PreviousTxOutIndex) < len(source.Outputs) {
+				input.PreviousTxScript = source.Outputs[input.PreviousTxOutIndex].LockingScript
+				input.PreviousTxSatoshis = source.Outputs[input.PreviousTxOutIndex].Satoshis
+			} else if tx.MerklePath == nil {
+				return nil, fmt.Errorf("%w: tx %s spends unknown output %s:%d", ErrInvalidBEEF, txid, sourceTxid, input.PreviousTxOutIndex)
 			}
 		}
+
+		txs = append(txs, tx)
+		byTxid[txid] = tx
 	}
+
+	return txs, nil
 }
 
 func (t *Tx) BEEF() []byte {
 	b := new(bytes.Buffer)
-	binary.Write(b, binary.LittleEndian, uint32(4022206465))
+	binary.Write(b, binary.LittleEndian, BEEFVersion)
 	bumps := make([]*MerklePath, 0)
 	txs := make(map[string]*BeefTx, 0)
 
@@ -1,5 +1,10 @@
 package transaction
 
+import (
+	"context"
+	"fmt"
+)
+
 type BroadcastSuccess struct {
 	Txid    string `json:"txid"`
 	Message string `json:"message"`
@@ -21,3 +26,89 @@ type Broadcaster interface {
 func (t *Tx) Broadcast(b Broadcaster) (*BroadcastSuccess, *BroadcastFailure) {
 	return b.Broadcast(t)
 }
+
+// BatchBroadcastOptions are options for BatchBroadcast.
+type BatchBroadcastOptions struct {
+	// ContinueOnFailure keeps broadcasting the rest of the batch after a tx fails,
+	// rather than stopping. Descendants of a failed tx are still skipped.
+	ContinueOnFailure bool
+}
+
+// BroadcastResult is the outcome of broadcasting one tx of a batch. Exactly one
+// of Success and Failure is set, unless the tx was Skipped.
+type BroadcastResult struct {
+	TxID    string
+	Success *BroadcastSuccess
+	Failure *BroadcastFailure
+	// Skipped is true if the tx was not broadcast, because an ancestor in the
+	// batch failed or the batch was stopped.
+	Skipped bool
+}
+
+// BatchBroadcast broadcasts a batch of txs, such as a chain of txs each spending
+// the last, in dependency order: a tx spending an output of another tx in the
+// batch is broadcast after it. Txs not depending on each other keep their order
+// in txs.
+//
+// A result is returned for every tx, keyed by txid. Broadcasting stops at the
+// first failure unless BatchBroadcastOptions.ContinueOnFailure is set, and in
+// either case the error returned wraps ErrBroadcastFailed and the first
+// BroadcastFailure. If ctx is done, the remaining txs are skipped and the error
+// wraps ctx.Err().
+//
+// ErrDuplicateTx is returned, without broadcasting anything, if a txid appears
+// more than once, and likewise ErrBatchCycle if the txs depend on each other in
+// a cycle, which can only happen if their previous txids have been tampered with.
+func BatchBroadcast(ctx context.Context, b Broadcaster, txs []*Tx, opts ...BatchBroadcastOptions) (map[string]*BroadcastResult, error) {
+	var opt BatchBroadcastOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	g, err := BuildDependencyGraph(txs)
+	if err != nil {
+		return nil, err
+	}
+	order, err := g.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*BroadcastResult, len(txs))
+	for _, tx := range txs {
+		results[tx.TxID()] = &BroadcastResult{TxID: tx.TxID(), Skipped: true}
+	}
+
+	var firstErr error
+	for _, tx := range order {
+		if firstErr != nil && !opt.ContinueOnFailure {
+			break
+		}
+		if err = ctx.Err(); err != nil {
+			return results, err
+		}
+		if failedParent(tx, results) {
+			continue
+		}
+
+		r := results[tx.TxID()]
+		r.Skipped = false
+		r.Success, r.Failure = b.Broadcast(tx)
+		if r.Failure != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%w: tx %s: %w", ErrBroadcastFailed, r.TxID, r.Failure)
+		}
+	}
+
+	return results, firstErr
+}
+
+// failedParent returns true if tx spends a tx in the batch which was not broadcast
+// successfully.
+func failedParent(tx *Tx, results map[string]*BroadcastResult) bool {
+	for _, in := range tx.Inputs {
+		if r, ok := results[in.PreviousTxIDStr()]; ok && r.Success == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockBroadcaster records the txs broadcast, failing those in fail.
+type mockBroadcaster struct {
+	fail      map[string]bool
+	broadcast []string
+}
+
+func (m *mockBroadcaster) Broadcast(tx *transaction.Tx) (*transaction.BroadcastSuccess, *transaction.BroadcastFailure) {
+	m.broadcast = append(m.broadcast, tx.TxID())
+	if m.fail[tx.TxID()] {
+		return nil, &transaction.BroadcastFailure{Code: "400", Description: "rejected"}
+	}
+	return &transaction.BroadcastSuccess{Txid: tx.TxID(), Message: "ok"}, nil
+}
+
+func TestBatchBroadcast(t *testing.T) {
+	t.Parallel()
+
+	const script = "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac"
+	spend := func(t *testing.T, prevTxID string, sats uint64) *transaction.Tx {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.From(prevTxID, 0, script, sats))
+		assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", sats-1))
+		return tx
+	}
+	// parent <- child <- grandchild, and an unrelated tx
+	parent := spend(t, "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 1000)
+	child := spend(t, parent.TxID(), 999)
+	grandchild := spend(t, child.TxID(), 998)
+	other := spend(t, "45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 1000)
+	batch := []*transaction.Tx{grandchild, other, child, parent}
+
+	t.Run("dependency order", func(t *testing.T) {
+		b := &mockBroadcaster{}
+		results, err := transaction.BatchBroadcast(context.Background(), b, batch)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{other.TxID(), parent.TxID(), child.TxID(), grandchild.TxID()}, b.broadcast)
+		assert.Len(t, results, 4)
+		for _, tx := range batch {
+			assert.NotNil(t, results[tx.TxID()].Success)
+			assert.False(t, results[tx.TxID()].Skipped)
+		}
+	})
+
+	t.Run("stop on failure", func(t *testing.T) {
+		b := &mockBroadcaster{fail: map[string]bool{other.TxID(): true}}
+		results, err := transaction.BatchBroadcast(context.Background(), b, batch)
+		assert.ErrorIs(t, err, transaction.ErrBroadcastFailed)
+		assert.Contains(t, err.Error(), "rejected")
+		assert.Equal(t, []string{other.TxID()}, b.broadcast)
+		assert.NotNil(t, results[other.TxID()].Failure)
+		assert.True(t, results[parent.TxID()].Skipped)
+	})
+
+	t.Run("continue on failure skips descendants", func(t *testing.T) {
+		b := &mockBroadcaster{fail: map[string]bool{parent.TxID(): true}}
+		results, err := transaction.BatchBroadcast(context.Background(), b, batch,
+			transaction.BatchBroadcastOptions{ContinueOnFailure: true})
+		assert.ErrorIs(t, err, transaction.ErrBroadcastFailed)
+		assert.Equal(t, []string{other.TxID(), parent.TxID()}, b.broadcast)
+		assert.NotNil(t, results[other.TxID()].Success)
+		assert.NotNil(t, results[parent.TxID()].Failure)
+		assert.True(t, results[child.TxID()].Skipped)
+		assert.True(t, results[grandchild.TxID()].Skipped)
+	})
+
+	t.Run("duplicate tx", func(t *testing.T) {
+		b := &mockBroadcaster{}
+		_, err := transaction.BatchBroadcast(context.Background(), b, []*transaction.Tx{parent, parent})
+		assert.ErrorIs(t, err, transaction.ErrDuplicateTx)
+		assert.Empty(t, b.broadcast)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		b := &mockBroadcaster{}
+		results, err := transaction.BatchBroadcast(ctx, b, batch)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, b.broadcast)
+		assert.True(t, results[parent.TxID()].Skipped)
+	})
+}
@@ -0,0 +1,154 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+)
+
+// builderStage is the furthest step a Builder has reached.
+type builderStage int
+
+const (
+	stageOutputs builderStage = iota
+	stageFunded
+	stageChanged
+	stageSigned
+)
+
+func (s builderStage) String() string {
+	switch s {
+	case stageFunded:
+		return "funded"
+	case stageChanged:
+		return "change added"
+	case stageSigned:
+		return "signed"
+	default:
+		return "building"
+	}
+}
+
+// Builder wraps a Tx and enforces the order in which it is built:
+//
+//  1. inputs and outputs are added, with From, AddOutput and PayTo
+//  2. the tx is funded with Fund, optionally adding change with FundOptions
+//  3. change is added with Change
+//  4. the inputs are signed with Sign
+//  5. the tx is returned by Build
+//
+// Change is optional, but steps cannot be taken out of order, for example
+// outputs cannot be added once the tx is funded, and Change and Sign fail until
+// Fund has been called, even if inputs were added with From. Such calls return an
+// error matching ErrBuilderOrder and leave the tx unchanged.
+//
+// The Tx methods remain available for building txs without these checks.
+type Builder struct {
+	tx    *Tx
+	stage builderStage
+}
+
+// NewBuilder returns a Builder for an empty tx.
+func NewBuilder() *Builder {
+	return &Builder{tx: NewTx()}
+}
+
+// From adds inputs spending the utxos provided, as Tx.FromUTXOs. Inputs cannot
+// be added once change has been added or the tx signed.
+func (b *Builder) From(utxos ...*UTXO) error {
+	if err := b.require("add inputs", stageFunded); err != nil {
+		return err
+	}
+	return b.tx.FromUTXOs(utxos...)
+}
+
+// AddOutput adds an output. Outputs cannot be added once the tx is funded.
+func (b *Builder) AddOutput(o *Output) error {
+	if err := b.require("add outputs", stageOutputs); err != nil {
+		return err
+	}
+	b.tx.AddOutput(o)
+	return nil
+}
+
+// PayTo adds an output paying satoshis to the locking script, as Tx.PayTo.
+// Outputs cannot be added once the tx is funded.
+func (b *Builder) PayTo(script *bscript.Script, satoshis uint64) error {
+	if err := b.require("add outputs", stageOutputs); err != nil {
+		return err
+	}
+	return b.tx.PayTo(script, satoshis)
+}
+
+// Fund funds the tx, as Tx.Fund. The tx must have outputs to fund and must not
+// have change added yet. If FundOptions.AutoChange is set the builder moves
+// straight to the change added stage.
+func (b *Builder) Fund(ctx context.Context, fq *FeeQuote, next UTXOGetterFunc, opts ...FundOptions) error {
+	if err := b.require("fund", stageFunded); err != nil {
+		return err
+	}
+	if b.tx.OutputCount() == 0 {
+		return fmt.Errorf("%w: cannot fund a tx with no outputs", ErrBuilderOrder)
+	}
+	if err := b.tx.Fund(ctx, fq, next, opts...); err != nil {
+		return err
+	}
+
+	b.stage = stageFunded
+	if len(opts) > 0 && opts[0].AutoChange {
+		b.stage = stageChanged
+	}
+	return nil
+}
+
+// Change adds change paying to the script provided, as Tx.Change. The tx must have
+// been funded, and change can only be added once.
+func (b *Builder) Change(s *bscript.Script, fq *FeeQuote) error {
+	if err := b.require("add change", stageFunded); err != nil {
+		return err
+	}
+	if b.stage < stageFunded {
+		return fmt.Errorf("%w: cannot add change before the tx is funded", ErrBuilderOrder)
+	}
+	if err := b.tx.Change(s, fq); err != nil {
+		return err
+	}
+
+	b.stage = stageChanged
+	return nil
+}
+
+// Sign signs every input, as Tx.FillAllInputs. The tx must have been funded, and
+// once signed it can no longer be changed.
+func (b *Builder) Sign(ctx context.Context, ug UnlockerGetter) error {
+	if err := b.require("sign", stageChanged); err != nil {
+		return err
+	}
+	if b.stage < stageFunded {
+		return fmt.Errorf("%w: cannot sign before the tx is funded", ErrBuilderOrder)
+	}
+	if err := b.tx.FillAllInputs(ctx, ug); err != nil {
+		return err
+	}
+
+	b.stage = stageSigned
+	return nil
+}
+
+// Build returns the signed tx. It fails if Sign has not been called.
+func (b *Builder) Build() (*Tx, error) {
+	if b.stage != stageSigned {
+		return nil, fmt.Errorf("%w: cannot build before the tx is signed", ErrBuilderOrder)
+	}
+	return b.tx, nil
+}
+
+// require returns an ErrBuilderOrder error if the builder has gone past the
+// last stage at which op is allowed.
+func (b *Builder) require(op string, last builderStage) error {
+	if b.stage > last {
+		return fmt.Errorf("%w: cannot %s once the tx is %s", ErrBuilderOrder, op, b.stage)
+	}
+	return nil
+}
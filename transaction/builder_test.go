@@ -0,0 +1,93 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	script, err := bscript.NewP2PKHFromPubKeyEC(priv.PubKey())
+	assert.NoError(t, err)
+	txID, err := hex.DecodeString("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+	assert.NoError(t, err)
+	utxos := []*transaction.UTXO{
+		{TxID: txID, Vout: 0, LockingScript: script, Satoshis: 1000},
+		{TxID: txID, Vout: 1, LockingScript: script, Satoshis: 1000},
+	}
+	ug := &unlocker.Getter{PrivateKey: priv}
+	fq := transaction.NewFeeQuote()
+
+	t.Run("builds in order", func(t *testing.T) {
+		b := transaction.NewBuilder()
+		assert.NoError(t, b.PayTo(script, 1500))
+		assert.NoError(t, b.Fund(context.Background(), fq, transaction.NewConsolidationGetter(utxos, 0)))
+		assert.NoError(t, b.Change(script, fq))
+		assert.NoError(t, b.Sign(context.Background(), ug))
+
+		tx, err := b.Build()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, tx.InputCount())
+		assert.Equal(t, 2, tx.OutputCount())
+		assert.NoError(t, tx.VerifyInputSignatures())
+	})
+
+	t.Run("auto change", func(t *testing.T) {
+		b := transaction.NewBuilder()
+		assert.NoError(t, b.PayTo(script, 1500))
+		assert.NoError(t, b.Fund(context.Background(), fq, transaction.NewConsolidationGetter(utxos, 0),
+			transaction.FundOptions{AutoChange: true, ChangeScript: script}))
+		assert.ErrorIs(t, b.Change(script, fq), transaction.ErrBuilderOrder)
+		assert.NoError(t, b.Sign(context.Background(), ug))
+
+		tx, err := b.Build()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, tx.OutputCount())
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		b := transaction.NewBuilder()
+		assert.ErrorIs(t, b.Sign(context.Background(), ug), transaction.ErrBuilderOrder)
+		assert.ErrorIs(t, b.Change(script, fq), transaction.ErrBuilderOrder)
+		assert.ErrorIs(t, b.Fund(context.Background(), fq, transaction.NewConsolidationGetter(utxos, 0)), transaction.ErrBuilderOrder)
+		_, err := b.Build()
+		assert.ErrorIs(t, err, transaction.ErrBuilderOrder)
+
+		assert.NoError(t, b.From(utxos...))
+		assert.ErrorIs(t, b.Sign(context.Background(), ug), transaction.ErrBuilderOrder)
+
+		assert.NoError(t, b.PayTo(script, 1500))
+		// inputs and outputs are present, but Fund has not been called
+		err = b.Sign(context.Background(), ug)
+		assert.ErrorIs(t, err, transaction.ErrBuilderOrder)
+		assert.Contains(t, err.Error(), "before the tx is funded")
+		assert.ErrorIs(t, b.Change(script, fq), transaction.ErrBuilderOrder)
+
+		assert.NoError(t, b.Fund(context.Background(), fq, transaction.NewConsolidationGetter(nil, 0)))
+		assert.ErrorIs(t, b.PayTo(script, 100), transaction.ErrBuilderOrder)
+		assert.ErrorIs(t, b.AddOutput(&transaction.Output{LockingScript: script, Satoshis: 100}), transaction.ErrBuilderOrder)
+
+		assert.NoError(t, b.Change(script, fq))
+		assert.ErrorIs(t, b.Change(script, fq), transaction.ErrBuilderOrder)
+		assert.ErrorIs(t, b.From(utxos[0]), transaction.ErrBuilderOrder)
+
+		assert.NoError(t, b.Sign(context.Background(), ug))
+		err = b.Sign(context.Background(), ug)
+		assert.ErrorIs(t, err, transaction.ErrBuilderOrder)
+		assert.Contains(t, err.Error(), "once the tx is signed")
+
+		tx, err := b.Build()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, tx.InputCount())
+	})
+}
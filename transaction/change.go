@@ -9,6 +9,8 @@ const (
 
 // ChangeToAddress calculates the amount of fees needed to cover the transaction
 // and adds the leftover change in a new P2PKH output using the address provided.
+//
+// No change is added if the leftover amount does not exceed the fee quote's DustThreshold.
 func (tx *Tx) ChangeToAddress(addr string, f *FeeQuote) error {
 	s, err := bscript.NewP2PKHFromAddress(addr)
 	if err != nil {
@@ -18,9 +20,9 @@ func (tx *Tx) ChangeToAddress(addr string, f *FeeQuote) error {
 	return tx.Change(s, f)
 }
 
-// Change calculates the amount of fees needed to cover the transaction
-//
-//	and adds the leftover change in a new output using the script provided.
+// Change calculates the amount of fees needed to cover the transaction, including
+// the new change output sized for the script provided, and adds the leftover
+// change in that output.
 func (tx *Tx) Change(s *bscript.Script, f *FeeQuote) error {
 	if _, _, err := tx.change(f, &changeOutput{
 		lockingScript: s,
@@ -52,53 +54,106 @@ type changeOutput struct {
 	newOutput     bool
 }
 
+// p2pkhOutputSize is the serialised size of a P2PKH output.
+const p2pkhOutputSize = 8 + 1 + 25
+
+// outputSize returns the serialised size of an output locked by s.
+func outputSize(s *bscript.Script) uint64 {
+	if s == nil {
+		return 8 + 1
+	}
+	return 8 + uint64(VarInt(len(*s)).Length()) + uint64(len(*s))
+}
+
+// CalculateChange returns the change that would remain once the fees are paid,
+// including the fee for a new P2PKH change output, without modifying the tx.
+// The returned bool is false if the change does not exceed the fee quote's DustThreshold,
+// in which case a change output should not be added.
+//
+// An ErrInsufficientInputs error is returned if the outputs exceed the inputs.
+func (tx *Tx) CalculateChange(f *FeeQuote) (uint64, bool, error) {
+	available, err := tx.changeAmount(f, p2pkhOutputSize)
+	if err != nil {
+		return 0, false, err
+	}
+	return available, available > f.DustThreshold(), nil
+}
+
 // change will return the amount of satoshis to add to an input after fees are removed.
 // True will be returned if change is required for this tx.
 func (tx *Tx) change(f *FeeQuote, output *changeOutput) (uint64, bool, error) {
-	inputAmount := tx.TotalInputSatoshis()
-	outputAmount := tx.TotalOutputSatoshis()
+	var changeBytes uint64
+	if output != nil && output.newOutput {
+		changeBytes = outputSize(output.lockingScript)
+	}
+	available, err := tx.changeAmount(f, changeBytes)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// not enough to add change, or the change would be dust, no change to add
+	if available <= f.DustThreshold() {
+		return 0, false, nil
+	}
+
+	// if we want to add to a new output, set
+	// newOutput to true, this will add the calculated change
+	// into a new output
+	if output != nil && output.newOutput {
+		tx.AddOutput(&Output{Satoshis: available, LockingScript: output.lockingScript})
+	}
+	return available, true, nil
+}
+
+// changeAmount returns the satoshis left over once the estimated fees are paid,
+// or zero if the fees are not covered. The fee includes changeBytes, the size of
+// the change output to be added, if any.
+func (tx *Tx) changeAmount(f *FeeQuote, changeBytes uint64) (uint64, error) {
+	inputAmount, outputAmount, err := tx.totalSatoshisChecked()
+	if err != nil {
+		return 0, err
+	}
 	if inputAmount < outputAmount {
-		return 0, false, ErrInsufficientInputs
+		return 0, ErrInsufficientInputs
 	}
 
 	available := inputAmount - outputAmount
 	size, err := tx.EstimateSizeWithTypes()
 	if err != nil {
-		return 0, false, err
+		return 0, err
 	}
 	stdFee, err := f.Fee(FeeTypeStandard)
 	if err != nil {
-		return 0, false, err
+		return 0, err
 	}
 	dataFee, err := f.Fee(FeeTypeData)
 	if err != nil {
-		return 0, false, err
+		return 0, err
 	}
 	varIntUpper := VarInt(tx.OutputCount()).UpperLimitInc()
 	if varIntUpper == -1 {
-		return 0, false, nil
+		return 0, nil
 	}
 	changeOutputFee := varIntUpper
-	changeP2pkhByteLen := uint64(0)
-	if output != nil && output.newOutput {
-		changeP2pkhByteLen = uint64(8 + 1 + 25)
-	}
-
-	sFees := (size.TotalStdBytes + changeP2pkhByteLen) * uint64(stdFee.MiningFee.Satoshis) / uint64(stdFee.MiningFee.Bytes)
-	dFees := size.TotalDataBytes * uint64(dataFee.MiningFee.Satoshis) / uint64(dataFee.MiningFee.Bytes)
-	txFees := sFees + dFees + uint64(changeOutputFee)
 
-	// not enough to add change, no change to add
-	if available <= txFees || available-txFees <= DustLimit {
-		return 0, false, nil
+	sFees, err := mulDivSatoshis(size.TotalStdBytes+changeBytes, uint64(stdFee.MiningFee.Satoshis), uint64(stdFee.MiningFee.Bytes))
+	if err != nil {
+		return 0, err
+	}
+	dFees, err := mulDivSatoshis(size.TotalDataBytes, uint64(dataFee.MiningFee.Satoshis), uint64(dataFee.MiningFee.Bytes))
+	if err != nil {
+		return 0, err
+	}
+	txFees, err := addSatoshis(sFees, dFees)
+	if err != nil {
+		return 0, err
+	}
+	if txFees, err = addSatoshis(txFees, uint64(changeOutputFee)); err != nil {
+		return 0, err
 	}
 
-	// if we want to add to a new output, set
-	// newOutput to true, this will add the calculated change
-	// into a new output
-	available -= txFees
-	if output != nil && output.newOutput {
-		tx.AddOutput(&Output{Satoshis: available, LockingScript: output.lockingScript})
+	if available <= txFees {
+		return 0, nil
 	}
-	return available, true, nil
+	return available - txFees, nil
 }
@@ -0,0 +1,132 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+)
+
+// ChunkedDataMarker identifies an OP_RETURN output created by AddChunkedData.
+var ChunkedDataMarker = []byte("chunk")
+
+// chunkOverhead is the largest number of bytes, other than the chunk itself,
+// in a chunked data locking script: OP_FALSE OP_RETURN, the marker push,
+// the two 4 byte index pushes and the OP_PUSHDATA4 header of the chunk.
+const chunkOverhead = 2 + 1 + 5 + 1 + 4 + 1 + 4 + 5
+
+// AddChunkedData splits data into chunks of at most chunkSize bytes and adds
+// each one to the tx as its own zero value OP_RETURN output, so data that is too
+// large for a single output can still be stored. The outputs are added in order.
+//
+// Each output has the form
+//
+//	OP_FALSE OP_RETURN "chunk" <index> <total> <chunk>
+//
+// where index and total are 4 byte big endian integers: index is the zero based
+// position of the chunk and total is the number of chunks. ReassembleChunkedData
+// relies on these, rather than output order, to rebuild the data.
+//
+// ErrInvalidChunkSize is returned if chunkSize is not positive or would make an
+// output exceed DefaultMaxScriptPubKeySize.
+func (tx *Tx) AddChunkedData(data []byte, chunkSize int) error {
+	if chunkSize <= 0 || chunkSize > DefaultMaxScriptPubKeySize-chunkOverhead {
+		return fmt.Errorf("%w: %d", ErrInvalidChunkSize, chunkSize)
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("%w: no data", ErrInvalidChunkSize)
+	}
+
+	total := (len(data) + chunkSize - 1) / chunkSize
+	outputs := make([]*Output, 0, total)
+	for i := 0; i < total; i++ {
+		end := (i + 1) * chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		index := make([]byte, 4)
+		binary.BigEndian.PutUint32(index, uint32(i))
+		count := make([]byte, 4)
+		binary.BigEndian.PutUint32(count, uint32(total))
+
+		o, err := CreateOpReturnOutput([][]byte{ChunkedDataMarker, index, count, data[i*chunkSize : end]})
+		if err != nil {
+			return err
+		}
+		outputs = append(outputs, o)
+	}
+
+	tx.Outputs = append(tx.Outputs, outputs...)
+	return nil
+}
+
+// ReassembleChunkedData rebuilds data previously stored with AddChunkedData.
+// Outputs which are not chunked data are ignored. Chunks are joined by their
+// index, so the outputs may appear in any order.
+//
+// ErrNoChunkedData is returned if the tx holds no chunks, and ErrChunkedDataCorrupt
+// if a chunk is missing, duplicated, out of range or disagrees on the number of
+// chunks, or claims more chunks than the tx has outputs.
+func ReassembleChunkedData(tx *Tx) ([]byte, error) {
+	var chunks [][]byte
+	total := -1
+	for i, o := range tx.Outputs {
+		index, count, chunk, ok := parseChunk(o)
+		if !ok {
+			continue
+		}
+		if total == -1 {
+			// the count is untrusted, so never allocate for more chunks than
+			// there are outputs to hold them
+			if count > len(tx.Outputs) {
+				return nil, fmt.Errorf("%w: output %d expects %d chunks, but the tx has %d outputs",
+					ErrChunkedDataCorrupt, i, count, len(tx.Outputs))
+			}
+			total = count
+			chunks = make([][]byte, total)
+		}
+		if count != total {
+			return nil, fmt.Errorf("%w: output %d expects %d chunks, not %d", ErrChunkedDataCorrupt, i, count, total)
+		}
+		if index >= total || chunks[index] != nil {
+			return nil, fmt.Errorf("%w: output %d has unexpected index %d", ErrChunkedDataCorrupt, i, index)
+		}
+		chunks[index] = chunk
+	}
+
+	if total == -1 {
+		return nil, ErrNoChunkedData
+	}
+	for i, c := range chunks {
+		if c == nil {
+			return nil, fmt.Errorf("%w: chunk %d of %d missing", ErrChunkedDataCorrupt, i, total)
+		}
+	}
+
+	return bytes.Join(chunks, nil), nil
+}
+
+func parseChunk(o *Output) (int, int, []byte, bool) {
+	if !o.IsDataCarrier() {
+		return 0, 0, nil, false
+	}
+
+	ls := *o.LockingScript
+	if ls[0] == bscript.OpFALSE {
+		ls = ls[1:]
+	}
+	parts, err := bscript.DecodeParts(ls[1:])
+	if err != nil || len(parts) != 4 || !bytes.Equal(parts[0], ChunkedDataMarker) ||
+		len(parts[1]) != 4 || len(parts[2]) != 4 {
+		return 0, 0, nil, false
+	}
+
+	index := int(binary.BigEndian.Uint32(parts[1]))
+	count := int(binary.BigEndian.Uint32(parts[2]))
+	if count == 0 {
+		return 0, 0, nil, false
+	}
+	return index, count, parts[3], true
+}
@@ -0,0 +1,96 @@
+package transaction_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_AddChunkedData(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte("0123456789"), 100)
+
+	t.Run("round trip", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+		assert.NoError(t, tx.AddOpReturnOutput([]byte("unrelated")))
+		assert.NoError(t, tx.AddChunkedData(data, 300))
+		assert.Equal(t, 6, tx.OutputCount())
+		for _, o := range tx.Outputs[2:] {
+			assert.True(t, o.IsDataCarrier())
+			assert.Zero(t, o.Satoshis)
+		}
+
+		tx, err := transaction.NewTxFromBytes(tx.Bytes())
+		assert.NoError(t, err)
+
+		got, err := transaction.ReassembleChunkedData(tx)
+		assert.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("out of order outputs", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.AddChunkedData(data, 99))
+		tx.Outputs[0], tx.Outputs[len(tx.Outputs)-1] = tx.Outputs[len(tx.Outputs)-1], tx.Outputs[0]
+
+		got, err := transaction.ReassembleChunkedData(tx)
+		assert.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+		assert.NoError(t, tx.AddChunkedData(data, 300))
+		tx.Outputs = append(tx.Outputs[:1], tx.Outputs[2:]...)
+
+		_, err := transaction.ReassembleChunkedData(tx)
+		assert.ErrorIs(t, err, transaction.ErrChunkedDataCorrupt)
+	})
+
+	t.Run("forged chunk count", func(t *testing.T) {
+		index := []byte{0x00, 0x00, 0x00, 0x00}
+		o, err := transaction.CreateOpReturnOutput([][]byte{transaction.ChunkedDataMarker, index,
+			[]byte{0xff, 0xff, 0xff, 0xff}, []byte("data")})
+		assert.NoError(t, err)
+		tx := transaction.NewTx()
+		tx.AddOutput(o)
+
+		_, err = transaction.ReassembleChunkedData(tx)
+		assert.ErrorIs(t, err, transaction.ErrChunkedDataCorrupt)
+	})
+
+	t.Run("forged chunk index", func(t *testing.T) {
+		tx := transaction.NewTx()
+		for _, index := range [][]byte{{0x00, 0x00, 0x00, 0x00}, {0xff, 0xff, 0xff, 0xff}} {
+			o, err := transaction.CreateOpReturnOutput([][]byte{transaction.ChunkedDataMarker, index,
+				[]byte{0x00, 0x00, 0x00, 0x02}, []byte("data")})
+			assert.NoError(t, err)
+			tx.AddOutput(o)
+		}
+
+		_, err := transaction.ReassembleChunkedData(tx)
+		assert.ErrorIs(t, err, transaction.ErrChunkedDataCorrupt)
+	})
+
+	t.Run("no chunks", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.AddOpReturnOutput([]byte("unrelated")))
+
+		_, err := transaction.ReassembleChunkedData(tx)
+		assert.ErrorIs(t, err, transaction.ErrNoChunkedData)
+	})
+
+	t.Run("invalid chunk size", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.ErrorIs(t, tx.AddChunkedData(data, 0), transaction.ErrInvalidChunkSize)
+		assert.ErrorIs(t, tx.AddChunkedData(data, transaction.DefaultMaxScriptPubKeySize),
+			transaction.ErrInvalidChunkSize)
+		assert.ErrorIs(t, tx.AddChunkedData(nil, 10), transaction.ErrInvalidChunkSize)
+		assert.Zero(t, tx.OutputCount())
+	})
+}
@@ -0,0 +1,108 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/pkg/errors"
+)
+
+// CollaborativeBuilder builds a tx whose inputs are owned by different parties,
+// such as in escrow or marketplace trades. Inputs are either local, and signed by
+// SignLocal, or external, and signed by their owner who returns the unlocking
+// script through ContributeInput.
+//
+// All inputs and outputs should be added before any input is signed, as the
+// default ALL|FORKID sighash commits to every input and output.
+type CollaborativeBuilder struct {
+	tx    *Tx
+	local map[uint32]bool
+}
+
+// NewCollaborativeBuilder returns a CollaborativeBuilder for an empty tx.
+func NewCollaborativeBuilder() *CollaborativeBuilder {
+	return &CollaborativeBuilder{
+		tx:    NewTx(),
+		local: make(map[uint32]bool),
+	}
+}
+
+// Tx returns the tx being built, for sharing with the other parties so they can
+// sign their inputs. It should not be modified.
+func (b *CollaborativeBuilder) Tx() *Tx {
+	return b.tx
+}
+
+// AddLocalInput adds an input owned by the local signer, returning its index.
+func (b *CollaborativeBuilder) AddLocalInput(in *Input) uint32 {
+	b.tx.addInput(in)
+	idx := uint32(len(b.tx.Inputs) - 1)
+	b.local[idx] = true
+	return idx
+}
+
+// AddExternalInput adds an input owned by another party, returning its index.
+// The input must carry its previous tx script and satoshis so that the
+// contributed signature can be checked.
+func (b *CollaborativeBuilder) AddExternalInput(in *Input) uint32 {
+	b.tx.addInput(in)
+	return uint32(len(b.tx.Inputs) - 1)
+}
+
+// AddOutput adds an output to the tx.
+func (b *CollaborativeBuilder) AddOutput(o *Output) {
+	b.tx.AddOutput(o)
+}
+
+// SignLocal fills the unlocking script of every local input using the UnlockerGetter.
+//
+// Any error returned is annotated with the index of the input that failed.
+func (b *CollaborativeBuilder) SignLocal(ctx context.Context, ug UnlockerGetter) error {
+	for i, in := range b.tx.Inputs {
+		idx := uint32(i)
+		if !b.local[idx] {
+			continue
+		}
+		u, err := ug.Unlocker(ctx, in.PreviousTxScript)
+		if err != nil {
+			return errors.Wrapf(err, "input %d", i)
+		}
+		if err = b.tx.FillInput(ctx, u, UnlockerParams{InputIdx: idx}); err != nil {
+			return errors.Wrapf(err, "input %d", i)
+		}
+	}
+
+	return nil
+}
+
+// ContributeInput sets the unlocking script for the external input at idx after
+// checking its signature against the input's sighash preimage. Only P2PKH and
+// P2PK inputs can be checked, so ErrUnsupportedScriptType is returned for any other
+// previous script type.
+//
+// ErrInputNoExist is returned for an unknown index, ErrInputNotExternal for a local
+// input and ErrInvalidSignature if the signature does not verify. The input is left
+// unchanged on error.
+func (b *CollaborativeBuilder) ContributeInput(idx uint32, unlockingScript *bscript.Script) error {
+	if int(idx) >= len(b.tx.Inputs) {
+		return fmt.Errorf("%w: %d", ErrInputNoExist, idx)
+	}
+	if b.local[idx] {
+		return fmt.Errorf("%w: %d", ErrInputNotExternal, idx)
+	}
+
+	return b.tx.insertVerifiedUnlockingScript(idx, unlockingScript)
+}
+
+// Finalize returns a copy of the completed tx. ErrInputNotSigned is returned,
+// detailing the input index, if any input has no unlocking script.
+func (b *CollaborativeBuilder) Finalize() (*Tx, error) {
+	for i, in := range b.tx.Inputs {
+		if in.UnlockingScriptSize() == 0 {
+			return nil, fmt.Errorf("%w: input %d", ErrInputNotSigned, i)
+		}
+	}
+
+	return b.tx.Clone(), nil
+}
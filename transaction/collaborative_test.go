@@ -0,0 +1,87 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollaborativeBuilder(t *testing.T) {
+	t.Parallel()
+
+	newInput := func(t *testing.T, priv *ec.PrivateKey, txid string, sats uint64) *transaction.Input {
+		s, err := bscript.NewP2PKHFromPubKeyEC(priv.PubKey())
+		assert.NoError(t, err)
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.From(txid, 0, s.String(), sats))
+		return tx.Inputs[0]
+	}
+
+	setup := func(t *testing.T) (*transaction.CollaborativeBuilder, *ec.PrivateKey, *ec.PrivateKey) {
+		buyer, err := ec.NewPrivateKey()
+		assert.NoError(t, err)
+		seller, err := ec.NewPrivateKey()
+		assert.NoError(t, err)
+
+		b := transaction.NewCollaborativeBuilder()
+		assert.Equal(t, uint32(0), b.AddLocalInput(newInput(t, buyer, "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 10000)))
+		assert.Equal(t, uint32(1), b.AddExternalInput(newInput(t, seller, "45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 1)))
+		s, err := bscript.NewP2PKHFromPubKeyEC(buyer.PubKey())
+		assert.NoError(t, err)
+		b.AddOutput(&transaction.Output{Satoshis: 9900, LockingScript: s})
+		return b, buyer, seller
+	}
+
+	externalSign := func(t *testing.T, tx *transaction.Tx, priv *ec.PrivateKey) *bscript.Script {
+		s, err := (&unlocker.Simple{PrivateKey: priv}).UnlockingScript(context.Background(), tx.Clone(),
+			transaction.UnlockerParams{InputIdx: 1})
+		assert.NoError(t, err)
+		return s
+	}
+
+	t.Run("both parties sign", func(t *testing.T) {
+		b, buyer, seller := setup(t)
+		assert.NoError(t, b.SignLocal(context.Background(), &unlocker.Getter{PrivateKey: buyer}))
+
+		_, err := b.Finalize()
+		assert.ErrorIs(t, err, transaction.ErrInputNotSigned)
+
+		assert.NoError(t, b.ContributeInput(1, externalSign(t, b.Tx(), seller)))
+
+		tx, err := b.Finalize()
+		assert.NoError(t, err)
+		assert.NoError(t, tx.VerifyInputSignatures())
+	})
+
+	t.Run("wrong key is rejected", func(t *testing.T) {
+		b, buyer, _ := setup(t)
+
+		err := b.ContributeInput(1, externalSign(t, b.Tx(), buyer))
+		assert.ErrorIs(t, err, transaction.ErrInvalidSignature)
+		assert.Zero(t, b.Tx().Inputs[1].UnlockingScriptSize())
+	})
+
+	t.Run("local input cannot be contributed", func(t *testing.T) {
+		b, _, seller := setup(t)
+
+		err := b.ContributeInput(0, externalSign(t, b.Tx(), seller))
+		assert.ErrorIs(t, err, transaction.ErrInputNotExternal)
+		assert.ErrorIs(t, b.ContributeInput(2, nil), transaction.ErrInputNoExist)
+	})
+
+	t.Run("unsupported script type", func(t *testing.T) {
+		b, _, seller := setup(t)
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 1, "51", 1))
+		idx := b.AddExternalInput(tx.Inputs[0])
+
+		err := b.ContributeInput(idx, externalSign(t, b.Tx(), seller))
+		assert.ErrorIs(t, err, transaction.ErrUnsupportedScriptType)
+		assert.Zero(t, b.Tx().Inputs[idx].UnlockingScriptSize())
+	})
+}
@@ -22,4 +22,29 @@ const (
 	// SequenceLockTimeMask is a mask that extracts the relative locktime
 	// when masked against the transaction input sequence number.
 	SequenceLockTimeMask = 0x0000ffff
+
+	// MinVersionRelativeLockTime is the lowest tx version in which input sequence
+	// numbers are interpreted as relative timelocks, as set out by BIP 68.
+	MinVersionRelativeLockTime uint32 = 2
+
+	// LockTimeThreshold is the number below which a lock time is
+	// interpreted to be a block height. At or above this value it is
+	// interpreted as a unix timestamp (Tue Nov  5 00:53:20 1985 UTC).
+	LockTimeThreshold uint32 = 500000000
+
+	// MaxSatoshis is the total supply of 21 million BSV, in satoshis, which no
+	// single output value can exceed.
+	MaxSatoshis uint64 = 21000000 * 100000000
+)
+
+const (
+	// DefaultMaxScriptSigSize is the default maximum size, in bytes, of an
+	// input unlocking script (scriptSig) accepted by BSV node policy
+	// (-maxscriptsizepolicy).
+	DefaultMaxScriptSigSize = 500 * 1000
+
+	// DefaultMaxScriptPubKeySize is the default maximum size, in bytes, of an
+	// output locking script (scriptPubKey) accepted by BSV node policy
+	// (-maxscriptsizepolicy).
+	DefaultMaxScriptPubKeySize = 500 * 1000
 )
@@ -0,0 +1,120 @@
+package transaction
+
+import "fmt"
+
+// DepGraph is the graph of spend relationships within a set of txs, with an edge
+// from each tx to every other tx in the set whose outputs it spends. Txs outside
+// the set are not part of the graph.
+//
+// It is built by BuildDependencyGraph and is not modified afterwards, so it is
+// safe for concurrent use as long as the txs are not modified.
+type DepGraph struct {
+	txs   []*Tx
+	index map[string]int
+	// parents lists the txs each tx spends from, and children the txs spending
+	// each tx, each without duplicates and in the order first spent.
+	parents  [][]int
+	children [][]int
+}
+
+// BuildDependencyGraph builds the DepGraph of txs, where each input spending an
+// output of another tx in txs makes the spending tx depend on it.
+//
+// An ErrDuplicateTx error is returned if a txid appears more than once, and
+// ErrTxNil if any tx is nil.
+func BuildDependencyGraph(txs []*Tx) (*DepGraph, error) {
+	g := &DepGraph{
+		txs:      txs,
+		index:    make(map[string]int, len(txs)),
+		parents:  make([][]int, len(txs)),
+		children: make([][]int, len(txs)),
+	}
+	for i, tx := range txs {
+		if tx == nil {
+			return nil, fmt.Errorf("%w: tx %d", ErrTxNil, i)
+		}
+		id := tx.TxID()
+		if _, ok := g.index[id]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateTx, id)
+		}
+		g.index[id] = i
+	}
+
+	for i, tx := range txs {
+		seen := make(map[int]bool)
+		for _, in := range tx.Inputs {
+			p, ok := g.index[in.PreviousTxIDStr()]
+			if !ok || seen[p] {
+				continue
+			}
+			seen[p] = true
+			g.parents[i] = append(g.parents[i], p)
+			g.children[p] = append(g.children[p], i)
+		}
+	}
+
+	return g, nil
+}
+
+// TopologicalOrder returns the txs ordered so that each tx comes after every tx
+// in the graph whose outputs it spends, as they must be broadcast. Txs not
+// depending on each other keep the order they were given in.
+//
+// An ErrBatchCycle error is returned if the txs depend on each other in a cycle,
+// which can only happen if their previous txids have been tampered with.
+func (g *DepGraph) TopologicalOrder() ([]*Tx, error) {
+	pending := make([]int, len(g.txs))
+	for i := range g.txs {
+		pending[i] = len(g.parents[i])
+	}
+
+	order := make([]*Tx, 0, len(g.txs))
+	done := make([]bool, len(g.txs))
+	for len(order) < len(g.txs) {
+		next := -1
+		for i := range g.txs {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, ErrBatchCycle
+		}
+		done[next] = true
+		order = append(order, g.txs[next])
+		for _, c := range g.children[next] {
+			pending[c]--
+		}
+	}
+
+	return order, nil
+}
+
+// Ancestors returns every tx in the graph the tx with txid depends on, directly
+// or through other txs in the graph, such as the package of unconfirmed parents
+// a CPFP child pays for. They are ordered so that each comes after its own
+// ancestors. Nil is returned if txid is not in the graph.
+func (g *DepGraph) Ancestors(txid string) []*Tx {
+	i, ok := g.index[txid]
+	if !ok {
+		return nil
+	}
+
+	ancestors := make([]*Tx, 0)
+	visited := map[int]bool{i: true}
+	var visit func(int)
+	visit = func(n int) {
+		for _, p := range g.parents[n] {
+			if visited[p] {
+				continue
+			}
+			visited[p] = true
+			visit(p)
+			ancestors = append(ancestors, g.txs[p])
+		}
+	}
+	visit(i)
+
+	return ancestors
+}
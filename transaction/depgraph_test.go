@@ -0,0 +1,92 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDependencyGraph(t *testing.T) {
+	t.Parallel()
+
+	const script = "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac"
+	// spend returns a tx spending output 0 of each prevTxID and paying two outputs.
+	spend := func(t *testing.T, prevTxIDs ...string) *Tx {
+		tx := NewTx()
+		for _, id := range prevTxIDs {
+			assert.NoError(t, tx.From(id, 0, script, 1000))
+		}
+		assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 400))
+		assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 400))
+		return tx
+	}
+	ids := func(txs []*Tx) []string {
+		s := make([]string, len(txs))
+		for i, tx := range txs {
+			s[i] = tx.TxID()
+		}
+		return s
+	}
+
+	t.Run("chain", func(t *testing.T) {
+		a := spend(t, "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+		b := spend(t, a.TxID())
+		c := spend(t, b.TxID())
+
+		g, err := BuildDependencyGraph([]*Tx{c, b, a})
+		assert.NoError(t, err)
+		order, err := g.TopologicalOrder()
+		assert.NoError(t, err)
+		assert.Equal(t, ids([]*Tx{a, b, c}), ids(order))
+
+		assert.Equal(t, ids([]*Tx{a, b}), ids(g.Ancestors(c.TxID())))
+		assert.Empty(t, g.Ancestors(a.TxID()))
+		assert.Nil(t, g.Ancestors("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d"))
+	})
+
+	t.Run("diamond", func(t *testing.T) {
+		// a is spent by b and c, which are both spent by d
+		a := spend(t, "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+		b := NewTx()
+		assert.NoError(t, b.From(a.TxID(), 0, script, 400))
+		assert.NoError(t, b.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 300))
+		c := NewTx()
+		assert.NoError(t, c.From(a.TxID(), 1, script, 400))
+		assert.NoError(t, c.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 300))
+		d := spend(t, b.TxID(), c.TxID())
+		unrelated := spend(t, "45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d")
+
+		g, err := BuildDependencyGraph([]*Tx{d, c, unrelated, b, a})
+		assert.NoError(t, err)
+		order, err := g.TopologicalOrder()
+		assert.NoError(t, err)
+		assert.Equal(t, ids([]*Tx{unrelated, a, c, b, d}), ids(order))
+
+		assert.Equal(t, ids([]*Tx{a, b, c}), ids(g.Ancestors(d.TxID())))
+		assert.Equal(t, ids([]*Tx{a}), ids(g.Ancestors(c.TxID())))
+		assert.Empty(t, g.Ancestors(unrelated.TxID()))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		// A real cycle needs a txid preimage, so wire one up by hand.
+		a := spend(t, "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+		b := spend(t, a.TxID())
+		g := &DepGraph{
+			txs:      []*Tx{a, b},
+			index:    map[string]int{a.TxID(): 0, b.TxID(): 1},
+			parents:  [][]int{{1}, {0}},
+			children: [][]int{{1}, {0}},
+		}
+		_, err := g.TopologicalOrder()
+		assert.ErrorIs(t, err, ErrBatchCycle)
+		assert.Equal(t, ids([]*Tx{a}), ids(g.Ancestors(b.TxID())))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		a := spend(t, "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+		_, err := BuildDependencyGraph([]*Tx{a, a})
+		assert.ErrorIs(t, err, ErrDuplicateTx)
+		_, err = BuildDependencyGraph([]*Tx{a, nil})
+		assert.ErrorIs(t, err, ErrTxNil)
+	})
+}
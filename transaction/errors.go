@@ -1,17 +1,42 @@
 package transaction
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
 
 // General errors.
 var (
 	ErrInvalidTxID       = errors.New("invalid TxID")
 	ErrTxNil             = errors.New("tx is nil")
 	ErrTxTooShort        = errors.New("too short to be a tx - even an empty tx has 10 bytes")
+	ErrMalformedTx       = errors.New("malformed tx")
+	ErrTxTooManyInputs   = errors.New("declared input count exceeds the remaining bytes")
+	ErrTxTooManyOutputs  = errors.New("declared output count exceeds the remaining bytes")
 	ErrNLockTimeLength   = errors.New("nLockTime length must be 4 bytes long")
 	ErrEmptyValues       = errors.New("empty value or values passed, all arguments are required and cannot be empty")
 	ErrUnsupportedScript = errors.New("non-P2PKH input used in the tx - unsupported")
 	ErrInvalidScriptType = errors.New("invalid script type")
 	ErrNoUnlocker        = errors.New("unlocker not supplied")
+	ErrScriptTooLarge    = errors.New("script exceeds the maximum size")
+
+	// ErrTooManyDataOutputs is returned when a tx has more data outputs than a
+	// miner policy allows.
+	ErrTooManyDataOutputs = errors.New("too many data outputs")
+
+	// ErrLockTimeIneffective is returned when a tx sets a locktime but every input
+	// is final, so the locktime is ignored.
+	ErrLockTimeIneffective = errors.New("locktime is ignored as every input sequence is final")
+
+	// ErrTxVersionTooLow is returned when a tx's version is below the minimum
+	// required by policy or by the features it uses.
+	ErrTxVersionTooLow = errors.New("tx version is too low")
+
+	// ErrUnsupportedScriptType is returned when an unlocker cannot build an
+	// unlocking script for the type of the previous locking script.
+	ErrUnsupportedScriptType = errors.New("unsupported script type")
 )
 
 // Sentinal errors reported by inputs.
@@ -22,12 +47,87 @@ var (
 	// You should not be able to spend an input with 0 Satoshi value.
 	// Most likely the input Satoshi value is not provided.
 	ErrInputSatsZero = errors.New("input satoshi value is not provided")
+
+	// ErrInputConflict is returned by Merge when both transactions spend the same
+	// outpoint but disagree on the previous output or sequence number.
+	ErrInputConflict = errors.New("conflicting inputs spend the same outpoint")
+
+	// ErrInputNotSigned is returned when an input has no unlocking script.
+	ErrInputNotSigned = errors.New("input is not signed")
+
+	// ErrInputNotExternal is returned by CollaborativeBuilder.ContributeInput when
+	// the input is owned by the local signer.
+	ErrInputNotExternal = errors.New("input is not external")
+
+	// ErrNotP2PKHUnlockingScript is returned when an input's unlocking script is not
+	// a standard P2PKH signature and public key push pair.
+	ErrNotP2PKHUnlockingScript = errors.New("unlocking script is not a p2pkh signature and public key")
+
+	// ErrKeyMismatch is returned when a public key cannot unlock the output an
+	// input spends.
+	ErrKeyMismatch = errors.New("public key does not match input's locking script")
+)
+
+// Sentinel errors reported by the Builder.
+var (
+	// ErrBuilderOrder is returned when a Builder step is taken out of order.
+	ErrBuilderOrder = errors.New("builder operation out of order")
+)
+
+// Sentinel errors reported by satoshi arithmetic.
+var (
+	// ErrSatoshiOverflow is returned when a satoshi total or fee overflows a uint64.
+	// Legitimate amounts are bounded by the 21M coin supply, so this signals malformed data.
+	ErrSatoshiOverflow = errors.New("satoshi amount overflows")
+)
+
+// Sentinel errors reported by UTXOs.
+var (
+	ErrInvalidUTXO = errors.New("invalid utxo")
 )
 
 // Sentinal errors reported by outputs.
 var (
 	ErrOutputNoExist  = errors.New("specified output does not exist")
 	ErrOutputTooShort = errors.New("output length too short")
+
+	// ErrZeroValueOutput is returned when a spendable output is given zero satoshis.
+	// Only data carrier (OP_RETURN) outputs may be zero value.
+	ErrZeroValueOutput = errors.New("zero satoshi output is not a data carrier")
+
+	// ErrDustOutput is returned when a spendable output is below the dust threshold.
+	ErrDustOutput = errors.New("output is dust")
+
+	// ErrInvalidSplitCount is returned by SplitOutput when the count is less than one.
+	ErrInvalidSplitCount = errors.New("split count must be at least one")
+
+	// ErrNonStandardScript is returned when an output's locking script is required
+	// to be standard but is not.
+	ErrNonStandardScript = errors.New("locking script is not standard")
+
+	// ErrInvalidAddressNetwork is returned when an address is not a P2PKH address
+	// for mainnet or testnet, or its checksum does not match.
+	ErrInvalidAddressNetwork = errors.New("address is not a valid mainnet or testnet P2PKH address")
+
+	// ErrOutputExceedsMaxSatoshis is returned when an output's value is greater
+	// than MaxSatoshis.
+	ErrOutputExceedsMaxSatoshis = errors.New("output value exceeds the 21M BSV supply")
+
+	// ErrNilLockingScript is returned when an output has no locking script.
+	ErrNilLockingScript = errors.New("output locking script is nil")
+)
+
+// Sentinel errors reported by chunked data.
+var (
+	ErrInvalidChunkSize   = errors.New("invalid chunk size")
+	ErrNoChunkedData      = errors.New("no chunked data found")
+	ErrChunkedDataCorrupt = errors.New("chunked data is incomplete or corrupt")
+)
+
+// Sentinel errors reported by AIP signed data.
+var (
+	ErrNoAIPData           = errors.New("output does not contain AIP signed data")
+	ErrInvalidAIPSignature = errors.New("invalid AIP signature")
 )
 
 // Sentinal errors reported by change.
@@ -41,6 +141,11 @@ var (
 	ErrEmptyPreviousTxScript = errors.New("'PreviousTxScript' not supplied")
 )
 
+// Sentinel errors reported by signature verification.
+var (
+	ErrInvalidSignature = errors.New("input signature is invalid")
+)
+
 // Sentinel errors reported by the fees.
 var (
 	ErrFeeQuotesNotInit = errors.New("feeQuotes have not been setup, call NewFeeQuotes")
@@ -48,6 +153,24 @@ var (
 	ErrFeeTypeNotFound  = errors.New("feetype not found")
 	ErrFeeQuoteNotInit  = errors.New("feeQuote has not been initialised, call NewFeeQuote()")
 	ErrUnknownFeeType   = errors.New("unknown fee type")
+
+	// ErrMAPISignature is returned when a mAPI envelope signature is missing or invalid.
+	ErrMAPISignature = errors.New("invalid mapi envelope signature")
+
+	// ErrMAPIPayload is returned when a mAPI envelope or its fee quote payload cannot be parsed.
+	ErrMAPIPayload = errors.New("invalid mapi fee quote payload")
+
+	// ErrFeeOutOfRange is returned by AssertFeeWithin when the fee paid is outside
+	// the tolerance of the expected fee.
+	ErrFeeOutOfRange = errors.New("fee paid is outside the expected range")
+
+	// ErrInvalidFeeTolerance is returned by AssertFeeWithin when the tolerance is
+	// negative or not a number.
+	ErrInvalidFeeTolerance = errors.New("fee tolerance must be a non-negative percentage")
+
+	// ErrNotFeeRejection is returned by SuggestBumpedFeeQuote when a broadcast was
+	// rejected for a reason other than its fee.
+	ErrNotFeeRejection = errors.New("rejection is not fee related")
 )
 
 // Sentinel errors reported by Fund.
@@ -57,6 +180,151 @@ var (
 
 	// ErrInsufficientFunds insufficient funds provided for funding
 	ErrInsufficientFunds = errors.New("insufficient funds provided")
+
+	// ErrFundCancelled is returned when the context passed to Fund is done before
+	// the tx is funded. It wraps the context error, so errors.Is(err, context.Canceled)
+	// also holds.
+	ErrFundCancelled = errors.New("funding cancelled")
+
+	// ErrNoChangeScript is returned when Fund is asked to add change without a change script.
+	ErrNoChangeScript = errors.New("auto change requires a change script")
+)
+
+// Sentinel errors reported by BatchBroadcast and DepGraph.
+var (
+	// ErrBroadcastFailed is returned when a tx in a batch fails to broadcast.
+	ErrBroadcastFailed = errors.New("broadcast failed")
+
+	// ErrBatchCycle is returned when the txs in a batch or dependency graph depend
+	// on each other in a cycle.
+	ErrBatchCycle = errors.New("batch txs depend on each other in a cycle")
+
+	// ErrDuplicateTx is returned when the same tx appears more than once in a batch
+	// or dependency graph.
+	ErrDuplicateTx = errors.New("duplicate tx in batch")
+)
+
+// InsufficientFundsError is returned by Fund when the UTXOGetterFunc is exhausted
+// before the outputs and fees are covered, detailing the shortfall.
+// It matches ErrInsufficientFunds with errors.Is.
+type InsufficientFundsError struct {
+	// Required is the satoshis needed to cover the outputs and estimated fees.
+	Required uint64
+	// Available is the satoshis provided by the inputs.
+	Available uint64
+}
+
+// Shortfall returns how many more satoshis are needed.
+func (e *InsufficientFundsError) Shortfall() uint64 {
+	return e.Required - e.Available
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("%s: %d satoshis required, %d available, %d more needed",
+		ErrInsufficientFunds, e.Required, e.Available, e.Shortfall())
+}
+
+// Unwrap returns ErrInsufficientFunds.
+func (e *InsufficientFundsError) Unwrap() error {
+	return ErrInsufficientFunds
+}
+
+// FeeOutOfRangeError is returned by AssertFeeWithin when the fee paid by a tx is
+// not within the tolerance of the fee expected from the FeeQuote.
+// It matches ErrFeeOutOfRange with errors.Is.
+type FeeOutOfRangeError struct {
+	// Actual is the fee paid, the input satoshis less the output satoshis.
+	Actual uint64
+	// Expected is the fee the FeeQuote charges for the tx.
+	Expected uint64
+	// TolerancePercent is the allowed difference, as a percentage of Expected.
+	TolerancePercent float64
+}
+
+func (e *FeeOutOfRangeError) Error() string {
+	return fmt.Sprintf("%s: paid %d satoshis, expected %d within %g%%",
+		ErrFeeOutOfRange, e.Actual, e.Expected, e.TolerancePercent)
+}
+
+// Unwrap returns ErrFeeOutOfRange.
+func (e *FeeOutOfRangeError) Unwrap() error {
+	return ErrFeeOutOfRange
+}
+
+// KeyMismatchError is returned by CheckKeyForInput when the public key does not
+// match the one the input's previous locking script pays, detailing both hashes.
+// It matches ErrKeyMismatch with errors.Is.
+type KeyMismatchError struct {
+	// InputIdx is the index of the input checked.
+	InputIdx uint32
+	// Expected is the hash160 of the public key the locking script pays.
+	Expected []byte
+	// Actual is the hash160 of the compressed public key given.
+	Actual []byte
+}
+
+func (e *KeyMismatchError) Error() string {
+	return fmt.Sprintf("%s: input %d expects key hash %x, got %x", ErrKeyMismatch, e.InputIdx, e.Expected, e.Actual)
+}
+
+// Unwrap returns ErrKeyMismatch.
+func (e *KeyMismatchError) Unwrap() error {
+	return ErrKeyMismatch
+}
+
+// InputError is an error signing a single input, as collected in InputErrors.
+type InputError struct {
+	// Index is the index of the input which failed.
+	Index int
+	// Err is the error signing it.
+	Err error
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("input %d: %s", e.Index, e.Err)
+}
+
+// Unwrap returns the error signing the input.
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// InputErrors is returned by FillAllInputs when FillOptions.CollectErrors is set,
+// listing every input which failed in index order. errors.Is and errors.As match
+// the error of any of them.
+type InputErrors []*InputError
+
+func (e InputErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d inputs failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the error of each failed input.
+func (e InputErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Sentinel errors reported by BEEF parsing.
+var (
+	// ErrInvalidBEEF is returned when a BEEF is malformed or does not include the
+	// txs or BUMPs its txs reference.
+	ErrInvalidBEEF = errors.New("invalid BEEF")
+)
+
+// Sentinel errors reported by merkle paths.
+var (
+	// ErrInvalidMerklePath is returned when a MerklePath is not structurally valid.
+	ErrInvalidMerklePath = errors.New("invalid merkle path")
+
+	// ErrTxidNotInPath is returned when a txid is not proven by a MerklePath.
+	ErrTxidNotInPath = errors.New("txid not in merkle path")
 )
 
 // Sentinal errors reported by ordinal inscriptions.
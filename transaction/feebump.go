@@ -0,0 +1,126 @@
+package transaction
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultFeeBumpFactor is the factor SuggestBumpedFeeQuote raises fee rates by
+// when the rejection does not say what fee was required.
+const DefaultFeeBumpFactor = 1.5
+
+// feeBumpMargin is added on top of the fee required by a rejection which gives it,
+// allowing for the tx growing or the minimum changing before the retry.
+const feeBumpMargin = 1.1
+
+// feeRejectionPhrases are phrases, in lower case, used by nodes, mAPI and ARC when
+// rejecting a tx for paying too little fee.
+var feeRejectionPhrases = []string{
+	"min fee not met",
+	"min relay fee not met",
+	"minimum fee",
+	"insufficient priority",
+	"insufficient fee",
+	"not enough fee",
+	"fee too low",
+	"fee is too low",
+	"fees too low",
+	"fees are too low",
+	"low fee",
+}
+
+// feeRejectionCode matches the node reject code 66 (insufficient fee) and ARC's
+// status 465 (fee too low) when they start the message or follow a label, such as
+// "66: ...", "ERROR: 66: ..." or "ARC status 465".
+var feeRejectionCode = regexp.MustCompile(`(^|\b(?:error|code|status|arc)\W*)(66|465)\b`)
+
+// feeRejectionAmounts matches the "paid < required" amounts the node appends to
+// fee rejections, such as "mempool min fee not met, 120 < 135".
+var feeRejectionAmounts = regexp.MustCompile(`(\d+)\s*<\s*(\d+)`)
+
+// SuggestBumpedFeeQuote returns a copy of current with every fee rate raised, for
+// retrying a broadcast which was rejected for paying too little fee, where
+// rejectionReason is the message returned by the node, mAPI or ARC.
+//
+// If the rejection gives the fee paid and the fee required, as in "min relay fee
+// not met, 120 < 135", rates are raised in proportion plus a 10% margin. Otherwise
+// they are raised by DefaultFeeBumpFactor. Every rate is raised by at least one
+// satoshi. Matching is case insensitive and tolerant of surrounding text.
+//
+// If the rejection is not about fees, an error matching ErrNotFeeRejection is
+// returned and retrying with a higher fee will not help. A nil current is treated
+// as DefaultFeeQuote.
+func SuggestBumpedFeeQuote(current *FeeQuote, rejectionReason string) (*FeeQuote, error) {
+	factor, ok := feeBumpFactor(rejectionReason)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotFeeRejection, rejectionReason)
+	}
+	if current == nil {
+		current = DefaultFeeQuote()
+	}
+
+	current.mu.RLock()
+	defer current.mu.RUnlock()
+	bumped := &FeeQuote{
+		fees:       make(map[FeeType]*Fee, len(current.fees)),
+		expiryTime: current.expiryTime,
+	}
+	if current.dustThreshold != nil {
+		threshold := *current.dustThreshold
+		bumped.dustThreshold = &threshold
+	}
+	for ft, fee := range current.fees {
+		if fee == nil {
+			continue
+		}
+		bumped.fees[ft] = &Fee{
+			FeeType:   fee.FeeType,
+			MiningFee: bumpFeeUnit(fee.MiningFee, factor),
+			RelayFee:  bumpFeeUnit(fee.RelayFee, factor),
+		}
+	}
+
+	return bumped, nil
+}
+
+// feeBumpFactor returns the factor to raise fee rates by for the rejection, and
+// false if it is not a fee rejection.
+func feeBumpFactor(reason string) (float64, bool) {
+	reason = strings.ToLower(strings.TrimSpace(reason))
+	if reason == "" {
+		return 0, false
+	}
+
+	isFee := feeRejectionCode.MatchString(reason)
+	for _, phrase := range feeRejectionPhrases {
+		if isFee {
+			break
+		}
+		isFee = strings.Contains(reason, phrase)
+	}
+	if !isFee {
+		return 0, false
+	}
+
+	if m := feeRejectionAmounts.FindStringSubmatch(reason); m != nil {
+		paid, errPaid := strconv.ParseFloat(m[1], 64)
+		required, errRequired := strconv.ParseFloat(m[2], 64)
+		if errPaid == nil && errRequired == nil && paid > 0 && required > paid {
+			return required / paid * feeBumpMargin, true
+		}
+	}
+
+	return DefaultFeeBumpFactor, true
+}
+
+func bumpFeeUnit(u FeeUnit, factor float64) FeeUnit {
+	// the epsilon stops float error rounding an exact result up a satoshi
+	satoshis := int(math.Ceil(float64(u.Satoshis)*factor - 1e-9))
+	if satoshis <= u.Satoshis {
+		satoshis = u.Satoshis + 1
+	}
+	return FeeUnit{Satoshis: satoshis, Bytes: u.Bytes}
+}
@@ -0,0 +1,61 @@
+package transaction
+
+import "math"
+
+// Confirmation buckets returned by EstimateConfirmationBucket.
+const (
+	ConfirmationNextBlock  = "next block"
+	ConfirmationWithinHour = "within an hour"
+	ConfirmationLow        = "low"
+	ConfirmationUnknown    = "unknown"
+)
+
+// Fractions of the recently accepted fee rates a rate must be at least as high as
+// to fall in each bucket of EstimateConfirmationBucket.
+const (
+	confirmationNextBlockFraction  = 0.5
+	confirmationWithinHourFraction = 0.1
+)
+
+// EstimateConfirmationBucket gives a rough idea of how soon a tx paying feeRate
+// is likely to be mined, given the fee rates of recently accepted txs, such as
+// those reported by a miner API. Both must be in the same unit, such as satoshis
+// per byte. No network requests are made.
+//
+// The heuristic is deliberately simple: feeRate is ranked against distribution,
+// and if it is at least as high as half of the recent rates it is expected in the
+// next block (ConfirmationNextBlock). If it is at least as high as a tenth of them
+// it is expected within an hour, around six blocks (ConfirmationWithinHour).
+// Otherwise it is ConfirmationLow, and may wait for fees to fall or not be mined.
+//
+// ConfirmationUnknown is returned if distribution holds no valid rates, or
+// feeRate is negative or not a number. Negative and NaN rates in distribution are
+// ignored.
+func EstimateConfirmationBucket(feeRate float64, distribution []float64) string {
+	if feeRate < 0 || math.IsNaN(feeRate) {
+		return ConfirmationUnknown
+	}
+
+	var total, covered int
+	for _, rate := range distribution {
+		if rate < 0 || math.IsNaN(rate) {
+			continue
+		}
+		total++
+		if feeRate >= rate {
+			covered++
+		}
+	}
+	if total == 0 {
+		return ConfirmationUnknown
+	}
+
+	switch fraction := float64(covered) / float64(total); {
+	case fraction >= confirmationNextBlockFraction:
+		return ConfirmationNextBlock
+	case fraction >= confirmationWithinHourFraction:
+		return ConfirmationWithinHour
+	default:
+		return ConfirmationLow
+	}
+}
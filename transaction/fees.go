@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
 )
 
 // FeeType is used to specify which
@@ -117,9 +119,10 @@ func (f *FeeQuotes) UpdateMinerFees(minerName string, feeType FeeType, fee *Fee)
 // When expiry expires ie Expired() == true then you should fetch
 // new quotes from a MAPI server and call AddQuote with the fee information.
 type FeeQuote struct {
-	mu         sync.RWMutex
-	fees       map[FeeType]*Fee
-	expiryTime time.Time
+	mu            sync.RWMutex
+	fees          map[FeeType]*Fee
+	expiryTime    time.Time
+	dustThreshold *uint64
 }
 
 // NewFeeQuote will set up and return a new FeeQuotes struct which
@@ -178,6 +181,59 @@ func NewFeeQuote() *FeeQuote {
 	return fq
 }
 
+// DefaultFeeQuote returns a new FeeQuote using the current BSV standard rate of
+// 5 satoshis per 100 bytes (50 per 1000) for both standard and data bytes, as
+// charged by most BSV miners. The rate was last reviewed against published miner
+// policy in early 2024. It is equivalent to NewFeeQuote.
+func DefaultFeeQuote() *FeeQuote {
+	return NewFeeQuote()
+}
+
+// ZeroFeeQuote returns a new FeeQuote charging no fees at all. This is useful
+// for testing, but txs built with it will not be accepted by miners.
+func ZeroFeeQuote() *FeeQuote {
+	return FeeQuoteFromRate(0)
+}
+
+// FeeQuoteFromRate returns a new FeeQuote charging satsPerKB satoshis per 1000
+// bytes, for both the mining and relay fees of the standard and data fee types.
+func FeeQuoteFromRate(satsPerKB uint64) *FeeQuote {
+	return NewFeeQuoteFromSatsPerKB(satsPerKB, satsPerKB)
+}
+
+// NewFeeQuoteFromSatsPerKB returns a new FeeQuote charging standard satoshis per
+// 1000 bytes for standard bytes and data satoshis per 1000 bytes for data bytes,
+// for both the mining and relay fees. For example, 0.5 satoshis per byte is 500.
+//
+// The rates are held exactly, as satoshis per 1000 bytes, so no rounding happens
+// here. Fees are rounded down to whole satoshis when calculated for a tx.
+func NewFeeQuoteFromSatsPerKB(standard, data uint64) *FeeQuote {
+	fq := &FeeQuote{
+		fees:       map[FeeType]*Fee{},
+		expiryTime: time.Now().UTC(),
+		mu:         sync.RWMutex{},
+	}
+	fq.AddQuote(FeeTypeStandard, newFee(FeeTypeStandard, int(standard), 1000)).
+		AddQuote(FeeTypeData, newFee(FeeTypeData, int(data), 1000))
+	return fq
+}
+
+// SatsPerKB returns the mining fee rate of the fee type in satoshis per 1000
+// bytes, whatever number of bytes the quote was given for. Rates which do not
+// convert exactly, such as 1 satoshi per 3 bytes, are rounded to the nearest
+// satoshi, with halves rounded up.
+//
+// Zero is returned if the fee type is not in the quote or its byte count is not
+// positive.
+func (f *FeeQuote) SatsPerKB(feeType FeeType) uint64 {
+	fee, err := f.Fee(feeType)
+	if err != nil || fee.MiningFee.Bytes <= 0 || fee.MiningFee.Satoshis <= 0 {
+		return 0
+	}
+	sats, bytes := uint64(fee.MiningFee.Satoshis), uint64(fee.MiningFee.Bytes)
+	return (sats*1000 + bytes/2) / bytes
+}
+
 // Fee will return a fee by type if found, nil and an error if not.
 func (f *FeeQuote) Fee(t FeeType) (*Fee, error) {
 	if f == nil {
@@ -201,6 +257,98 @@ func (f *FeeQuote) AddQuote(ft FeeType, fee *Fee) *FeeQuote {
 	return f
 }
 
+// dustSpendSize is the size in bytes of a P2PKH output (34 bytes) plus the size
+// of the input needed to later spend it (148 bytes), as used by StandardDustThreshold.
+const dustSpendSize = 34 + 148
+
+// DustThreshold returns the minimum satoshi value an output must have to not be
+// considered dust, as consulted when funding, adding change and paying to outputs.
+//
+// It is StandardDustThreshold unless overridden with SetDustThreshold.
+func (f *FeeQuote) DustThreshold() uint64 {
+	f.mu.RLock()
+	threshold := f.dustThreshold
+	f.mu.RUnlock()
+	if threshold != nil {
+		return *threshold
+	}
+	return f.StandardDustThreshold()
+}
+
+// StandardDustThreshold returns the dust threshold derived from the standard relay
+// fee with the standard formula, the value below which an output costs more in fees
+// to spend than it is worth:
+//
+//	3 * (34 + 148) * relayFee.Satoshis / relayFee.Bytes
+//
+// where 34 is the size of a P2PKH output and 148 the size of the input spending it.
+// The threshold is never lower than DustLimit.
+func (f *FeeQuote) StandardDustThreshold() uint64 {
+	fee, err := f.Fee(FeeTypeStandard)
+	if err != nil || fee.RelayFee.Bytes <= 0 || fee.RelayFee.Satoshis <= 0 {
+		return DustLimit
+	}
+	threshold := 3 * dustSpendSize * uint64(fee.RelayFee.Satoshis) / uint64(fee.RelayFee.Bytes)
+	if threshold < DustLimit {
+		return DustLimit
+	}
+	return threshold
+}
+
+// MarginalInputFee returns the fee fq's standard mining rate charges for the bytes
+// one more signed P2PKH input adds to a tx: 148 bytes, being the 36 byte outpoint,
+// the unlocking script estimated as by EstimateSize with its length, and the 4 byte
+// sequence number. Coin selection can weigh it against a utxo's value to decide
+// whether spending the utxo is worthwhile.
+//
+// The fee of a whole tx is rounded down, so adding the input can raise it by one
+// satoshi more than this. If fq has no standard fee the default standard rate of
+// NewFeeQuote is used, so an input is never treated as free.
+func MarginalInputFee(fq *FeeQuote) uint64 {
+	fee, err := inputFee(fq, p2pkhUnlockingScriptSize)
+	if err != nil {
+		fee, _ = inputFee(NewFeeQuote(), p2pkhUnlockingScriptSize)
+	}
+	return fee
+}
+
+// MarginalInputFeeForScript returns the fee for one more signed input spending an
+// output locked by lockingScript, as MarginalInputFee does for P2PKH, with the
+// unlocking script size estimated as by EstimateSize for P2PKH, P2PK and multisig.
+//
+// An ErrUnsupportedScript error is returned for other script types, and an error
+// if fq has no standard fee.
+func MarginalInputFeeForScript(fq *FeeQuote, lockingScript *bscript.Script) (uint64, error) {
+	if lockingScript == nil {
+		return 0, ErrEmptyPreviousTxScript
+	}
+	size, err := estimateUnlockingScriptSize(lockingScript)
+	if err != nil {
+		return 0, err
+	}
+	return inputFee(fq, size)
+}
+
+// inputFee returns the standard mining fee for an input with an unlocking script
+// of unlockingScriptSize bytes.
+func inputFee(fq *FeeQuote, unlockingScriptSize int) (uint64, error) {
+	fee, err := fq.Fee(FeeTypeStandard)
+	if err != nil {
+		return 0, err
+	}
+	size := 32 + 4 + uint64(VarInt(unlockingScriptSize).Length()) + uint64(unlockingScriptSize) + 4
+	return mulDivSatoshis(size, uint64(fee.MiningFee.Satoshis), uint64(fee.MiningFee.Bytes))
+}
+
+// SetDustThreshold overrides the default dust threshold of DustLimit with the
+// provided value, see DustThreshold.
+func (f *FeeQuote) SetDustThreshold(n uint64) *FeeQuote {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.dustThreshold = &n
+	return f
+}
+
 // Expiry will return the expiry timestamp for the `bt.FeeQuote` in a threadsafe manner.
 func (f *FeeQuote) Expiry() time.Time {
 	f.mu.RLock()
@@ -292,31 +440,27 @@ type Fee struct {
 // defaultStandardFee returns the default
 // standard fees offered by most miners.
 func defaultStandardFee() *Fee {
-	return &Fee{
-		FeeType: FeeTypeStandard,
-		MiningFee: FeeUnit{
-			Satoshis: 5,
-			Bytes:    100,
-		},
-		RelayFee: FeeUnit{
-			Satoshis: 5,
-			Bytes:    100,
-		},
-	}
+	return newFee(FeeTypeStandard, 5, 100)
 }
 
 // defaultDataFee returns the default
 // data fees offered by most miners.
 func defaultDataFee() *Fee {
+	return newFee(FeeTypeData, 5, 100)
+}
+
+// newFee returns a fee of the given type where both the mining
+// and relay fees charge satoshis for every number of bytes.
+func newFee(ft FeeType, satoshis, bytes int) *Fee {
 	return &Fee{
-		FeeType: FeeTypeData,
+		FeeType: ft,
 		MiningFee: FeeUnit{
-			Satoshis: 5,
-			Bytes:    100,
+			Satoshis: satoshis,
+			Bytes:    bytes,
 		},
 		RelayFee: FeeUnit{
-			Satoshis: 5,
-			Bytes:    100,
+			Satoshis: satoshis,
+			Bytes:    bytes,
 		},
 	}
 }
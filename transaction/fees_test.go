@@ -3,10 +3,13 @@ package transaction
 import (
 	"encoding/json"
 	"errors"
+	"math"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/bitcoin-sv/go-sdk/bscript"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -635,3 +638,533 @@ func TestFeeQuote_MarshalUnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestFeeQuote_Presets(t *testing.T) {
+	tests := map[string]struct {
+		fq       *FeeQuote
+		expSats  int
+		expBytes int
+	}{
+		"default fee quote": {
+			fq:       DefaultFeeQuote(),
+			expSats:  5,
+			expBytes: 100,
+		},
+		"zero fee quote": {
+			fq:       ZeroFeeQuote(),
+			expSats:  0,
+			expBytes: 1000,
+		},
+		"fee quote from rate": {
+			fq:       FeeQuoteFromRate(250),
+			expSats:  250,
+			expBytes: 1000,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, ft := range []FeeType{FeeTypeStandard, FeeTypeData} {
+				fee, err := test.fq.Fee(ft)
+				assert.NoError(t, err)
+				assert.Equal(t, ft, fee.FeeType)
+				assert.Equal(t, test.expSats, fee.MiningFee.Satoshis)
+				assert.Equal(t, test.expBytes, fee.MiningFee.Bytes)
+				assert.Equal(t, fee.MiningFee, fee.RelayFee)
+			}
+		})
+	}
+}
+
+func TestFeeQuote_DustThreshold(t *testing.T) {
+	t.Run("default rate", func(t *testing.T) {
+		assert.Equal(t, uint64(27), NewFeeQuote().DustThreshold())
+	})
+
+	t.Run("1000 sats per kb gives the classic dust limit", func(t *testing.T) {
+		assert.Equal(t, uint64(546), FeeQuoteFromRate(1000).DustThreshold())
+	})
+
+	t.Run("zero rate falls back to the dust limit", func(t *testing.T) {
+		assert.Equal(t, uint64(DustLimit), ZeroFeeQuote().DustThreshold())
+	})
+
+	t.Run("override", func(t *testing.T) {
+		fq := NewFeeQuote().SetDustThreshold(100)
+		assert.Equal(t, uint64(100), fq.DustThreshold())
+	})
+
+	t.Run("change below the threshold is not added", func(t *testing.T) {
+		tx := NewTx()
+		assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 1000))
+		assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 900))
+
+		assert.NoError(t, tx.ChangeToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", NewFeeQuote().SetDustThreshold(100)))
+		assert.Equal(t, 1, tx.OutputCount())
+
+		assert.NoError(t, tx.ChangeToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", NewFeeQuote().SetDustThreshold(1)))
+		assert.Equal(t, 2, tx.OutputCount())
+	})
+
+	t.Run("near dust remainder goes to fees", func(t *testing.T) {
+		tx := NewTx()
+		assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 912))
+		assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 900))
+
+		change, ok, err := tx.CalculateChange(DefaultFeeQuote())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.NotZero(t, change)
+
+		assert.NoError(t, tx.ChangeToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", DefaultFeeQuote()))
+		assert.Equal(t, 1, tx.OutputCount())
+		assert.Equal(t, uint64(12), tx.TotalInputSatoshis()-tx.TotalOutputSatoshis())
+	})
+}
+
+func TestTx_CalculateChange(t *testing.T) {
+	tests := map[string]struct {
+		pay           uint64
+		dustThreshold uint64
+		expOK         bool
+		expDust       bool
+		expErr        error
+	}{
+		"change matches the change added": {
+			pay:   500,
+			expOK: true,
+		},
+		"dust change": {
+			pay:           900,
+			dustThreshold: 100,
+			expDust:       true,
+		},
+		"fees not covered": {
+			pay: 999,
+		},
+		"outputs exceed inputs": {
+			pay:    1001,
+			expErr: ErrInsufficientInputs,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := NewTx()
+			assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 1000))
+			assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", test.pay))
+			fq := NewFeeQuote()
+			if test.dustThreshold > 0 {
+				fq.SetDustThreshold(test.dustThreshold)
+			}
+
+			change, ok, err := tx.CalculateChange(fq)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expOK, ok)
+			if test.expDust {
+				assert.Greater(t, change, uint64(0))
+				assert.LessOrEqual(t, change, test.dustThreshold)
+			} else if !test.expOK {
+				assert.Zero(t, change)
+			}
+
+			assert.NoError(t, tx.ChangeToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", fq))
+			if test.expOK {
+				assert.Equal(t, 2, tx.OutputCount())
+				assert.Equal(t, change, tx.Outputs[1].Satoshis)
+			} else {
+				assert.Equal(t, 1, tx.OutputCount())
+			}
+		})
+	}
+}
+
+func TestTx_SatoshiOverflow(t *testing.T) {
+	overflowFee := NewFeeQuote().AddQuote(FeeTypeStandard, &Fee{
+		FeeType:   FeeTypeStandard,
+		MiningFee: FeeUnit{Satoshis: math.MaxInt, Bytes: 1},
+	})
+	tests := map[string]struct {
+		inputSats  []uint64
+		outputSats []uint64
+		fn         func(tx *Tx) error
+	}{
+		"input total": {
+			inputSats: []uint64{math.MaxUint64, 2},
+			fn: func(tx *Tx) error {
+				_, err := tx.TotalInputSatoshisChecked()
+				return err
+			},
+		},
+		"output total": {
+			inputSats:  []uint64{1000},
+			outputSats: []uint64{math.MaxUint64},
+			fn: func(tx *Tx) error {
+				_, err := tx.TotalOutputSatoshisChecked()
+				return err
+			},
+		},
+		"fee paid enough": {
+			inputSats: []uint64{math.MaxUint64, 2},
+			fn: func(tx *Tx) error {
+				_, err := tx.IsFeePaidEnough(NewFeeQuote())
+				return err
+			},
+		},
+		"calculate change": {
+			inputSats: []uint64{math.MaxUint64, 2},
+			fn: func(tx *Tx) error {
+				_, _, err := tx.CalculateChange(NewFeeQuote())
+				return err
+			},
+		},
+		"estimate deficit": {
+			inputSats: []uint64{math.MaxUint64, 2},
+			fn: func(tx *Tx) error {
+				_, err := tx.estimateDeficit(NewFeeQuote(), 0)
+				return err
+			},
+		},
+		"fee rate": {
+			inputSats: []uint64{1000},
+			fn: func(tx *Tx) error {
+				_, err := tx.EstimateFeesPaid(overflowFee)
+				return err
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := NewTx()
+			for i, sats := range test.inputSats {
+				assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", uint32(i), "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", sats))
+			}
+			assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 500))
+			for _, sats := range test.outputSats {
+				tx.AddOutput(&Output{Satoshis: sats, LockingScript: tx.Outputs[0].LockingScript})
+			}
+
+			assert.ErrorIs(t, test.fn(tx), ErrSatoshiOverflow)
+			assert.False(t, tx.Explain(nil).OK())
+		})
+	}
+
+	t.Run("unchecked totals wrap", func(t *testing.T) {
+		tx := NewTx()
+		assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", math.MaxUint64))
+		assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 1, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 2))
+		assert.Equal(t, uint64(1), tx.TotalInputSatoshis())
+	})
+}
+
+func TestMulDivSatoshis(t *testing.T) {
+	v, err := mulDivSatoshis(math.MaxUint64, 10, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64/2), v)
+
+	_, err = mulDivSatoshis(math.MaxUint64, 2, 1)
+	assert.ErrorIs(t, err, ErrSatoshiOverflow)
+}
+
+func TestTx_AssertFeeWithin(t *testing.T) {
+	fq := FeeQuoteFromRate(50000)
+	tests := map[string]struct {
+		changeDelta int64
+		noInputSats bool
+		tolerance   float64
+		expErr      error
+	}{
+		"change within tolerance": {
+			tolerance: 1,
+		},
+		"change lost to fees": {
+			changeDelta: -5000,
+			tolerance:   10,
+			expErr:      ErrFeeOutOfRange,
+		},
+		"change lost to fees within a large tolerance": {
+			changeDelta: -5000,
+			tolerance:   100,
+		},
+		"underpaid": {
+			changeDelta: 5000,
+			tolerance:   10,
+			expErr:      ErrFeeOutOfRange,
+		},
+		"input satoshis missing": {
+			noInputSats: true,
+			tolerance:   10,
+			expErr:      ErrInputSatsZero,
+		},
+		"negative tolerance": {
+			tolerance: -1,
+			expErr:    ErrInvalidFeeTolerance,
+		},
+		"NaN tolerance": {
+			tolerance: math.NaN(),
+			expErr:    ErrInvalidFeeTolerance,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := NewTx()
+			assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 100000))
+			assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 50000))
+			assert.NoError(t, tx.ChangeToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", fq))
+			expected, err := tx.EstimateFeesPaid(fq)
+			assert.NoError(t, err)
+
+			tx.Outputs[1].Satoshis = uint64(int64(tx.Outputs[1].Satoshis) + test.changeDelta)
+			if test.noInputSats {
+				tx.Inputs[0].PreviousTxSatoshis = 0
+			}
+
+			err = tx.AssertFeeWithin(fq, test.tolerance)
+			assert.ErrorIs(t, err, test.expErr)
+			var feeErr *FeeOutOfRangeError
+			if errors.As(err, &feeErr) {
+				assert.Equal(t, expected.TotalFeePaid, feeErr.Expected)
+				assert.Equal(t, uint64(int64(expected.TotalFeePaid)-test.changeDelta), feeErr.Actual)
+			}
+		})
+	}
+}
+
+func TestTx_FeeBreakdown(t *testing.T) {
+	tx := NewTx()
+	assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 100000))
+	tx.Inputs[0].UnlockingScript = bscript.NewFromBytes(make([]byte, 107))
+	assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 500))
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+
+	// version 4 + input count 1 + input 148 + output count 1 + p2pkh output 34
+	// + data output 17 + locktime 4 = 209 bytes, of which the 8 bytes of data
+	// output script (OP_FALSE OP_RETURN OP_DATA_5 hello) are data.
+	size := tx.SizeWithTypes()
+	assert.Equal(t, uint64(209), size.TotalBytes)
+	assert.Equal(t, uint64(8), size.TotalDataBytes)
+
+	fq := NewFeeQuote().
+		AddQuote(FeeTypeStandard, newFee(FeeTypeStandard, 100, 1000)).
+		AddQuote(FeeTypeData, newFee(FeeTypeData, 1000, 10))
+
+	std, data, err := tx.FeeBreakdown(fq)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(201*100/1000), std)
+	assert.Equal(t, uint64(8*1000/10), data)
+	assert.Equal(t, uint64(820), std+data)
+
+	fees, err := tx.feesPaid(size, fq)
+	assert.NoError(t, err)
+	assert.Equal(t, fees.TotalFeePaid, std+data)
+
+	_, _, err = tx.FeeBreakdown(&FeeQuote{})
+	assert.ErrorIs(t, err, ErrFeeTypeNotFound)
+}
+
+func TestSuggestBumpedFeeQuote(t *testing.T) {
+	current := FeeQuoteFromRate(100)
+
+	t.Run("fee rejections", func(t *testing.T) {
+		for _, reason := range []string{
+			"66: mempool min fee not met",
+			"ERROR: 66: insufficient priority",
+			"Transaction below minimum fee",
+			"ARC error 465: Fee too low",
+			"  Not enough fees  ",
+			"status 465",
+		} {
+			bumped, err := SuggestBumpedFeeQuote(current, reason)
+			assert.NoError(t, err, reason)
+			for _, ft := range []FeeType{FeeTypeStandard, FeeTypeData} {
+				fee, err := bumped.Fee(ft)
+				assert.NoError(t, err)
+				assert.Equal(t, FeeUnit{Satoshis: 150, Bytes: 1000}, fee.MiningFee, reason)
+				assert.Equal(t, FeeUnit{Satoshis: 150, Bytes: 1000}, fee.RelayFee, reason)
+			}
+		}
+
+		fee, err := current.Fee(FeeTypeStandard)
+		assert.NoError(t, err)
+		assert.Equal(t, 100, fee.MiningFee.Satoshis)
+	})
+
+	t.Run("required fee given", func(t *testing.T) {
+		bumped, err := SuggestBumpedFeeQuote(current, "66: min relay fee not met, 100 < 200")
+		assert.NoError(t, err)
+		fee, err := bumped.Fee(FeeTypeStandard)
+		assert.NoError(t, err)
+		assert.Equal(t, 220, fee.MiningFee.Satoshis)
+	})
+
+	t.Run("zero rate", func(t *testing.T) {
+		bumped, err := SuggestBumpedFeeQuote(ZeroFeeQuote().SetDustThreshold(1), "fee too low")
+		assert.NoError(t, err)
+		fee, err := bumped.Fee(FeeTypeStandard)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, fee.MiningFee.Satoshis)
+		assert.Equal(t, uint64(1), bumped.DustThreshold())
+	})
+
+	t.Run("nil quote", func(t *testing.T) {
+		bumped, err := SuggestBumpedFeeQuote(nil, "insufficient fee")
+		assert.NoError(t, err)
+		fee, err := bumped.Fee(FeeTypeStandard)
+		assert.NoError(t, err)
+		assert.Equal(t, FeeUnit{Satoshis: 8, Bytes: 100}, fee.MiningFee)
+	})
+
+	t.Run("not fee related", func(t *testing.T) {
+		for _, reason := range []string{
+			"",
+			"18: txn-mempool-conflict",
+			"16: mandatory-script-verify-flag-failed",
+			"Missing inputs",
+			"txn-already-known 66a3f0",
+		} {
+			_, err := SuggestBumpedFeeQuote(current, reason)
+			assert.ErrorIs(t, err, ErrNotFeeRejection, reason)
+		}
+	})
+}
+
+func TestEstimateConfirmationBucket(t *testing.T) {
+	t.Parallel()
+
+	// 20 recent rates from 0.05 to 1 sat/byte
+	distribution := make([]float64, 20)
+	for i := range distribution {
+		distribution[i] = float64(i+1) * 0.05
+	}
+
+	tests := map[string]struct {
+		feeRate      float64
+		distribution []float64
+		exp          string
+	}{
+		"above every rate":      {feeRate: 2, distribution: distribution, exp: ConfirmationNextBlock},
+		"median":                {feeRate: 0.5, distribution: distribution, exp: ConfirmationNextBlock},
+		"just below median":     {feeRate: 0.49, distribution: distribution, exp: ConfirmationWithinHour},
+		"tenth":                 {feeRate: 0.1, distribution: distribution, exp: ConfirmationWithinHour},
+		"below a tenth":         {feeRate: 0.09, distribution: distribution, exp: ConfirmationLow},
+		"zero":                  {feeRate: 0, distribution: distribution, exp: ConfirmationLow},
+		"empty distribution":    {feeRate: 1, exp: ConfirmationUnknown},
+		"only invalid rates":    {feeRate: 1, distribution: []float64{-1, math.NaN()}, exp: ConfirmationUnknown},
+		"invalid rates ignored": {feeRate: 1, distribution: []float64{-1, 0.5, 2}, exp: ConfirmationNextBlock},
+		"negative fee rate":     {feeRate: -1, distribution: distribution, exp: ConfirmationUnknown},
+		"not a number fee rate": {feeRate: math.NaN(), distribution: distribution, exp: ConfirmationUnknown},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.exp, EstimateConfirmationBucket(test.feeRate, test.distribution))
+		})
+	}
+}
+
+func TestNewFeeQuoteFromSatsPerKB(t *testing.T) {
+	t.Parallel()
+
+	fq := NewFeeQuoteFromSatsPerKB(500, 250)
+	assert.Equal(t, uint64(500), fq.SatsPerKB(FeeTypeStandard))
+	assert.Equal(t, uint64(250), fq.SatsPerKB(FeeTypeData))
+
+	std, err := fq.Fee(FeeTypeStandard)
+	assert.NoError(t, err)
+	assert.Equal(t, FeeUnit{Satoshis: 500, Bytes: 1000}, std.MiningFee)
+	assert.Equal(t, FeeUnit{Satoshis: 500, Bytes: 1000}, std.RelayFee)
+
+	// 0.5 sat/byte, with odd sizes rounded down
+	tx := NewTx()
+	assert.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 0,
+		"76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac", 1000))
+	assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 500))
+	fees, err := tx.EstimateFeesPaid(fq)
+	assert.NoError(t, err)
+	size, err := tx.EstimateSize()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(size/2), fees.TotalFeePaid)
+
+	t.Run("converts other byte counts", func(t *testing.T) {
+		assert.Equal(t, uint64(50), DefaultFeeQuote().SatsPerKB(FeeTypeStandard))
+		assert.Equal(t, uint64(50), FeeQuoteFromRate(50).SatsPerKB(FeeTypeData))
+
+		fq := NewFeeQuote().
+			AddQuote(FeeTypeStandard, newFee(FeeTypeStandard, 1, 3)).
+			AddQuote(FeeTypeData, newFee(FeeTypeData, 1, 2000))
+		assert.Equal(t, uint64(333), fq.SatsPerKB(FeeTypeStandard))
+		assert.Equal(t, uint64(1), fq.SatsPerKB(FeeTypeData))
+	})
+
+	t.Run("missing or zero", func(t *testing.T) {
+		assert.Zero(t, NewFeeQuoteFromSatsPerKB(0, 0).SatsPerKB(FeeTypeStandard))
+		assert.Zero(t, NewFeeQuoteFromSatsPerKB(1, 1).SatsPerKB(FeeType("other")))
+		var nilQuote *FeeQuote
+		assert.Zero(t, nilQuote.SatsPerKB(FeeTypeStandard))
+	})
+}
+
+func TestMarginalInputFee(t *testing.T) {
+	t.Parallel()
+
+	p2pkh, err := bscript.NewP2PKHFromAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk")
+	assert.NoError(t, err)
+
+	t.Run("p2pkh", func(t *testing.T) {
+		assert.Equal(t, uint64(148), MarginalInputFee(NewFeeQuoteFromSatsPerKB(1000, 1000)))
+		assert.Equal(t, uint64(7), MarginalInputFee(NewFeeQuote())) // 148 bytes at 5 sats per 100 bytes
+
+		fee, err := MarginalInputFeeForScript(NewFeeQuoteFromSatsPerKB(1000, 1000), p2pkh)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(148), fee)
+	})
+
+	t.Run("no standard fee uses the default rate", func(t *testing.T) {
+		fq := NewFeeQuote()
+		delete(fq.fees, FeeTypeStandard)
+		_, err := fq.Fee(FeeTypeStandard)
+		assert.ErrorIs(t, err, ErrFeeTypeNotFound)
+		assert.Equal(t, uint64(7), MarginalInputFee(fq))
+		assert.Equal(t, uint64(7), MarginalInputFee(&FeeQuote{}))
+	})
+
+	t.Run("matches the size estimate", func(t *testing.T) {
+		fq := NewFeeQuoteFromSatsPerKB(1000, 1000)
+		tx := NewTx()
+		assert.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 0, p2pkh.String(), 1000))
+		assert.NoError(t, tx.PayTo(p2pkh, 500))
+		before, err := tx.EstimateSize()
+		assert.NoError(t, err)
+		assert.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 1, p2pkh.String(), 1000))
+		after, err := tx.EstimateSize()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(after-before), MarginalInputFee(fq))
+	})
+
+	t.Run("other script types", func(t *testing.T) {
+		fq := NewFeeQuoteFromSatsPerKB(1000, 1000)
+		pubKey := "02" + strings.Repeat("11", 32)
+
+		p2pk, err := bscript.NewFromASM(pubKey + " OP_CHECKSIG")
+		assert.NoError(t, err)
+		fee, err := MarginalInputFeeForScript(fq, p2pk)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(32+4+1+73+4), fee)
+
+		multisig, err := bscript.NewFromASM("OP_2 " + pubKey + " " + pubKey + " " + pubKey + " OP_3 OP_CHECKMULTISIG")
+		assert.NoError(t, err)
+		fee, err = MarginalInputFeeForScript(fq, multisig)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(32+4+1+(1+2*73)+4), fee)
+
+		data, err := bscript.NewFromASM("OP_FALSE OP_RETURN 68656c6c6f")
+		assert.NoError(t, err)
+		_, err = MarginalInputFeeForScript(fq, data)
+		assert.ErrorIs(t, err, ErrUnsupportedScript)
+		_, err = MarginalInputFeeForScript(fq, nil)
+		assert.ErrorIs(t, err, ErrEmptyPreviousTxScript)
+		_, err = MarginalInputFeeForScript(&FeeQuote{}, p2pkh)
+		assert.ErrorIs(t, err, ErrFeeTypeNotFound)
+	})
+}
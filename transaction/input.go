@@ -1,12 +1,16 @@
 package transaction
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/sighash"
 	"github.com/bitcoin-sv/go-sdk/util"
 	"github.com/pkg/errors"
 )
@@ -36,6 +40,10 @@ type Input struct {
 	UnlockingScript    *bscript.Script
 	PreviousTxOutIndex uint32
 	SequenceNumber     uint32
+	// EstimatedUnlockingScriptSize, if non-zero, overrides the unlocking script size
+	// assumed for this input by the size and fee estimates while it is unsigned.
+	// It is not serialised.
+	EstimatedUnlockingScriptSize uint32
 }
 
 // ReadFrom reads from the `io.Reader` into the `bt.Input`.
@@ -74,8 +82,7 @@ func (i *Input) readFrom(r io.Reader, extended bool) (int64, error) {
 		return bytesRead, err
 	}
 
-	scriptBytes := make([]byte, l)
-	n, err = io.ReadFull(r, scriptBytes)
+	scriptBytes, n, err := readBytes(r, l)
 	bytesRead += int64(n)
 	if err != nil {
 		return bytesRead, errors.Wrapf(err, "script(%d): got %d bytes", l, n)
@@ -111,8 +118,7 @@ func (i *Input) readFrom(r io.Reader, extended bool) (int64, error) {
 			return bytesRead, err
 		}
 
-		scriptBytes := make([]byte, scriptLen)
-		n, err := io.ReadFull(r, scriptBytes)
+		scriptBytes, n, err := readBytes(r, scriptLen)
 		bytesRead += int64(n)
 		if err != nil {
 			return bytesRead, errors.Wrapf(err, "script(%d): got %d bytes", scriptLen.Length(), n)
@@ -139,10 +145,17 @@ func (i *Input) PreviousTxIDAdd(txID []byte) error {
 
 // PreviousTxIDAddStr will validate and add the supplied txID string to the Input,
 // if it isn't a valid transaction id an ErrInvalidTxID error will be returned.
+//
+// The txID is expected as displayed by explorers and returned by TxID(). It is
+// reversed into wire order when the Input is serialised, so should not be reversed
+// by the caller.
 func (i *Input) PreviousTxIDAddStr(txID string) error {
 	bb, err := hex.DecodeString(txID)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: '%s' is not hex: %s", ErrInvalidTxID, txID, err)
+	}
+	if !IsValidTxID(bb) {
+		return fmt.Errorf("%w: expected 32 bytes, got %d", ErrInvalidTxID, len(bb))
 	}
 	return i.PreviousTxIDAdd(bb)
 }
@@ -157,22 +170,142 @@ func (i *Input) PreviousTxIDStr() string {
 	return hex.EncodeToString(i.previousTxID)
 }
 
-// String implements the Stringer interface and returns a string
-// representation of a transaction input.
+// Outpoint returns the outpoint spent by the input in the form `txid:vout`, with the
+// txid in display order, as UTXO.Outpoint.
+func (i *Input) Outpoint() string {
+	return fmt.Sprintf("%s:%d", i.PreviousTxIDStr(), i.PreviousTxOutIndex)
+}
+
+// OutpointKey returns the outpoint spent by the input as it is serialised, the txid
+// in wire order followed by the little endian vout. Being an array, it can be used
+// as a map key without allocating.
+func (i *Input) OutpointKey() [36]byte {
+	var k [36]byte
+	copy(k[:32], util.ReverseBytes(i.previousTxID))
+	binary.LittleEndian.PutUint32(k[32:], i.PreviousTxOutIndex)
+	return k
+}
+
+// Equal returns true if other spends the same outpoint with the same unlocking
+// script and sequence number, and has the same previous satoshis and script. Use
+// OutpointKey to compare only the outpoints.
+func (i *Input) Equal(other *Input) bool {
+	if i == nil || other == nil {
+		return i == other
+	}
+	return bytes.Equal(i.previousTxID, other.previousTxID) &&
+		i.PreviousTxOutIndex == other.PreviousTxOutIndex &&
+		i.SequenceNumber == other.SequenceNumber &&
+		i.PreviousTxSatoshis == other.PreviousTxSatoshis &&
+		equalScripts(i.UnlockingScript, other.UnlockingScript) &&
+		equalScripts(i.PreviousTxScript, other.PreviousTxScript)
+}
+
+// equalScripts compares scripts, treating nil as equal only to nil.
+func equalScripts(a, b *bscript.Script) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return bytes.Equal(*a, *b)
+}
+
+// UnlockingScriptSize returns the size in bytes of the unlocking script
+// (scriptSig) of the input, or 0 if it has not been set.
+func (i *Input) UnlockingScriptSize() int {
+	if i.UnlockingScript == nil {
+		return 0
+	}
+	return len(*i.UnlockingScript)
+}
+
+// ExtractSignature returns the DER encoded signature and its sighash flag from a
+// standard P2PKH unlocking script (<sig> <pubkey>). The signature is returned
+// without the trailing sighash byte.
+//
+// ErrNotP2PKHUnlockingScript is returned if the unlocking script is not a
+// P2PKH signature and public key push pair.
+func (i *Input) ExtractSignature() ([]byte, sighash.Flag, error) {
+	sig, _, err := i.p2pkhUnlockingParts()
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, err = ec.ParseDERSignature(sig[:len(sig)-1]); err != nil {
+		return nil, 0, errors.Wrap(ErrNotP2PKHUnlockingScript, err.Error())
+	}
+
+	return sig[:len(sig)-1], sighash.Flag(sig[len(sig)-1]), nil
+}
+
+// ExtractPublicKey returns the signer's public key from a standard P2PKH
+// unlocking script (<sig> <pubkey>).
+//
+// ErrNotP2PKHUnlockingScript is returned if the unlocking script is not a
+// P2PKH signature and public key push pair.
+func (i *Input) ExtractPublicKey() (*ec.PublicKey, error) {
+	_, pubKeyBytes, err := i.p2pkhUnlockingParts()
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := ec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return nil, errors.Wrap(ErrNotP2PKHUnlockingScript, err.Error())
+	}
+
+	return pubKey, nil
+}
+
+// p2pkhUnlockingParts splits a P2PKH unlocking script into its signature, with
+// the sighash byte, and public key pushes.
+func (i *Input) p2pkhUnlockingParts() ([]byte, []byte, error) {
+	if i.UnlockingScript == nil || len(*i.UnlockingScript) == 0 {
+		return nil, nil, ErrNotP2PKHUnlockingScript
+	}
+	parts, err := bscript.DecodeParts(*i.UnlockingScript)
+	if err != nil {
+		return nil, nil, errors.Wrap(ErrNotP2PKHUnlockingScript, err.Error())
+	}
+	if len(parts) != 2 || len(parts[0]) < 2 || len(parts[1]) == 0 {
+		return nil, nil, ErrNotP2PKHUnlockingScript
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// String implements the Stringer interface and returns a human-readable
+// representation of a transaction input, with the scripts shown as ASM.
+// The previous satoshis and script are included when they are populated.
 func (i *Input) String() string {
-	return fmt.Sprintf(
-		`prevTxHash:   %s
-prevOutIndex: %d
-scriptLen:    %d
-script:       %s
-sequence:     %x
-`,
-		hex.EncodeToString(i.previousTxID),
-		i.PreviousTxOutIndex,
-		len(*i.UnlockingScript),
-		i.UnlockingScript,
-		i.SequenceNumber,
-	)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "prevTxID:     %s\n", hex.EncodeToString(i.previousTxID))
+	fmt.Fprintf(&sb, "prevOutIndex: %d\n", i.PreviousTxOutIndex)
+	fmt.Fprintf(&sb, "scriptLen:    %d\n", i.UnlockingScriptSize())
+	fmt.Fprintf(&sb, "script:       %s\n", scriptASM(i.UnlockingScript))
+	fmt.Fprintf(&sb, "sequence:     %x (%s)\n", i.SequenceNumber, sequenceDescription(i.SequenceNumber))
+	if i.PreviousTxScript != nil {
+		fmt.Fprintf(&sb, "prevSatoshis: %d\n", i.PreviousTxSatoshis)
+		fmt.Fprintf(&sb, "prevScript:   %s\n", scriptASM(i.PreviousTxScript))
+	}
+
+	return sb.String()
+}
+
+// sequenceDescription annotates a sequence number: final sequences disable the
+// tx locktime, and sequences below 0xfffffffe signal replaceability.
+func sequenceDescription(seq uint32) string {
+	switch {
+	case seq == MaxTxInSequenceNum:
+		return "final"
+	case seq == MaxTxInSequenceNum-1:
+		return "non-final"
+	default:
+		return "replaceable"
+	}
+}
+
+// scriptASM returns the ASM of s, or an empty string if s is nil.
+func scriptASM(s *bscript.Script) string {
+	asm, _ := s.ToASM()
+	return asm
 }
 
 // Bytes encodes the Input into a hex byte array.
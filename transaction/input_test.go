@@ -5,6 +5,9 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/sighash"
+	"github.com/bitcoin-sv/go-sdk/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,8 +69,167 @@ func TestInput_String(t *testing.T) {
 		assert.Equal(t, int64(148), s)
 
 		assert.Equal(t,
-			"prevTxHash:   6fc75f30a085f3313265b92c818082f9768c13b8a1a107b484023ecf63c86e4c\nprevOutIndex: 1\nscriptLen:    107\nscript:       483045022100f01c1a1679c9437398d691c8497f278fa2d615efc05115688bf2c3335b45c88602201b54437e54fb53bc50545de44ea8c64e9e583952771fcc663c8687dc2638f7854121037e87bbd3b680748a74372640628a8f32d3a841ceeef6f75626ab030c1a04824f\nsequence:     ffffffff\n",
+			"prevTxID:     6fc75f30a085f3313265b92c818082f9768c13b8a1a107b484023ecf63c86e4c\nprevOutIndex: 1\nscriptLen:    107\nscript:       3045022100f01c1a1679c9437398d691c8497f278fa2d615efc05115688bf2c3335b45c88602201b54437e54fb53bc50545de44ea8c64e9e583952771fcc663c8687dc2638f78541 037e87bbd3b680748a74372640628a8f32d3a841ceeef6f75626ab030c1a04824f\nsequence:     ffffffff (final)\n",
 			i.String(),
 		)
 	})
+
+	t.Run("unsigned input with previous output", func(t *testing.T) {
+		tx := NewTx()
+		assert.NoError(t, tx.From("6fc75f30a085f3313265b92c818082f9768c13b8a1a107b484023ecf63c86e4c", 0, "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac", 1000))
+		tx.Inputs[0].SequenceNumber = 0
+
+		assert.Equal(t,
+			"prevTxID:     6fc75f30a085f3313265b92c818082f9768c13b8a1a107b484023ecf63c86e4c\nprevOutIndex: 0\nscriptLen:    0\nscript:       \nsequence:     0 (replaceable)\n"+
+				"prevSatoshis: 1000\nprevScript:   OP_DUP OP_HASH160 eb0bd5edba389198e73f8efabddfc61666969ff7 OP_EQUALVERIFY OP_CHECKSIG\n",
+			tx.Inputs[0].String(),
+		)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			_ = (&Input{}).String()
+		})
+	})
+}
+
+func TestInput_ExtractSignatureAndPublicKey(t *testing.T) {
+	t.Parallel()
+
+	unlocking, err := bscript.NewFromHex("483045022100f01c1a1679c9437398d691c8497f278fa2d615efc05115688bf2c3335b45c88602201b54437e54fb53bc50545de44ea8c64e9e583952771fcc663c8687dc2638f7854121037e87bbd3b680748a74372640628a8f32d3a841ceeef6f75626ab030c1a04824f")
+	assert.NoError(t, err)
+
+	t.Run("signed p2pkh input", func(t *testing.T) {
+		i := &Input{UnlockingScript: unlocking}
+
+		sig, flag, err := i.ExtractSignature()
+		assert.NoError(t, err)
+		assert.Equal(t, "3045022100f01c1a1679c9437398d691c8497f278fa2d615efc05115688bf2c3335b45c88602201b54437e54fb53bc50545de44ea8c64e9e583952771fcc663c8687dc2638f785", hex.EncodeToString(sig))
+		assert.Equal(t, sighash.AllForkID, flag)
+
+		pubKey, err := i.ExtractPublicKey()
+		assert.NoError(t, err)
+		assert.Equal(t, "037e87bbd3b680748a74372640628a8f32d3a841ceeef6f75626ab030c1a04824f", hex.EncodeToString(pubKey.SerialiseCompressed()))
+	})
+
+	t.Run("unsigned input", func(t *testing.T) {
+		i := &Input{}
+
+		_, _, err := i.ExtractSignature()
+		assert.ErrorIs(t, err, ErrNotP2PKHUnlockingScript)
+		_, err = i.ExtractPublicKey()
+		assert.ErrorIs(t, err, ErrNotP2PKHUnlockingScript)
+	})
+
+	t.Run("single push", func(t *testing.T) {
+		s := &bscript.Script{}
+		assert.NoError(t, s.AppendPushData([]byte{0x30, 0x01}))
+		i := &Input{UnlockingScript: s}
+
+		_, _, err := i.ExtractSignature()
+		assert.ErrorIs(t, err, ErrNotP2PKHUnlockingScript)
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		s := &bscript.Script{}
+		assert.NoError(t, s.AppendPushData([]byte{0x30, 0x01}))
+		assert.NoError(t, s.AppendPushData([]byte{0x02, 0x01}))
+		i := &Input{UnlockingScript: s}
+
+		_, err := i.ExtractPublicKey()
+		assert.ErrorIs(t, err, ErrNotP2PKHUnlockingScript)
+		_, _, err = i.ExtractSignature()
+		assert.ErrorIs(t, err, ErrNotP2PKHUnlockingScript)
+	})
+}
+
+func TestInput_UnlockingScriptSize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil unlocking script", func(t *testing.T) {
+		i := &Input{}
+		assert.Equal(t, 0, i.UnlockingScriptSize())
+	})
+
+	t.Run("set unlocking script", func(t *testing.T) {
+		i := &Input{UnlockingScript: bscript.NewFromBytes(make([]byte, 107))}
+		assert.Equal(t, 107, i.UnlockingScriptSize())
+	})
+}
+
+func TestInput_PreviousTxIDAddStr(t *testing.T) {
+	t.Parallel()
+
+	const txID = "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5"
+
+	t.Run("display order txid", func(t *testing.T) {
+		in := &Input{}
+		assert.NoError(t, in.PreviousTxIDAddStr(txID))
+		assert.Equal(t, txID, in.PreviousTxIDStr())
+
+		// serialised in wire (reversed) order
+		display, err := hex.DecodeString(txID)
+		assert.NoError(t, err)
+		in.UnlockingScript = &bscript.Script{}
+		assert.Equal(t, util.ReverseBytes(display), in.Bytes(false)[:32])
+	})
+
+	t.Run("not hex", func(t *testing.T) {
+		err := (&Input{}).PreviousTxIDAddStr("zz")
+		assert.ErrorIs(t, err, ErrInvalidTxID)
+		assert.Contains(t, err.Error(), "not hex")
+	})
+
+	t.Run("wrong length", func(t *testing.T) {
+		err := (&Input{}).PreviousTxIDAddStr("3c8edde2")
+		assert.ErrorIs(t, err, ErrInvalidTxID)
+		assert.Contains(t, err.Error(), "got 4")
+	})
+}
+
+func TestInput_OutpointAndEqual(t *testing.T) {
+	t.Parallel()
+
+	txID, err := hex.DecodeString("6fc75f30a085f3313265b92c818082f9768c13b8a1a107b484023ecf63c86e4c")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac")
+	assert.NoError(t, err)
+	utxo := &UTXO{TxID: txID, Vout: 1, LockingScript: script, Satoshis: 1000}
+
+	tx := NewTx()
+	assert.NoError(t, tx.FromUTXOs(utxo, utxo))
+	a, b := tx.Inputs[0], tx.Inputs[1]
+
+	assert.Equal(t, utxo.Outpoint(), a.Outpoint())
+	assert.True(t, a.Equal(b))
+
+	key := a.OutpointKey()
+	assert.Equal(t, util.ReverseBytes(txID), key[:32])
+	assert.Equal(t, []byte{1, 0, 0, 0}, key[32:])
+
+	t.Run("different sequence", func(t *testing.T) {
+		c := *b
+		c.SequenceNumber = 0
+		assert.False(t, a.Equal(&c))
+		assert.Equal(t, a.OutpointKey(), c.OutpointKey())
+	})
+
+	t.Run("different unlocking script", func(t *testing.T) {
+		c := *b
+		c.UnlockingScript = bscript.NewFromBytes([]byte{0x51})
+		assert.False(t, a.Equal(&c))
+	})
+
+	t.Run("different outpoint", func(t *testing.T) {
+		c := *b
+		c.PreviousTxOutIndex = 2
+		assert.False(t, a.Equal(&c))
+		assert.NotEqual(t, a.OutpointKey(), c.OutpointKey())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var n *Input
+		assert.False(t, a.Equal(nil))
+		assert.True(t, n.Equal(nil))
+	})
 }
@@ -0,0 +1,102 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
+)
+
+// mapiEnvelope is a signed mAPI JSON envelope.
+// See https://github.com/bitcoin-sv-specs/brfc-misc/tree/master/jsonenvelope
+type mapiEnvelope struct {
+	Payload   string  `json:"payload"`
+	Signature *string `json:"signature"`
+	PublicKey *string `json:"publicKey"`
+}
+
+// mapiFeeQuote is the payload of a mAPI fee quote response.
+type mapiFeeQuote struct {
+	ExpiryTime string `json:"expiryTime"`
+	Fees       []*struct {
+		FeeType FeeType `json:"feeType"`
+		*Fee
+	} `json:"fees"`
+}
+
+// ParseMAPIFeeQuote parses a mAPI fee quote response, which is a JSON envelope
+// wrapping the fee quote payload, into a FeeQuote. The envelope signature, a hex
+// encoded DER signature over the sha256 of the payload, is verified against the
+// envelope's public key, and the quote expiry is taken from the payload.
+//
+// ErrMAPISignature is returned if the envelope is unsigned or the signature does
+// not verify, and ErrMAPIPayload if the envelope or payload cannot be parsed.
+func ParseMAPIFeeQuote(envelope []byte) (*FeeQuote, error) {
+	var env mapiEnvelope
+	if err := json.Unmarshal(envelope, &env); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMAPIPayload, err)
+	}
+	if err := env.verify(); err != nil {
+		return nil, err
+	}
+
+	var payload mapiFeeQuote
+	if err := json.Unmarshal([]byte(env.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMAPIPayload, err)
+	}
+
+	fq := NewFeeQuote()
+	for _, f := range payload.Fees {
+		if f == nil || f.Fee == nil {
+			return nil, fmt.Errorf("%w: empty fee", ErrMAPIPayload)
+		}
+		if f.FeeType != FeeTypeStandard && f.FeeType != FeeTypeData {
+			return nil, fmt.Errorf("%w: %w '%s'", ErrMAPIPayload, ErrUnknownFeeType, f.FeeType)
+		}
+		if f.MiningFee.Bytes <= 0 || f.RelayFee.Bytes <= 0 {
+			return nil, fmt.Errorf("%w: %s fee has no bytes", ErrMAPIPayload, f.FeeType)
+		}
+		f.Fee.FeeType = f.FeeType
+		fq.AddQuote(f.FeeType, f.Fee)
+	}
+	if payload.ExpiryTime != "" {
+		exp, err := time.Parse(time.RFC3339, payload.ExpiryTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMAPIPayload, err)
+		}
+		fq.UpdateExpiry(exp)
+	}
+
+	return fq, nil
+}
+
+func (e *mapiEnvelope) verify() error {
+	if e.Signature == nil || e.PublicKey == nil {
+		return fmt.Errorf("%w: envelope is not signed", ErrMAPISignature)
+	}
+
+	pubKeyBytes, err := hex.DecodeString(*e.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMAPISignature, err)
+	}
+	pubKey, err := ec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMAPISignature, err)
+	}
+	sigBytes, err := hex.DecodeString(*e.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMAPISignature, err)
+	}
+	sig, err := ec.ParseDERSignature(sigBytes)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMAPISignature, err)
+	}
+	if !sig.Verify(crypto.Sha256([]byte(e.Payload)), pubKey) {
+		return fmt.Errorf("%w: signature does not verify", ErrMAPISignature)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package transaction_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+const mapiFeeQuotePayload = `{"apiVersion":"1.4.0","timestamp":"2024-01-01T00:00:00Z","expiryTime":"2024-01-01T00:10:00Z",` +
+	`"minerId":"03e92d3e5c3f7bd945dfbf48e7a99393b1bfb3f11f380ae30d286e7ff2aec5a270","currentHighestBlockHeight":800000,` +
+	`"fees":[{"feeType":"standard","miningFee":{"satoshis":1,"bytes":20},"relayFee":{"satoshis":1,"bytes":40}},` +
+	`{"feeType":"data","miningFee":{"satoshis":2,"bytes":30},"relayFee":{"satoshis":1,"bytes":50}}]}`
+
+func TestParseMAPIFeeQuote(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+
+	envelope := func(t *testing.T, payload string, signed string) []byte {
+		sig, err := priv.Sign(crypto.Sha256([]byte(signed)))
+		assert.NoError(t, err)
+		der, err := sig.ToDER()
+		assert.NoError(t, err)
+		b, err := json.Marshal(map[string]interface{}{
+			"payload":   payload,
+			"signature": hex.EncodeToString(der),
+			"publicKey": hex.EncodeToString(priv.PubKey().SerialiseCompressed()),
+			"encoding":  "UTF-8",
+			"mimetype":  "application/json",
+		})
+		assert.NoError(t, err)
+		return b
+	}
+
+	t.Run("valid envelope", func(t *testing.T) {
+		fq, err := transaction.ParseMAPIFeeQuote(envelope(t, mapiFeeQuotePayload, mapiFeeQuotePayload))
+		assert.NoError(t, err)
+
+		std, err := fq.Fee(transaction.FeeTypeStandard)
+		assert.NoError(t, err)
+		assert.Equal(t, transaction.FeeUnit{Satoshis: 1, Bytes: 20}, std.MiningFee)
+		assert.Equal(t, transaction.FeeUnit{Satoshis: 1, Bytes: 40}, std.RelayFee)
+
+		data, err := fq.Fee(transaction.FeeTypeData)
+		assert.NoError(t, err)
+		assert.Equal(t, transaction.FeeTypeData, data.FeeType)
+		assert.Equal(t, transaction.FeeUnit{Satoshis: 2, Bytes: 30}, data.MiningFee)
+
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC), fq.Expiry())
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		tampered := `{"fees":[{"feeType":"standard","miningFee":{"satoshis":0,"bytes":1},"relayFee":{"satoshis":0,"bytes":1}}]}`
+		_, err := transaction.ParseMAPIFeeQuote(envelope(t, tampered, mapiFeeQuotePayload))
+		assert.ErrorIs(t, err, transaction.ErrMAPISignature)
+	})
+
+	t.Run("unsigned envelope", func(t *testing.T) {
+		b, err := json.Marshal(map[string]interface{}{"payload": mapiFeeQuotePayload, "signature": nil, "publicKey": nil})
+		assert.NoError(t, err)
+
+		_, err = transaction.ParseMAPIFeeQuote(b)
+		assert.ErrorIs(t, err, transaction.ErrMAPISignature)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, err := transaction.ParseMAPIFeeQuote(envelope(t, "not json", "not json"))
+		assert.ErrorIs(t, err, transaction.ErrMAPIPayload)
+		assert.NotErrorIs(t, err, transaction.ErrMAPISignature)
+
+		_, err = transaction.ParseMAPIFeeQuote([]byte("{"))
+		assert.ErrorIs(t, err, transaction.ErrMAPIPayload)
+	})
+}
@@ -64,6 +64,89 @@ func NewMerklePath(blockHeight uint32, path [][]*PathElement) *MerklePath {
 	}
 }
 
+// ExtractMerklePath builds the merkle tree of a block from all of its txids, in
+// block order, and returns the MerklePath (BUMP) proving the inclusion of the txid
+// at index. Each txid is expected in display order, as returned by TxIDBytes.
+//
+// The returned path holds the txid and its sibling hashes at each height, and
+// verifies back to the block's merkle root with ComputeRoot. The BlockHeight is
+// left as zero for the caller to set, see NewMerklePathFromBlock.
+func ExtractMerklePath(txids [][]byte, index int) (*MerklePath, error) {
+	if len(txids) == 0 {
+		return nil, errors.New("no txids provided")
+	}
+	if index < 0 || index >= len(txids) {
+		return nil, fmt.Errorf("index %d out of range for %d txids", index, len(txids))
+	}
+
+	level := make([][]byte, len(txids))
+	for i, txid := range txids {
+		if !IsValidTxID(txid) {
+			return nil, fmt.Errorf("txid %d: %w", i, ErrInvalidTxID)
+		}
+		level[i] = util.ReverseBytes(txid)
+	}
+
+	isTxid := true
+	if len(level) == 1 {
+		return NewMerklePath(0, [][]*PathElement{{
+			{Offset: 0, Hash: level[0], Txid: &isTxid},
+		}}), nil
+	}
+
+	path := make([][]*PathElement, 0)
+	offset := uint64(index)
+	for height := 0; len(level) > 1; height++ {
+		elements := make([]*PathElement, 0, 2)
+		if height == 0 {
+			elements = append(elements, &PathElement{Offset: offset, Hash: level[offset], Txid: &isTxid})
+		}
+
+		sibling := offset ^ 1
+		if sibling < uint64(len(level)) {
+			elements = append(elements, &PathElement{Offset: sibling, Hash: level[sibling]})
+		} else {
+			duplicate := true
+			elements = append(elements, &PathElement{Offset: sibling, Duplicate: &duplicate})
+		}
+		sort.Slice(elements, func(i, j int) bool {
+			return elements[i].Offset < elements[j].Offset
+		})
+		path = append(path, elements)
+
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			digest := make([]byte, 0, 64)
+			digest = append(digest, level[i]...)
+			digest = append(digest, right...)
+			next = append(next, crypto.Sha256d(digest))
+		}
+		level = next
+		offset >>= 1
+	}
+
+	return NewMerklePath(0, path), nil
+}
+
+// NewMerklePathFromBlock returns the MerklePath (BUMP) proving the inclusion of
+// the txid at targetIndex in the block at blockHeight, given all of the block's
+// txids in block order, as ExtractMerklePath.
+//
+// Where a level of the tree has an odd number of hashes, the last hash is paired
+// with itself, and a path through it marks its missing sibling as a duplicate.
+func NewMerklePathFromBlock(txids [][]byte, targetIndex int, blockHeight uint32) (*MerklePath, error) {
+	mp, err := ExtractMerklePath(txids, targetIndex)
+	if err != nil {
+		return nil, err
+	}
+	mp.BlockHeight = blockHeight
+	return mp, nil
+}
+
 // NewMerklePathFromHex creates a new MerklePath with the given hex data
 func NewMerklePathFromHex(hexData string) (*MerklePath, error) {
 	bin, err := hex.DecodeString(hexData)
@@ -272,6 +355,107 @@ func (mp *MerklePath) ComputeRootBin(txidLE *[]byte) ([]byte, error) {
 	return workingHash, nil
 }
 
+// maxMerklePathHeight is the height of a tree of 2^64 txs, beyond which offsets
+// cannot be represented.
+const maxMerklePathHeight = 64
+
+// Validate checks the structure of the path without reference to any block, so
+// malformed proofs can be rejected before asking a chain tracker for the root.
+// It checks that:
+//   - there is at least one level, no more than 64, and the first is not empty
+//   - each entry is either a 32 byte hash or flagged duplicate, but not both
+//   - duplicates are not txids, sit at odd offsets and are the last entry of
+//     their level
+//   - only entries in the first level are flagged as txids
+//   - offsets are unique within a level and within range for the tree height
+//   - every hash in the first level has a sibling at each level up to the root,
+//     either given or computable from the levels below
+//
+// An error wrapping ErrInvalidMerklePath and identifying the level, and entry
+// where there is one, is returned for the first problem found. A valid structure
+// does not mean the path proves anything; ComputeRoot and Verify check that.
+func (mp *MerklePath) Validate() error {
+	height := len(mp.Path)
+	if height == 0 {
+		return fmt.Errorf("%w: no levels", ErrInvalidMerklePath)
+	}
+	if height > maxMerklePathHeight {
+		return fmt.Errorf("%w: %d levels, at most %d are allowed", ErrInvalidMerklePath, height, maxMerklePathHeight)
+	}
+	if len(mp.Path[0]) == 0 {
+		return fmt.Errorf("%w: level 0: no entries", ErrInvalidMerklePath)
+	}
+
+	indexedPath := make(IndexedPath, height)
+	for h, level := range mp.Path {
+		// the number of nodes the level can hold, which overflows for a full
+		// height tree's first level, where any offset is in range
+		width := uint64(1) << (height - h)
+		indexedPath[h] = make(map[uint64]*PathElement, len(level))
+		var duplicateAt *uint64
+		for i, leaf := range level {
+			if leaf == nil {
+				return fmt.Errorf("%w: level %d entry %d: nil", ErrInvalidMerklePath, h, i)
+			}
+			if height-h < maxMerklePathHeight && leaf.Offset >= width {
+				return fmt.Errorf("%w: level %d entry %d: offset %d out of range for %d nodes",
+					ErrInvalidMerklePath, h, i, leaf.Offset, width)
+			}
+			if _, ok := indexedPath[h][leaf.Offset]; ok {
+				return fmt.Errorf("%w: level %d entry %d: offset %d appears more than once",
+					ErrInvalidMerklePath, h, i, leaf.Offset)
+			}
+			indexedPath[h][leaf.Offset] = leaf
+
+			isDuplicate := leaf.Duplicate != nil && *leaf.Duplicate
+			isTxid := leaf.Txid != nil && *leaf.Txid
+			switch {
+			case isDuplicate && len(leaf.Hash) > 0:
+				return fmt.Errorf("%w: level %d entry %d: flagged duplicate but has a hash", ErrInvalidMerklePath, h, i)
+			case isDuplicate && isTxid:
+				return fmt.Errorf("%w: level %d entry %d: flagged both duplicate and txid", ErrInvalidMerklePath, h, i)
+			case isDuplicate && leaf.Offset%2 == 0:
+				return fmt.Errorf("%w: level %d entry %d: duplicate at even offset %d", ErrInvalidMerklePath, h, i, leaf.Offset)
+			case !isDuplicate && len(leaf.Hash) != 32:
+				return fmt.Errorf("%w: level %d entry %d: hash is %d bytes, expected 32", ErrInvalidMerklePath, h, i, len(leaf.Hash))
+			case isTxid && h > 0:
+				return fmt.Errorf("%w: level %d entry %d: txid flag above level 0", ErrInvalidMerklePath, h, i)
+			}
+			if isDuplicate {
+				offset := leaf.Offset
+				duplicateAt = &offset
+			}
+		}
+		if duplicateAt != nil {
+			for i, leaf := range level {
+				if leaf.Offset > *duplicateAt {
+					return fmt.Errorf("%w: level %d entry %d: offset %d is beyond the duplicate at offset %d",
+						ErrInvalidMerklePath, h, i, leaf.Offset, *duplicateAt)
+				}
+			}
+		}
+	}
+
+	// A single tx block has only the txid, which is also the root.
+	if height == 1 && len(mp.Path[0]) == 1 {
+		return nil
+	}
+	for i, leaf := range mp.Path[0] {
+		if leaf.Duplicate != nil && *leaf.Duplicate {
+			continue
+		}
+		for h := 0; h < height; h++ {
+			sibling := (leaf.Offset >> h) ^ 1
+			if indexedPath.GetOffsetLeaf(h, sibling) == nil {
+				return fmt.Errorf("%w: level %d: no node at offset %d needed by level 0 entry %d",
+					ErrInvalidMerklePath, h, sibling, i)
+			}
+		}
+	}
+
+	return nil
+}
+
 // Verify checks if a given transaction ID is part of the Merkle tree at the specified block height using a chain tracker
 func (mp *MerklePath) Verify(txid string, ct chaintracker.ChainTracker) (bool, error) {
 	root, err := mp.ComputeRoot(&txid)
@@ -286,6 +470,28 @@ func (mp *MerklePath) Verify(txid string, ct chaintracker.ChainTracker) (bool, e
 	return ct.IsValidRootForHeight(rootBytes, mp.BlockHeight), nil
 }
 
+// ConfirmationsAt returns the number of confirmations the block at the path's
+// BlockHeight has when the chain tip is at currentHeight. The including block
+// counts as the first confirmation. Zero is returned if the path's height is
+// above currentHeight.
+func (mp *MerklePath) ConfirmationsAt(currentHeight uint32) uint32 {
+	if mp.BlockHeight > currentHeight {
+		return 0
+	}
+	return currentHeight - mp.BlockHeight + 1
+}
+
+// VerifyConfirmations checks that txid is proven by the path using the chain tracker,
+// as Verify does, and that the block has at least required confirmations when the
+// chain tip is at currentHeight.
+func (mp *MerklePath) VerifyConfirmations(txid string, ct chaintracker.ChainTracker, currentHeight, required uint32) (bool, error) {
+	ok, err := mp.Verify(txid, ct)
+	if err != nil || !ok {
+		return false, err
+	}
+	return mp.ConfirmationsAt(currentHeight) >= required, nil
+}
+
 func (m *MerklePath) Combine(other *MerklePath) (err error) {
 	if m.BlockHeight != other.BlockHeight {
 		return errors.New("cannot combine MerklePaths with different block heights")
@@ -340,3 +546,123 @@ func (m *MerklePath) Combine(other *MerklePath) (err error) {
 
 	return
 }
+
+// CombineMerklePaths merges paths proving txs in the same block into a single
+// BUMP, as Combine does, sharing the nodes common to the paths and dropping those
+// which can be computed from the level below. Each txid proven by any of the
+// paths is proven by the result.
+//
+// The paths are not modified. An error is returned if there are no paths, or if
+// they are not all for the same block, having different block heights or roots.
+func CombineMerklePaths(paths []*MerklePath) (*MerklePath, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no merkle paths to combine")
+	}
+	for i, mp := range paths {
+		if mp == nil || len(mp.Path) == 0 {
+			return nil, fmt.Errorf("%w: path %d is empty", ErrInvalidMerklePath, i)
+		}
+	}
+
+	combined := paths[0].clone()
+	for i, mp := range paths[1:] {
+		if len(mp.Path) != len(combined.Path) {
+			return nil, fmt.Errorf("path %d: cannot combine MerklePaths with different tree heights", i+1)
+		}
+		if err := combined.Combine(mp.clone()); err != nil {
+			return nil, errors.Wrapf(err, "path %d", i+1)
+		}
+	}
+
+	return combined, nil
+}
+
+// clone returns a deep copy of the path.
+func (mp *MerklePath) clone() *MerklePath {
+	path := make([][]*PathElement, len(mp.Path))
+	for h, level := range mp.Path {
+		path[h] = make([]*PathElement, len(level))
+		for i, leaf := range level {
+			c := *leaf
+			c.Hash = append(util.ByteStringLE(nil), leaf.Hash...)
+			if leaf.Txid != nil {
+				txid := *leaf.Txid
+				c.Txid = &txid
+			}
+			if leaf.Duplicate != nil {
+				duplicate := *leaf.Duplicate
+				c.Duplicate = &duplicate
+			}
+			path[h][i] = &c
+		}
+	}
+	return NewMerklePath(mp.BlockHeight, path)
+}
+
+// PathForTxid returns the minimal path proving just the txid, given in display
+// order as returned by TxIDBytes, extracted from a path which may prove several
+// txs in the block, such as one from CombineMerklePaths. The result holds the
+// txid and one sibling at each level, computing any sibling the combined path
+// leaves out, and verifies to the same root.
+//
+// An ErrTxidNotInPath error is returned if the txid is not in the first level of
+// the path, and ErrInvalidMerklePath if a sibling it needs is missing.
+func (mp *MerklePath) PathForTxid(txid []byte) (*MerklePath, error) {
+	if len(mp.Path) == 0 {
+		return nil, fmt.Errorf("%w: no levels", ErrInvalidMerklePath)
+	}
+	txidLE := util.ReverseBytes(txid)
+
+	var txLeaf *PathElement
+	for _, leaf := range mp.Path[0] {
+		if bytes.Equal(leaf.Hash, txidLE) {
+			txLeaf = leaf
+			break
+		}
+	}
+	if txLeaf == nil {
+		return nil, fmt.Errorf("%w: %x", ErrTxidNotInPath, txid)
+	}
+
+	isTxid := true
+	target := &PathElement{Offset: txLeaf.Offset, Hash: slices.Clone(txLeaf.Hash), Txid: &isTxid}
+	if len(mp.Path) == 1 && len(mp.Path[0]) == 1 {
+		// the txid is the only one in the block
+		return NewMerklePath(mp.BlockHeight, [][]*PathElement{{target}}), nil
+	}
+
+	indexedPath := make(IndexedPath, len(mp.Path))
+	for h, level := range mp.Path {
+		indexedPath[h] = make(map[uint64]*PathElement, len(level))
+		for _, leaf := range level {
+			indexedPath[h][leaf.Offset] = leaf
+		}
+	}
+
+	path := make([][]*PathElement, len(mp.Path))
+	for h := range mp.Path {
+		offset := (txLeaf.Offset >> h) ^ 1
+		leaf := indexedPath.GetOffsetLeaf(h, offset)
+		if leaf == nil {
+			return nil, fmt.Errorf("%w: level %d is missing offset %d", ErrInvalidMerklePath, h, offset)
+		}
+		sibling := &PathElement{Offset: offset}
+		if leaf.Duplicate != nil && *leaf.Duplicate {
+			duplicate := true
+			sibling.Duplicate = &duplicate
+		} else {
+			sibling.Hash = slices.Clone(leaf.Hash)
+		}
+
+		if h == 0 {
+			path[h] = []*PathElement{target, sibling}
+			slices.SortFunc(path[h], func(a, b *PathElement) int {
+				return int(a.Offset) - int(b.Offset)
+			})
+		} else {
+			path[h] = []*PathElement{sibling}
+		}
+	}
+
+	return NewMerklePath(mp.BlockHeight, path), nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/bitcoin-sv/go-sdk/crypto"
 	"github.com/bitcoin-sv/go-sdk/transaction/testdata"
 	"github.com/bitcoin-sv/go-sdk/util"
 	"github.com/stretchr/testify/assert"
@@ -121,6 +122,47 @@ func TestMerklePath_Verify(t *testing.T) {
 
 }
 
+func TestMerklePath_ConfirmationsAt(t *testing.T) {
+	t.Parallel()
+
+	path := MerklePath{BlockHeight: 100}
+	assert.Equal(t, uint32(0), path.ConfirmationsAt(99))
+	assert.Equal(t, uint32(1), path.ConfirmationsAt(100))
+	assert.Equal(t, uint32(6), path.ConfirmationsAt(105))
+
+	path.BlockHeight = 0
+	assert.Equal(t, uint32(1), path.ConfirmationsAt(0))
+}
+
+func TestMerklePath_VerifyConfirmations(t *testing.T) {
+	t.Parallel()
+
+	path := MerklePath{
+		BlockHeight: BRC74JSON.BlockHeight,
+		Path:        BRC74JSON.Path,
+	}
+	tracker := MyChainTracker{}
+
+	t.Run("enough confirmations", func(t *testing.T) {
+		ok, err := path.VerifyConfirmations(BRC74TXID1, tracker, BRC74JSON.BlockHeight+5, 6)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("too few confirmations", func(t *testing.T) {
+		ok, err := path.VerifyConfirmations(BRC74TXID1, tracker, BRC74JSON.BlockHeight+4, 6)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid root for height", func(t *testing.T) {
+		wrong := MerklePath{BlockHeight: BRC74JSON.BlockHeight + 1, Path: BRC74JSON.Path}
+		ok, err := wrong.VerifyConfirmations(BRC74TXID1, tracker, BRC74JSON.BlockHeight+10, 1)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
 func TestMerklePath_Combine(t *testing.T) {
 	t.Parallel()
 
@@ -186,3 +228,359 @@ func TestMerklePath_Combine(t *testing.T) {
 		}
 	})
 }
+
+func TestExtractMerklePath(t *testing.T) {
+	t.Parallel()
+
+	// Txids of block 100000.
+	blockTxids := []string{
+		"8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87",
+		"fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4",
+		"6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4",
+		"e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+	}
+	const blockRoot = "f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766"
+
+	decode := func(txids []string) [][]byte {
+		bb := make([][]byte, len(txids))
+		for i, txid := range txids {
+			b, err := hex.DecodeString(txid)
+			assert.NoError(t, err)
+			bb[i] = b
+		}
+		return bb
+	}
+
+	t.Run("verifies to the block merkle root", func(t *testing.T) {
+		for i, txid := range blockTxids {
+			mp, err := ExtractMerklePath(decode(blockTxids), i)
+			assert.NoError(t, err)
+			root, err := mp.ComputeRoot(&txid)
+			assert.NoError(t, err)
+			assert.Equal(t, blockRoot, root)
+
+			// survives BUMP serialisation
+			parsed, err := NewMerklePathFromHex(mp.ToHex())
+			assert.NoError(t, err)
+			root, err = parsed.ComputeRoot(&txid)
+			assert.NoError(t, err)
+			assert.Equal(t, blockRoot, root)
+		}
+	})
+
+	t.Run("odd number of txids", func(t *testing.T) {
+		txids := append(append([]string{}, blockTxids...), BRC74TXID1)
+		var roots []string
+		for i, txid := range txids {
+			mp, err := ExtractMerklePath(decode(txids), i)
+			assert.NoError(t, err)
+			root, err := mp.ComputeRoot(&txid)
+			assert.NoError(t, err)
+			roots = append(roots, root)
+		}
+		for _, root := range roots {
+			assert.Equal(t, roots[0], root)
+		}
+		assert.NotEqual(t, blockRoot, roots[0])
+	})
+
+	t.Run("single txid", func(t *testing.T) {
+		mp, err := ExtractMerklePath(decode(blockTxids[:1]), 0)
+		assert.NoError(t, err)
+		root, err := mp.ComputeRoot(&blockTxids[0])
+		assert.NoError(t, err)
+		assert.Equal(t, blockTxids[0], root)
+	})
+
+	t.Run("index out of range", func(t *testing.T) {
+		_, err := ExtractMerklePath(decode(blockTxids), 4)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid txid", func(t *testing.T) {
+		_, err := ExtractMerklePath([][]byte{{0x01}}, 0)
+		assert.ErrorIs(t, err, ErrInvalidTxID)
+	})
+}
+
+func TestNewMerklePathFromBlock(t *testing.T) {
+	t.Parallel()
+
+	// naiveRoot computes the merkle root of display order txids, pairing the last
+	// hash at each odd level with itself.
+	naiveRoot := func(txids [][]byte) string {
+		level := make([][]byte, len(txids))
+		for i, txid := range txids {
+			level[i] = util.ReverseBytes(txid)
+		}
+		for len(level) > 1 {
+			if len(level)%2 == 1 {
+				level = append(level, level[len(level)-1])
+			}
+			next := make([][]byte, 0, len(level)/2)
+			for i := 0; i < len(level); i += 2 {
+				next = append(next, crypto.Sha256d(append(append([]byte{}, level[i]...), level[i+1]...)))
+			}
+			level = next
+		}
+		return hex.EncodeToString(util.ReverseBytes(level[0]))
+	}
+
+	for _, count := range []int{1, 2, 3, 5, 6, 7, 11} {
+		txids := make([][]byte, count)
+		for i := range txids {
+			txids[i] = crypto.Sha256d([]byte{byte(count), byte(i)})
+		}
+		root := naiveRoot(txids)
+
+		for i, txid := range txids {
+			mp, err := NewMerklePathFromBlock(txids, i, 800000)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(800000), mp.BlockHeight)
+
+			txidStr := hex.EncodeToString(txid)
+			got, err := mp.ComputeRoot(&txidStr)
+			assert.NoError(t, err, "count %d index %d", count, i)
+			assert.Equal(t, root, got, "count %d index %d", count, i)
+		}
+	}
+
+	t.Run("duplicate marked at odd levels", func(t *testing.T) {
+		txids := make([][]byte, 3)
+		for i := range txids {
+			txids[i] = crypto.Sha256d([]byte{byte(i)})
+		}
+		mp, err := NewMerklePathFromBlock(txids, 2, 1)
+		assert.NoError(t, err)
+		assert.Len(t, mp.Path[0], 2)
+		assert.Equal(t, uint64(3), mp.Path[0][1].Offset)
+		assert.NotNil(t, mp.Path[0][1].Duplicate)
+		assert.True(t, *mp.Path[0][1].Duplicate)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewMerklePathFromBlock(nil, 0, 1)
+		assert.Error(t, err)
+		_, err = NewMerklePathFromBlock([][]byte{{0x01}}, 0, 1)
+		assert.ErrorIs(t, err, ErrInvalidTxID)
+	})
+}
+
+func TestMerklePath_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid paths", func(t *testing.T) {
+		mp, err := NewMerklePathFromHex(BRC74Hex)
+		assert.NoError(t, err)
+		assert.NoError(t, mp.Validate())
+
+		for _, valid := range testdata.ValidBumps {
+			mp, err := NewMerklePathFromHex(valid.Bump)
+			assert.NoError(t, err)
+			assert.NoError(t, mp.Validate(), valid.Bump)
+		}
+
+		for _, count := range []int{1, 2, 3, 5, 8} {
+			txids := make([][]byte, count)
+			for i := range txids {
+				txids[i] = crypto.Sha256d([]byte{byte(i)})
+			}
+			for i := range txids {
+				mp, err := NewMerklePathFromBlock(txids, i, 1)
+				assert.NoError(t, err)
+				assert.NoError(t, mp.Validate(), "count %d index %d", count, i)
+			}
+		}
+	})
+
+	isTrue := true
+	hash := func(b byte) []byte { return crypto.Sha256d([]byte{b}) }
+	// valid returns a path for a 3 tx block proving the txid at offset 2.
+	valid := func() *MerklePath {
+		return NewMerklePath(1, [][]*PathElement{
+			{{Offset: 2, Hash: hash(2), Txid: &isTrue}, {Offset: 3, Duplicate: &isTrue}},
+			{{Offset: 0, Hash: hash(0)}},
+		})
+	}
+	assert.NoError(t, valid().Validate())
+
+	tests := map[string]struct {
+		modify func(mp *MerklePath)
+		msg    string
+	}{
+		"no levels": {
+			modify: func(mp *MerklePath) { mp.Path = nil },
+			msg:    "no levels",
+		},
+		"empty first level": {
+			modify: func(mp *MerklePath) { mp.Path[0] = nil },
+			msg:    "level 0: no entries",
+		},
+		"nil entry": {
+			modify: func(mp *MerklePath) { mp.Path[1][0] = nil },
+			msg:    "level 1 entry 0: nil",
+		},
+		"offset out of range": {
+			modify: func(mp *MerklePath) { mp.Path[1][0].Offset = 2 },
+			msg:    "level 1 entry 0: offset 2 out of range",
+		},
+		"repeated offset": {
+			modify: func(mp *MerklePath) { mp.Path[1] = append(mp.Path[1], &PathElement{Offset: 0, Hash: hash(0)}) },
+			msg:    "level 1 entry 1: offset 0 appears more than once",
+		},
+		"duplicate with a hash": {
+			modify: func(mp *MerklePath) { mp.Path[0][1].Hash = hash(3) },
+			msg:    "level 0 entry 1: flagged duplicate but has a hash",
+		},
+		"duplicate txid": {
+			modify: func(mp *MerklePath) { mp.Path[0][1].Txid = &isTrue },
+			msg:    "level 0 entry 1: flagged both duplicate and txid",
+		},
+		"duplicate at even offset": {
+			modify: func(mp *MerklePath) {
+				mp.Path[0] = []*PathElement{{Offset: 1, Hash: hash(1), Txid: &isTrue}, {Offset: 2, Duplicate: &isTrue}}
+			},
+			msg: "level 0 entry 1: duplicate at even offset 2",
+		},
+		"entry beyond duplicate": {
+			modify: func(mp *MerklePath) {
+				mp.Path = [][]*PathElement{
+					{{Offset: 0, Hash: hash(0), Txid: &isTrue}, {Offset: 1, Hash: hash(1)}},
+					{{Offset: 1, Duplicate: &isTrue}},
+					{{Offset: 1, Hash: hash(9)}},
+				}
+				mp.Path[1] = append(mp.Path[1], &PathElement{Offset: 3, Hash: hash(8)})
+			},
+			msg: "level 1 entry 1: offset 3 is beyond the duplicate at offset 1",
+		},
+		"short hash": {
+			modify: func(mp *MerklePath) { mp.Path[1][0].Hash = hash(0)[:31] },
+			msg:    "level 1 entry 0: hash is 31 bytes",
+		},
+		"txid above first level": {
+			modify: func(mp *MerklePath) { mp.Path[1][0].Txid = &isTrue },
+			msg:    "level 1 entry 0: txid flag above level 0",
+		},
+		"missing sibling": {
+			modify: func(mp *MerklePath) { mp.Path[1][0].Offset = 1 },
+			msg:    "level 1: no node at offset 0 needed by level 0 entry 0",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mp := valid()
+			test.modify(mp)
+			err := mp.Validate()
+			assert.ErrorIs(t, err, ErrInvalidMerklePath)
+			assert.ErrorContains(t, err, test.msg)
+		})
+	}
+}
+
+func TestCombineMerklePaths(t *testing.T) {
+	t.Parallel()
+
+	txids := make([][]byte, 11)
+	for i := range txids {
+		txids[i] = crypto.Sha256d([]byte{byte(i)})
+	}
+	pathFor := func(t *testing.T, index int, height uint32) *MerklePath {
+		mp, err := NewMerklePathFromBlock(txids, index, height)
+		assert.NoError(t, err)
+		return mp
+	}
+	root, err := pathFor(t, 0, 1).ComputeRoot(nil)
+	assert.NoError(t, err)
+
+	for _, indices := range [][]int{{3, 4, 10}, {0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}} {
+		paths := make([]*MerklePath, len(indices))
+		var separate int
+		for i, index := range indices {
+			paths[i] = pathFor(t, index, 800000)
+			separate += len(paths[i].Bytes())
+		}
+		before := paths[0].ToHex()
+
+		combined, err := CombineMerklePaths(paths)
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(800000), combined.BlockHeight)
+		assert.Less(t, len(combined.Bytes()), separate)
+		assert.Equal(t, before, paths[0].ToHex())
+
+		for _, index := range indices {
+			txid := hex.EncodeToString(txids[index])
+			got, err := combined.ComputeRoot(&txid)
+			assert.NoError(t, err, "index %d", index)
+			assert.Equal(t, root, got, "index %d", index)
+		}
+	}
+
+	t.Run("single path", func(t *testing.T) {
+		mp := pathFor(t, 5, 1)
+		combined, err := CombineMerklePaths([]*MerklePath{mp})
+		assert.NoError(t, err)
+		assert.Equal(t, mp.ToHex(), combined.ToHex())
+	})
+
+	t.Run("different blocks", func(t *testing.T) {
+		_, err := CombineMerklePaths([]*MerklePath{pathFor(t, 0, 1), pathFor(t, 1, 2)})
+		assert.Error(t, err)
+
+		other, err := NewMerklePathFromBlock(txids[:10], 1, 1)
+		assert.NoError(t, err)
+		_, err = CombineMerklePaths([]*MerklePath{pathFor(t, 0, 1), other})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := CombineMerklePaths(nil)
+		assert.Error(t, err)
+		_, err = CombineMerklePaths([]*MerklePath{pathFor(t, 0, 1), nil})
+		assert.ErrorIs(t, err, ErrInvalidMerklePath)
+	})
+}
+
+func TestMerklePath_PathForTxid(t *testing.T) {
+	t.Parallel()
+
+	txids := make([][]byte, 11)
+	paths := make([]*MerklePath, len(txids))
+	for i := range txids {
+		txids[i] = crypto.Sha256d([]byte{byte(i)})
+	}
+	for i := range txids {
+		mp, err := NewMerklePathFromBlock(txids, i, 800000)
+		assert.NoError(t, err)
+		paths[i] = mp
+	}
+	combined, err := CombineMerklePaths(paths)
+	assert.NoError(t, err)
+	root, err := combined.ComputeRoot(nil)
+	assert.NoError(t, err)
+
+	for i, txid := range txids {
+		mp, err := combined.PathForTxid(txid)
+		assert.NoError(t, err, "index %d", i)
+		assert.Equal(t, paths[i].ToHex(), mp.ToHex(), "index %d", i)
+
+		id := hex.EncodeToString(txid)
+		got, err := mp.ComputeRoot(&id)
+		assert.NoError(t, err, "index %d", i)
+		assert.Equal(t, root, got, "index %d", i)
+	}
+
+	t.Run("single tx block", func(t *testing.T) {
+		single, err := NewMerklePathFromBlock(txids[:1], 0, 1)
+		assert.NoError(t, err)
+		mp, err := single.PathForTxid(txids[0])
+		assert.NoError(t, err)
+		assert.Equal(t, single.ToHex(), mp.ToHex())
+	})
+
+	t.Run("txid not in path", func(t *testing.T) {
+		_, err := paths[0].PathForTxid(txids[5])
+		assert.ErrorIs(t, err, ErrTxidNotInPath)
+		_, err = combined.PathForTxid(crypto.Sha256d([]byte("missing")))
+		assert.ErrorIs(t, err, ErrTxidNotInPath)
+	})
+}
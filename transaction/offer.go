@@ -0,0 +1,103 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/sighash"
+)
+
+// OfferSigHash is the sighash used to sign offers, committing each input only to
+// itself and the output at the same index.
+const OfferSigHash = sighash.SingleForkID | sighash.AnyOneCanPay
+
+// BuildOffer returns a partial tx offering utxo in exchange for askOutput, such as a
+// token or ordinal listed for sale at the price paid to askOutput.
+//
+// The tx has the utxo as its only input and askOutput as its only output, and the
+// input is signed by the unlocker with SINGLE|ANYONECANPAY|FORKID. The signature
+// only commits to the input itself, the output at the same index, and the tx
+// version and locktime, so a buyer can add their own inputs and outputs without
+// invalidating it, but cannot change the price or where it is paid.
+//
+// SIGHASH_SINGLE pairs input i with output i, so whoever completes the offer must
+// keep the input and askOutput at the same index as each other. Adding inputs and
+// outputs after them, as Tx.AcceptOffer does, keeps them at index 0.
+//
+// The offer should be shared in extended format, with Tx.ExtendedBytes, as the
+// buyer needs the value and locking script of the offered utxo to accept it.
+func BuildOffer(ctx context.Context, utxo *UTXO, askOutput *Output, unlocker Unlocker) (*Tx, error) {
+	if utxo == nil {
+		return nil, fmt.Errorf("%w: no utxo to offer", ErrInvalidSellOffer)
+	}
+	if askOutput == nil || askOutput.LockingScript == nil {
+		return nil, fmt.Errorf("%w: no ask output", ErrInvalidSellOffer)
+	}
+	if unlocker == nil {
+		return nil, ErrNoUnlocker
+	}
+
+	tx := NewTx()
+	if err := tx.FromUTXOs(utxo); err != nil {
+		return nil, err
+	}
+	tx.AddOutput(askOutput)
+
+	if err := tx.FillInput(ctx, unlocker, UnlockerParams{
+		InputIdx:     0,
+		SigHashFlags: OfferSigHash,
+	}); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// AcceptOffer completes an offer made by BuildOffer, or any tx whose inputs are
+// each signed SINGLE|ANYONECANPAY against the output at the same index, into the
+// receiver for the buyer to sign.
+//
+// The receiver must have no inputs. Its outputs, such as one receiving the item
+// being bought, are kept but moved after the offer's outputs, so each offer input
+// stays at the index of its output. The tx takes the version and locktime of the
+// offer, which the offer signatures commit to. It is then funded from the
+// UTXOGetterFunc, with change paid to changeScript, as Tx.Fund.
+//
+// The offer inputs must carry their previous output, as the offer does when
+// parsed from extended format or returned by BuildOffer, since the fee and the
+// signatures depend on it. An offer parsed from plain tx bytes is rejected with
+// ErrInvalidSellOffer.
+//
+// The offer inputs keep their unlocking scripts, and the buyer then signs only
+// their own inputs, for example with Tx.FillUnsignedInputs. The offer itself is
+// not modified, and neither is the receiver if an error is returned.
+func (tx *Tx) AcceptOffer(ctx context.Context, offer *Tx, fq *FeeQuote, next UTXOGetterFunc, changeScript *bscript.Script) error {
+	if offer == nil || offer.InputCount() == 0 || offer.InputCount() != offer.OutputCount() {
+		return fmt.Errorf("%w: offer must pair each input with an output", ErrInvalidSellOffer)
+	}
+	for i, in := range offer.Inputs {
+		if in.UnlockingScript == nil || len(*in.UnlockingScript) == 0 {
+			return fmt.Errorf("%w: input %d is not signed", ErrInvalidSellOffer, i)
+		}
+		if in.PreviousTxScript == nil {
+			return fmt.Errorf("%w: input %d has no previous output, the offer must be in extended format",
+				ErrInvalidSellOffer, i)
+		}
+	}
+	if tx.InputCount() != 0 {
+		return fmt.Errorf("%w: cannot accept an offer into a tx with inputs", ErrInvalidSellOffer)
+	}
+
+	accepted := offer.Clone()
+	accepted.Outputs = append(accepted.Outputs, tx.Outputs...)
+	if err := accepted.Fund(ctx, fq, next, FundOptions{AutoChange: true, ChangeScript: changeScript}); err != nil {
+		return err
+	}
+
+	tx.Version = accepted.Version
+	tx.LockTime = accepted.LockTime
+	tx.Inputs = accepted.Inputs
+	tx.Outputs = accepted.Outputs
+	return nil
+}
@@ -0,0 +1,170 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildOffer(t *testing.T) {
+	t.Parallel()
+
+	seller, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	sellerScript, err := bscript.NewP2PKHFromPubKeyEC(seller.PubKey())
+	assert.NoError(t, err)
+	buyer, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	buyerScript, err := bscript.NewP2PKHFromPubKeyEC(buyer.PubKey())
+	assert.NoError(t, err)
+	txID, err := hex.DecodeString("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+	assert.NoError(t, err)
+
+	item := &transaction.UTXO{TxID: txID, Vout: 0, LockingScript: sellerScript, Satoshis: 1}
+	ask := &transaction.Output{LockingScript: sellerScript, Satoshis: 5000}
+
+	t.Run("signature survives buyer additions", func(t *testing.T) {
+		offer, err := transaction.BuildOffer(context.Background(), item, ask, &unlocker.Simple{PrivateKey: seller})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, offer.InputCount())
+		assert.Equal(t, 1, offer.OutputCount())
+		assert.NoError(t, offer.VerifyInputSignatures())
+
+		_, flag, err := offer.Inputs[0].ExtractSignature()
+		assert.NoError(t, err)
+		assert.Equal(t, transaction.OfferSigHash, flag)
+
+		assert.NoError(t, offer.FromUTXOs(&transaction.UTXO{TxID: txID, Vout: 1, LockingScript: buyerScript, Satoshis: 10000}))
+		assert.NoError(t, offer.PayTo(buyerScript, 1))
+		assert.NoError(t, offer.PayTo(buyerScript, 4800))
+		assert.NoError(t, offer.FillInput(context.Background(), &unlocker.Simple{PrivateKey: buyer},
+			transaction.UnlockerParams{InputIdx: 1}))
+
+		assert.NoError(t, offer.VerifyInputSignatures())
+	})
+
+	t.Run("changing the ask invalidates the signature", func(t *testing.T) {
+		offer, err := transaction.BuildOffer(context.Background(), item, ask, &unlocker.Simple{PrivateKey: seller})
+		assert.NoError(t, err)
+
+		offer.Outputs[0] = &transaction.Output{LockingScript: buyerScript, Satoshis: 5000}
+		assert.ErrorIs(t, offer.VerifyInputSignatures(), transaction.ErrInvalidSignature)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := transaction.BuildOffer(context.Background(), nil, ask, &unlocker.Simple{PrivateKey: seller})
+		assert.ErrorIs(t, err, transaction.ErrInvalidSellOffer)
+		_, err = transaction.BuildOffer(context.Background(), item, nil, &unlocker.Simple{PrivateKey: seller})
+		assert.ErrorIs(t, err, transaction.ErrInvalidSellOffer)
+		_, err = transaction.BuildOffer(context.Background(), item, ask, nil)
+		assert.ErrorIs(t, err, transaction.ErrNoUnlocker)
+	})
+}
+
+func TestTx_AcceptOffer(t *testing.T) {
+	t.Parallel()
+
+	seller, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	sellerScript, err := bscript.NewP2PKHFromPubKeyEC(seller.PubKey())
+	assert.NoError(t, err)
+	buyer, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	buyerScript, err := bscript.NewP2PKHFromPubKeyEC(buyer.PubKey())
+	assert.NoError(t, err)
+	txID, err := hex.DecodeString("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+	assert.NoError(t, err)
+
+	item := &transaction.UTXO{TxID: txID, Vout: 0, LockingScript: sellerScript, Satoshis: 1}
+	ask := &transaction.Output{LockingScript: sellerScript, Satoshis: 5000}
+	funds := []*transaction.UTXO{
+		{TxID: txID, Vout: 1, LockingScript: buyerScript, Satoshis: 3000},
+		{TxID: txID, Vout: 2, LockingScript: buyerScript, Satoshis: 3000},
+	}
+	fq := transaction.NewFeeQuote()
+
+	t.Run("offer, accept, sign and verify", func(t *testing.T) {
+		offer, err := transaction.BuildOffer(context.Background(), item, ask, &unlocker.Simple{PrivateKey: seller})
+		assert.NoError(t, err)
+		sellerUnlocking := offer.Inputs[0].UnlockingScript.String()
+
+		buy := transaction.NewTx()
+		assert.NoError(t, buy.PayTo(buyerScript, 1))
+		assert.NoError(t, buy.AcceptOffer(context.Background(), offer, fq,
+			transaction.NewConsolidationGetter(funds, 0), buyerScript))
+
+		assert.Equal(t, 1, offer.InputCount())
+		assert.Equal(t, 1, offer.OutputCount())
+		assert.Equal(t, 3, buy.InputCount())
+		assert.Equal(t, 3, buy.OutputCount())
+		assert.Equal(t, ask.Satoshis, buy.Outputs[0].Satoshis)
+		assert.Equal(t, sellerScript.String(), buy.Outputs[0].LockingScript.String())
+		assert.Equal(t, uint64(1), buy.Outputs[1].Satoshis)
+
+		assert.NoError(t, buy.FillUnsignedInputs(context.Background(), &unlocker.Getter{PrivateKey: buyer}))
+		assert.Equal(t, sellerUnlocking, buy.Inputs[0].UnlockingScript.String())
+		assert.NoError(t, buy.VerifyInputSignatures())
+
+		ok, err := buy.IsFeePaidEnough(fq)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("offer shared in extended format", func(t *testing.T) {
+		built, err := transaction.BuildOffer(context.Background(), item, ask, &unlocker.Simple{PrivateKey: seller})
+		assert.NoError(t, err)
+
+		raw, err := transaction.NewTxFromBytes(built.Bytes())
+		assert.NoError(t, err)
+		err = transaction.NewTx().AcceptOffer(context.Background(), raw, fq, transaction.NewConsolidationGetter(funds, 0), buyerScript)
+		assert.ErrorIs(t, err, transaction.ErrInvalidSellOffer)
+
+		offer, err := transaction.NewTxFromBytes(built.ExtendedBytes())
+		assert.NoError(t, err)
+		buy := transaction.NewTx()
+		assert.NoError(t, buy.AcceptOffer(context.Background(), offer, fq, transaction.NewConsolidationGetter(funds, 0), buyerScript))
+		assert.NoError(t, buy.FillUnsignedInputs(context.Background(), &unlocker.Getter{PrivateKey: buyer}))
+		assert.NoError(t, buy.VerifyInputSignatures())
+	})
+
+	t.Run("insufficient funds leaves the tx unchanged", func(t *testing.T) {
+		offer, err := transaction.BuildOffer(context.Background(), item, ask, &unlocker.Simple{PrivateKey: seller})
+		assert.NoError(t, err)
+
+		buy := transaction.NewTx()
+		assert.NoError(t, buy.PayTo(buyerScript, 1))
+		err = buy.AcceptOffer(context.Background(), offer, fq, transaction.NewConsolidationGetter(funds[:1], 0), buyerScript)
+		assert.ErrorIs(t, err, transaction.ErrInsufficientFunds)
+		assert.Equal(t, 0, buy.InputCount())
+		assert.Equal(t, 1, buy.OutputCount())
+	})
+
+	t.Run("invalid offers", func(t *testing.T) {
+		getter := transaction.NewConsolidationGetter(funds, 0)
+
+		unsigned := transaction.NewTx()
+		assert.NoError(t, unsigned.FromUTXOs(item))
+		unsigned.AddOutput(ask)
+		assert.ErrorIs(t, transaction.NewTx().AcceptOffer(context.Background(), unsigned, fq, getter, buyerScript),
+			transaction.ErrInvalidSellOffer)
+
+		offer, err := transaction.BuildOffer(context.Background(), item, ask, &unlocker.Simple{PrivateKey: seller})
+		assert.NoError(t, err)
+		offer.AddOutput(ask)
+		assert.ErrorIs(t, transaction.NewTx().AcceptOffer(context.Background(), offer, fq, getter, buyerScript),
+			transaction.ErrInvalidSellOffer)
+
+		offer, err = transaction.BuildOffer(context.Background(), item, ask, &unlocker.Simple{PrivateKey: seller})
+		assert.NoError(t, err)
+		buy := transaction.NewTx()
+		assert.NoError(t, buy.FromUTXOs(funds[0]))
+		assert.ErrorIs(t, buy.AcceptOffer(context.Background(), offer, fq, getter, buyerScript),
+			transaction.ErrInvalidSellOffer)
+	})
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/crypto"
 	"github.com/pkg/errors"
 )
 
@@ -47,8 +49,7 @@ func (o *Output) ReadFrom(r io.Reader) (int64, error) {
 		return bytesRead, err
 	}
 
-	scriptBytes := make([]byte, l)
-	n, err = io.ReadFull(r, scriptBytes)
+	scriptBytes, n, err := readBytes(r, l)
 	bytesRead += int64(n)
 	if err != nil {
 		return bytesRead, errors.Wrapf(err, "lockingScript(%d): got %d bytes", l, n)
@@ -60,26 +61,117 @@ func (o *Output) ReadFrom(r io.Reader) (int64, error) {
 	return bytesRead, nil
 }
 
+// IsDataCarrier returns true if the Output locking script is an OP_RETURN
+// (or OP_FALSE OP_RETURN) data script. Such outputs are unspendable and may
+// legitimately carry zero satoshis.
+func (o *Output) IsDataCarrier() bool {
+	return o.LockingScript != nil && o.LockingScript.IsData()
+}
+
+// IsDust returns true if the Output is spendable and its value is below the
+// fee quote's DustThreshold. Data carrier outputs are never dust.
+func (o *Output) IsDust(f *FeeQuote) bool {
+	if o.IsDataCarrier() {
+		return false
+	}
+	return o.Satoshis < f.DustThreshold()
+}
+
+// Validate checks the output could be accepted by miners on its own: it must have
+// a locking script, its value must not exceed MaxSatoshis, and unless it is a data
+// carrier it must not be below dustThreshold, such as a fee quote's DustThreshold.
+// Data carrier outputs may be zero value.
+//
+// An ErrNilLockingScript, ErrOutputExceedsMaxSatoshis, ErrZeroValueOutput or
+// ErrDustOutput error is returned for the first check which fails.
+func (o *Output) Validate(dustThreshold uint64) error {
+	if o.LockingScript == nil {
+		return ErrNilLockingScript
+	}
+	if o.Satoshis > MaxSatoshis {
+		return fmt.Errorf("%w: %d satoshis", ErrOutputExceedsMaxSatoshis, o.Satoshis)
+	}
+	if o.IsDataCarrier() {
+		return nil
+	}
+	if o.Satoshis == 0 {
+		return ErrZeroValueOutput
+	}
+	if o.Satoshis < dustThreshold {
+		return fmt.Errorf("%w: %d satoshis, threshold is %d", ErrDustOutput, o.Satoshis, dustThreshold)
+	}
+
+	return nil
+}
+
 // LockingScriptHex returns the locking script
 // of an output encoded as a hex string.
 func (o *Output) LockingScriptHex() string {
 	return hex.EncodeToString(*o.LockingScript)
 }
 
+// String implements the Stringer interface and returns a human-readable
+// representation of the output, with the script shown as ASM. The mainnet
+// address paid is included for P2PKH and P2PK outputs.
 func (o *Output) String() string {
-	return fmt.Sprintf(`value:     %d
-scriptLen: %d
-script:    %s
-`, o.Satoshis, len(*o.LockingScript), o.LockingScript)
+	scriptLen := 0
+	if o.LockingScript != nil {
+		scriptLen = len(*o.LockingScript)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "value:     %d\n", o.Satoshis)
+	fmt.Fprintf(&sb, "scriptLen: %d\n", scriptLen)
+	fmt.Fprintf(&sb, "script:    %s\n", scriptASM(o.LockingScript))
+	if addr := o.address(); addr != "" {
+		fmt.Fprintf(&sb, "address:   %s\n", addr)
+	}
+
+	return sb.String()
 }
 
-// Bytes encodes the Output into a byte array.
-func (o *Output) Bytes() []byte {
+// address returns the mainnet address of a P2PKH or P2PK output, or an
+// empty string if there is none.
+func (o *Output) address() string {
+	if o.LockingScript == nil {
+		return ""
+	}
+
+	var a *bscript.Address
+	var err error
+	switch {
+	case o.LockingScript.IsP2PKH():
+		var pkh []byte
+		if pkh, err = o.LockingScript.PublicKeyHash(); err == nil {
+			a, err = bscript.NewAddressFromPublicKeyHash(pkh, true)
+		}
+	case o.LockingScript.IsP2PK():
+		var parts [][]byte
+		if parts, err = bscript.DecodeParts(*o.LockingScript); err == nil {
+			a, err = bscript.NewAddressFromPublicKeyHash(crypto.Hash160(parts[0]), true)
+		}
+	default:
+		return ""
+	}
+	if err != nil {
+		return ""
+	}
+
+	return a.AddressString
+}
+
+// SatoshiBytes returns the satoshi value of the Output as the 8 little-endian
+// bytes it is serialised as.
+func (o *Output) SatoshiBytes() []byte {
 	b := make([]byte, 8)
 	binary.LittleEndian.PutUint64(b, o.Satoshis)
+	return b
+}
 
+// Bytes encodes the Output into a byte array.
+func (o *Output) Bytes() []byte {
 	h := make([]byte, 0)
-	h = append(h, b...)
+	h = append(h, o.SatoshiBytes()...)
 	h = append(h, VarInt(uint64(len(*o.LockingScript))).Bytes()...)
 	h = append(h, *o.LockingScript...)
 
@@ -90,10 +182,7 @@ func (o *Output) Bytes() []byte {
 // of an output to be hashed and signed (sighash).
 func (o *Output) BytesForSigHash() []byte {
 	buf := make([]byte, 0)
-
-	satoshis := make([]byte, 8)
-	binary.LittleEndian.PutUint64(satoshis, o.Satoshis)
-	buf = append(buf, satoshis...)
+	buf = append(buf, o.SatoshiBytes()...)
 
 	buf = append(buf, VarInt(uint64(len(*o.LockingScript))).Bytes()...)
 	buf = append(buf, *o.LockingScript...)
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/bitcoin-sv/go-sdk/bscript"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,26 +14,40 @@ func TestNewOutputFromBytes(t *testing.T) {
 	t.Parallel()
 
 	t.Run("invalid output, too short", func(t *testing.T) {
-		o, s, err := newOutputFromBytes([]byte(""))
+		o, s, err := OutputFromBytes([]byte(""))
 		assert.Error(t, err)
 		assert.Nil(t, o)
 		assert.Equal(t, 0, s)
 	})
 
 	t.Run("invalid output, too short + script", func(t *testing.T) {
-		o, s, err := newOutputFromBytes([]byte("0000000000000"))
+		o, s, err := OutputFromBytes([]byte("0000000000000"))
 		assert.Error(t, err)
 		assert.Nil(t, o)
 		assert.Equal(t, 0, s)
 	})
 
+	t.Run("invalid output, truncated varint", func(t *testing.T) {
+		o, s, err := OutputFromBytes([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0xfe, 0x01})
+		assert.ErrorIs(t, err, ErrOutputTooShort)
+		assert.Nil(t, o)
+		assert.Equal(t, 0, s)
+	})
+
+	t.Run("invalid output, huge script length", func(t *testing.T) {
+		o, s, err := OutputFromBytes([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+		assert.ErrorIs(t, err, ErrOutputTooShort)
+		assert.Nil(t, o)
+		assert.Equal(t, 0, s)
+	})
+
 	t.Run("valid output", func(t *testing.T) {
 		bytes, err := hex.DecodeString(outputHexStr)
 		assert.NoError(t, err)
 
 		var o *Output
 		var s int
-		o, s, err = newOutputFromBytes(bytes)
+		o, s, err = OutputFromBytes(bytes)
 		assert.NoError(t, err)
 		assert.NotNil(t, o)
 
@@ -43,6 +58,18 @@ func TestNewOutputFromBytes(t *testing.T) {
 	})
 }
 
+func TestOutput_SatoshiBytes(t *testing.T) {
+	t.Parallel()
+
+	bytes, err := hex.DecodeString(outputHexStr)
+	assert.NoError(t, err)
+
+	o, _, err := OutputFromBytes(bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, bytes[:8], o.SatoshiBytes())
+	assert.Equal(t, bytes[:34], o.Bytes())
+}
+
 func TestOutput_String(t *testing.T) {
 	t.Run("compare string output", func(t *testing.T) {
 
@@ -50,10 +77,78 @@ func TestOutput_String(t *testing.T) {
 		assert.NoError(t, err)
 
 		var o *Output
-		o, _, err = newOutputFromBytes(bytes)
+		o, _, err = OutputFromBytes(bytes)
 		assert.NoError(t, err)
 		assert.NotNil(t, o)
 
-		assert.Equal(t, "value:     1252788362\nscriptLen: 25\nscript:    76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac\n", o.String())
+		assert.Equal(t, "value:     1252788362\nscriptLen: 25\nscript:    OP_DUP OP_HASH160 8bf10d323ac757268eb715e613cb8e8e1d1793aa OP_EQUALVERIFY OP_CHECKSIG\naddress:   1DkwhDjgTfeysTTyteR6RUY8HDyTgieynb\n", o.String())
+	})
+
+	t.Run("data output", func(t *testing.T) {
+		o, err := CreateOpReturnOutput([][]byte{[]byte("hi")})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "value:     0\nscriptLen: 5\nscript:    OP_FALSE OP_RETURN 6869\n", o.String())
+	})
+
+	t.Run("nil script", func(t *testing.T) {
+		assert.Equal(t, "value:     5\nscriptLen: 0\nscript:    \n", (&Output{Satoshis: 5}).String())
 	})
 }
+
+func TestOutput_Validate(t *testing.T) {
+	t.Parallel()
+
+	p2pkh, err := bscript.NewP2PKHFromAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi")
+	assert.NoError(t, err)
+	data, err := bscript.NewFromASM("OP_FALSE OP_RETURN 68656c6c6f")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		output *Output
+		err    error
+	}{
+		"spendable output": {
+			output: &Output{Satoshis: 1000, LockingScript: p2pkh},
+		},
+		"spendable output at threshold": {
+			output: &Output{Satoshis: 136, LockingScript: p2pkh},
+		},
+		"max satoshis": {
+			output: &Output{Satoshis: MaxSatoshis, LockingScript: p2pkh},
+		},
+		"zero value data carrier": {
+			output: &Output{LockingScript: data},
+		},
+		"nil locking script": {
+			output: &Output{Satoshis: 1000},
+			err:    ErrNilLockingScript,
+		},
+		"exceeds max satoshis": {
+			output: &Output{Satoshis: MaxSatoshis + 1, LockingScript: p2pkh},
+			err:    ErrOutputExceedsMaxSatoshis,
+		},
+		"data carrier exceeds max satoshis": {
+			output: &Output{Satoshis: MaxSatoshis + 1, LockingScript: data},
+			err:    ErrOutputExceedsMaxSatoshis,
+		},
+		"zero value spendable output": {
+			output: &Output{LockingScript: p2pkh},
+			err:    ErrZeroValueOutput,
+		},
+		"dust": {
+			output: &Output{Satoshis: 135, LockingScript: p2pkh},
+			err:    ErrDustOutput,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.output.Validate(136)
+			if test.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package transaction
+
+import (
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/sighash"
+)
+
+// PushTxUnlockingScript returns an unlocking script for the input at inputIdx
+// which pushes the input's sighash preimage, as calculated by CalcInputPreimage,
+// as a single push. This is the OP_PUSH_TX pattern used by sCrypt style contracts
+// to introspect the spending tx. If flags is zero, ALL|FORKID is used.
+//
+// The preimage commits to whatever the flags select, so it must be calculated
+// once the tx is otherwise final: with ALL every input and output is committed
+// to, so any later change to the tx invalidates it. Adding ANYONECANPAY leaves
+// other inputs free to change, and NONE or SINGLE free the outputs (other than
+// the one at inputIdx for SINGLE). FORKID should always be set for BSV.
+func (tx *Tx) PushTxUnlockingScript(inputIdx uint32, flags sighash.Flag) (*bscript.Script, error) {
+	return tx.pushTxUnlockingScript(inputIdx, flags, nil)
+}
+
+// PushTxUnlockingScriptWithSig is the same as PushTxUnlockingScript but pushes a
+// signature by key over the same sighash before the preimage, giving
+// <signature> <preimage>, for contracts which check both.
+func (tx *Tx) PushTxUnlockingScriptWithSig(inputIdx uint32, flags sighash.Flag,
+	key *ec.PrivateKey) (*bscript.Script, error) {
+	return tx.pushTxUnlockingScript(inputIdx, flags, key)
+}
+
+func (tx *Tx) pushTxUnlockingScript(inputIdx uint32, flags sighash.Flag, key *ec.PrivateKey) (*bscript.Script, error) {
+	if flags == 0 {
+		flags = sighash.AllForkID
+	}
+
+	preimage, err := tx.sigStrat(flags)(inputIdx, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &bscript.Script{}
+	if key != nil {
+		sh, err := tx.CalcInputSignatureHash(inputIdx, flags)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := key.Sign(sh)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.AppendPushData(append(sig.Serialise(), byte(flags))); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.AppendPushData(preimage); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,185 @@
+package transaction
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TxReport is a summary of a transaction intended to help diagnose why it
+// may be rejected by a miner. It is returned by Explain.
+type TxReport struct {
+	TxID        string
+	Version     uint32
+	LockTime    uint32
+	Size        uint64
+	DataBytes   uint64
+	InputCount  int
+	OutputCount int
+	// TotalInput is the sum of the previous output values being spent. Inputs
+	// without previous tx data contribute zero.
+	TotalInput  uint64
+	TotalOutput uint64
+	// Fee is TotalInput minus TotalOutput, or zero if the outputs exceed the inputs.
+	Fee uint64
+	// ExpectedFee is the fee required by the fee quote for the current size.
+	ExpectedFee uint64
+	// FeeRate is the fee paid in satoshis per byte.
+	FeeRate float64
+	Outputs []OutputReport
+	// Issues lists anything that is likely to make the transaction non-standard
+	// or otherwise unacceptable to a miner.
+	Issues []string
+}
+
+// OutputReport describes a single output within a TxReport.
+type OutputReport struct {
+	Index    int
+	Satoshis uint64
+	Type     string
+	Dust     bool
+}
+
+// ExplainOptions are stricter miner policies for Explain to check, beyond the
+// default node policy.
+type ExplainOptions struct {
+	// MaxDataOutputs is the number of data (OP_RETURN) outputs allowed, as
+	// CheckDataOutputs. Zero allows any number.
+	MaxDataOutputs int
+	// CheckLockTime reports a locktime which is ignored because every input is
+	// final, as ValidateLockTimeEffective.
+	CheckLockTime bool
+}
+
+// Explain builds a TxReport for the transaction, checking its fee, output
+// values and script sizes against the passed fee quote and default node policy,
+// along with any stricter policy set in opts.
+// If fq is nil, DefaultFeeQuote is used. The transaction is not modified.
+func (tx *Tx) Explain(fq *FeeQuote, opts ...ExplainOptions) *TxReport {
+	if fq == nil {
+		fq = DefaultFeeQuote()
+	}
+	var opt ExplainOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	size := tx.SizeWithTypes()
+	r := &TxReport{
+		TxID:        tx.TxID(),
+		Version:     tx.Version,
+		LockTime:    tx.LockTime,
+		Size:        size.TotalBytes,
+		DataBytes:   size.TotalDataBytes,
+		InputCount:  tx.InputCount(),
+		OutputCount: tx.OutputCount(),
+	}
+
+	var err error
+	if r.TotalInput, err = tx.TotalInputSatoshisChecked(); err != nil {
+		r.addIssue("input total: %s", err)
+	}
+	if r.TotalOutput, err = tx.TotalOutputSatoshisChecked(); err != nil {
+		r.addIssue("output total: %s", err)
+	}
+
+	if r.TotalInput >= r.TotalOutput {
+		r.Fee = r.TotalInput - r.TotalOutput
+	} else {
+		r.addIssue("outputs exceed inputs by %d satoshis", r.TotalOutput-r.TotalInput)
+	}
+	if r.Size > 0 {
+		r.FeeRate = float64(r.Fee) / float64(r.Size)
+	}
+
+	if fees, err := tx.feesPaid(size, fq); err != nil {
+		r.addIssue("cannot calculate expected fee: %s", err)
+	} else {
+		r.ExpectedFee = fees.TotalFeePaid
+		if r.Fee < r.ExpectedFee {
+			r.addIssue("fee of %d satoshis is below the expected %d", r.Fee, r.ExpectedFee)
+		}
+	}
+
+	if r.InputCount == 0 {
+		r.addIssue("no inputs")
+	}
+	for i, in := range tx.Inputs {
+		if in.PreviousTxScript == nil {
+			r.addIssue("input %d has no previous tx data", i)
+		}
+		if in.UnlockingScriptSize() == 0 {
+			r.addIssue("input %d is not signed", i)
+		}
+	}
+
+	if r.OutputCount == 0 {
+		r.addIssue("no outputs")
+	}
+	r.Outputs = make([]OutputReport, 0, len(tx.Outputs))
+	for i, o := range tx.Outputs {
+		or := OutputReport{
+			Index:    i,
+			Satoshis: o.Satoshis,
+			Type:     "empty",
+			Dust:     o.IsDust(fq),
+		}
+		if o.LockingScript != nil {
+			or.Type = o.LockingScript.ScriptType()
+		}
+		if or.Dust {
+			r.addIssue("output %d of %d satoshis is below the dust threshold of %d", i, o.Satoshis, fq.DustThreshold())
+		}
+		r.Outputs = append(r.Outputs, or)
+	}
+
+	if err := tx.CheckScriptSizes(0, 0); err != nil {
+		r.addIssue("%s", err)
+	}
+	if err := tx.CheckDataOutputs(opt.MaxDataOutputs); err != nil {
+		r.addIssue("%s", err)
+	}
+	if opt.CheckLockTime {
+		if err := tx.ValidateLockTimeEffective(); err != nil {
+			r.addIssue("%s", err)
+		}
+	}
+
+	return r
+}
+
+// OK returns true if no issues were found.
+func (r *TxReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+func (r *TxReport) addIssue(format string, a ...interface{}) {
+	r.Issues = append(r.Issues, fmt.Sprintf(format, a...))
+}
+
+// String returns the report in a multi-line, human-readable form suitable for logs.
+func (r *TxReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "txid:     %s\n", r.TxID)
+	fmt.Fprintf(&sb, "version:  %d locktime: %d\n", r.Version, r.LockTime)
+	fmt.Fprintf(&sb, "size:     %d bytes (%d data)\n", r.Size, r.DataBytes)
+	fmt.Fprintf(&sb, "inputs:   %d totalling %d satoshis\n", r.InputCount, r.TotalInput)
+	fmt.Fprintf(&sb, "outputs:  %d totalling %d satoshis\n", r.OutputCount, r.TotalOutput)
+	fmt.Fprintf(&sb, "fee:      %d satoshis (%.3f sat/byte, expected %d)\n", r.Fee, r.FeeRate, r.ExpectedFee)
+	for _, o := range r.Outputs {
+		fmt.Fprintf(&sb, "  [%d] %d satoshis %s", o.Index, o.Satoshis, o.Type)
+		if o.Dust {
+			sb.WriteString(" (dust)")
+		}
+		sb.WriteString("\n")
+	}
+	if r.OK() {
+		sb.WriteString("issues:   none\n")
+		return sb.String()
+	}
+	sb.WriteString("issues:\n")
+	for _, issue := range r.Issues {
+		fmt.Fprintf(&sb, "  - %s\n", issue)
+	}
+
+	return sb.String()
+}
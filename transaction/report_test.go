@@ -0,0 +1,122 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/txtest"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_Explain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("signed tx has no issues", func(t *testing.T) {
+		f := txtest.NewFixture(t, 1, 2)
+
+		r := f.Tx.Explain(transaction.DefaultFeeQuote())
+		assert.True(t, r.OK(), r.String())
+		assert.Equal(t, f.Tx.TxID(), r.TxID)
+		assert.Equal(t, uint64(f.Tx.Size()), r.Size)
+		assert.Equal(t, uint64(txtest.UTXOSatoshis), r.TotalInput)
+		assert.Equal(t, f.Tx.TotalOutputSatoshis(), r.TotalOutput)
+		assert.Equal(t, r.TotalInput-r.TotalOutput, r.Fee)
+		assert.Equal(t, r.ExpectedFee, r.Fee)
+		assert.InDelta(t, float64(r.Fee)/float64(f.Tx.Size()), r.FeeRate, 0.0001)
+		assert.Len(t, r.Outputs, 2)
+		assert.Equal(t, "pubkeyhash", r.Outputs[0].Type)
+		assert.Contains(t, r.String(), "issues:   none")
+	})
+
+	tests := map[string]struct {
+		modify    func(t *testing.T, tx *transaction.Tx)
+		unsigned  bool
+		opts      []transaction.ExplainOptions
+		expIssues []string
+	}{
+		"unsigned tx with dust output": {
+			modify: func(t *testing.T, tx *transaction.Tx) {
+				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1))
+			},
+			unsigned:  true,
+			expIssues: []string{"input 0 is not signed", "(dust)"},
+		},
+		"outputs exceed inputs": {
+			modify: func(t *testing.T, tx *transaction.Tx) {
+				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", txtest.UTXOSatoshis))
+			},
+			expIssues: []string{"outputs exceed inputs by"},
+		},
+		"data outputs within the limit": {
+			modify: addDataOutputs(2),
+			opts:   []transaction.ExplainOptions{{MaxDataOutputs: 2}},
+		},
+		"too many data outputs": {
+			modify:    addDataOutputs(2),
+			opts:      []transaction.ExplainOptions{{MaxDataOutputs: 1}},
+			expIssues: []string{"too many data outputs: 2 data outputs, limit is 1"},
+		},
+		"locktime not checked": {
+			modify: func(t *testing.T, tx *transaction.Tx) {
+				tx.LockTime = 800000
+			},
+		},
+		"ineffective locktime": {
+			modify: func(t *testing.T, tx *transaction.Tx) {
+				tx.LockTime = 800000
+			},
+			opts:      []transaction.ExplainOptions{{CheckLockTime: true}},
+			expIssues: []string{"locktime is ignored"},
+		},
+		"effective locktime": {
+			modify: func(t *testing.T, tx *transaction.Tx) {
+				tx.LockTime = 800000
+				tx.Inputs[0].SequenceNumber = 0
+			},
+			opts: []transaction.ExplainOptions{{CheckLockTime: true}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := txtest.NewFixture(t, 1, 2)
+			// leave room for the fee of anything added
+			f.Tx.Outputs[1].Satoshis -= 100
+			test.modify(t, f.Tx)
+			f.Tx.Inputs[0].UnlockingScript = nil
+			if !test.unsigned {
+				assert.NoError(t, f.Tx.FillInput(context.Background(), &unlocker.Simple{PrivateKey: f.Keys[0]},
+					transaction.UnlockerParams{InputIdx: 0}))
+			}
+
+			r := f.Tx.Explain(transaction.DefaultFeeQuote(), test.opts...)
+			assert.Equal(t, len(test.expIssues) == 0, r.OK(), r.String())
+			for _, issue := range test.expIssues {
+				assert.Contains(t, r.String(), issue)
+			}
+		})
+	}
+}
+
+// addDataOutputs returns a modifier adding n OP_RETURN outputs to a tx.
+func addDataOutputs(n int) func(t *testing.T, tx *transaction.Tx) {
+	return func(t *testing.T, tx *transaction.Tx) {
+		for i := 0; i < n; i++ {
+			assert.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+		}
+	}
+}
+
+func TestTx_CheckDataOutputs(t *testing.T) {
+	t.Parallel()
+
+	tx := txtest.NewFundedTx(t, 1, 1)
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("world")))
+	assert.Equal(t, 2, tx.OpReturnOutputCount())
+
+	assert.NoError(t, tx.CheckDataOutputs(0))
+	assert.NoError(t, tx.CheckDataOutputs(2))
+	assert.ErrorIs(t, tx.CheckDataOutputs(1), transaction.ErrTooManyDataOutputs)
+}
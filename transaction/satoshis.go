@@ -0,0 +1,23 @@
+package transaction
+
+import "math/bits"
+
+// addSatoshis returns a + b, or ErrSatoshiOverflow if the sum does not fit in a uint64.
+func addSatoshis(a, b uint64) (uint64, error) {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return 0, ErrSatoshiOverflow
+	}
+	return sum, nil
+}
+
+// mulDivSatoshis returns a * b / d without overflowing the intermediate product,
+// or ErrSatoshiOverflow if the result does not fit in a uint64.
+func mulDivSatoshis(a, b, d uint64) (uint64, error) {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= d {
+		return 0, ErrSatoshiOverflow
+	}
+	q, _ := bits.Div64(hi, lo, d)
+	return q, nil
+}
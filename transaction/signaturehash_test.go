@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
 	"github.com/bitcoin-sv/go-sdk/sighash"
 	"github.com/bitcoin-sv/go-sdk/transaction"
 	"github.com/stretchr/testify/assert"
@@ -225,3 +226,65 @@ func TestTx_CalcInputPreimageLegacy(t *testing.T) {
 		})
 	}
 }
+
+func TestTx_PushTxUnlockingScript(t *testing.T) {
+	t.Parallel()
+
+	tx, err := transaction.NewTxFromHex("010000000193a35408b6068499e0d5abd799d3e827d9bfe70c9b75ebe209c91d25072326510000000000ffffffff02404b4c00000000001976a91404ff367be719efa79d76e4416ffb072cd53b208888acde94a905000000001976a91404d03f746652cfcb6cb55119ab473a045137d26588ac00000000")
+	assert.NoError(t, err)
+	tx.Inputs[0].PreviousTxSatoshis = 100000000
+	tx.Inputs[0].PreviousTxScript, err = bscript.NewFromHex("76a914c0a3c167a28cabb9fbb495affa0761e6e74ac60d88ac")
+	assert.NoError(t, err)
+
+	for _, flags := range []sighash.Flag{sighash.AllForkID, sighash.SingleForkID | sighash.AnyOneCanPay} {
+		t.Run(flags.String(), func(t *testing.T) {
+			preimage, err := tx.CalcInputPreimage(0, flags)
+			assert.NoError(t, err)
+
+			s, err := tx.PushTxUnlockingScript(0, flags)
+			assert.NoError(t, err)
+			parts, err := bscript.DecodeParts(*s)
+			assert.NoError(t, err)
+			assert.Equal(t, [][]byte{preimage}, parts)
+		})
+	}
+
+	t.Run("default flags", func(t *testing.T) {
+		preimage, err := tx.CalcInputPreimage(0, sighash.AllForkID)
+		assert.NoError(t, err)
+
+		s, err := tx.PushTxUnlockingScript(0, 0)
+		assert.NoError(t, err)
+		parts, err := bscript.DecodeParts(*s)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{preimage}, parts)
+	})
+
+	t.Run("with signature", func(t *testing.T) {
+		priv, err := ec.NewPrivateKey()
+		assert.NoError(t, err)
+
+		s, err := tx.PushTxUnlockingScriptWithSig(0, sighash.AllForkID, priv)
+		assert.NoError(t, err)
+		parts, err := bscript.DecodeParts(*s)
+		assert.NoError(t, err)
+		assert.Len(t, parts, 2)
+
+		sigBytes := parts[0]
+		assert.Equal(t, byte(sighash.AllForkID), sigBytes[len(sigBytes)-1])
+		sig, err := ec.ParseDERSignature(sigBytes[:len(sigBytes)-1])
+		assert.NoError(t, err)
+		sh, err := tx.CalcInputSignatureHash(0, sighash.AllForkID)
+		assert.NoError(t, err)
+		assert.True(t, sig.Verify(sh, priv.PubKey()))
+
+		preimage, err := tx.CalcInputPreimage(0, sighash.AllForkID)
+		assert.NoError(t, err)
+		assert.Equal(t, preimage, parts[1])
+	})
+
+	t.Run("missing input", func(t *testing.T) {
+		_, err := tx.PushTxUnlockingScript(1, sighash.AllForkID)
+		assert.ErrorIs(t, err, transaction.ErrInputNoExist)
+	})
+}
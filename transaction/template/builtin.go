@@ -0,0 +1,122 @@
+package template
+
+import (
+	"context"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/sighash"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+)
+
+// P2PKH is the pay to public key hash template.
+type P2PKH struct{}
+
+// Matches returns true for P2PKH scripts, including those carrying an inscription.
+func (p *P2PKH) Matches(lockingScript *bscript.Script) bool {
+	switch lockingScript.ScriptType() {
+	case bscript.ScriptTypePubKeyHash, bscript.ScriptTypePubKeyHashInscription:
+		return true
+	}
+	return false
+}
+
+// Lock builds a P2PKH locking script from params.PublicKey, or from
+// params.Address if no public key is set.
+func (p *P2PKH) Lock(params Params) (*bscript.Script, error) {
+	if params.PublicKey != nil {
+		return bscript.NewP2PKHFromPubKeyEC(params.PublicKey)
+	}
+	if params.Address != "" {
+		return bscript.NewP2PKHFromAddress(params.Address)
+	}
+	return nil, ErrMissingPublicKey
+}
+
+// Unlock returns an `unlocker.Simple` for the private key.
+func (p *P2PKH) Unlock(privKey *ec.PrivateKey, _ Params) transaction.Unlocker {
+	return &unlocker.Simple{PrivateKey: privKey}
+}
+
+// P2PK is the pay to public key template.
+type P2PK struct{}
+
+// Matches returns true for P2PK scripts.
+func (p *P2PK) Matches(lockingScript *bscript.Script) bool {
+	return lockingScript.IsP2PK()
+}
+
+// Lock builds a P2PK locking script paying to the compressed params.PublicKey.
+func (p *P2PK) Lock(params Params) (*bscript.Script, error) {
+	if params.PublicKey == nil {
+		return nil, ErrMissingPublicKey
+	}
+	s := &bscript.Script{}
+	if err := s.AppendPushData(params.PublicKey.SerialiseCompressed()); err != nil {
+		return nil, err
+	}
+	if err := s.AppendOpcodes(bscript.OpCHECKSIG); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Unlock returns an unlocker which pushes a signature made with the private key.
+func (p *P2PK) Unlock(privKey *ec.PrivateKey, _ Params) transaction.Unlocker {
+	return &p2pkUnlocker{privateKey: privKey}
+}
+
+type p2pkUnlocker struct {
+	privateKey *ec.PrivateKey
+}
+
+func (u *p2pkUnlocker) UnlockingScript(ctx context.Context, tx *transaction.Tx, params transaction.UnlockerParams) (*bscript.Script, error) {
+	if params.SigHashFlags == 0 {
+		params.SigHashFlags = sighash.AllForkID
+	}
+
+	sh, err := tx.CalcInputSignatureHash(params.InputIdx, params.SigHashFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := u.privateKey.Sign(sh)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &bscript.Script{}
+	if err = s.AppendPushData(append(sig.Serialise(), uint8(params.SigHashFlags))); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// OpReturn is the OP_FALSE OP_RETURN data template. Its outputs cannot be spent.
+type OpReturn struct{}
+
+// Matches returns true for data scripts.
+func (o *OpReturn) Matches(lockingScript *bscript.Script) bool {
+	return lockingScript.IsData()
+}
+
+// Lock builds an OP_FALSE OP_RETURN locking script pushing params.Data.
+func (o *OpReturn) Lock(params Params) (*bscript.Script, error) {
+	out, err := transaction.CreateOpReturnOutput(params.Data)
+	if err != nil {
+		return nil, err
+	}
+	return out.LockingScript, nil
+}
+
+// Unlock returns an unlocker which always fails with ErrUnspendable.
+func (o *OpReturn) Unlock(_ *ec.PrivateKey, _ Params) transaction.Unlocker {
+	return unspendableUnlocker{}
+}
+
+type unspendableUnlocker struct{}
+
+func (unspendableUnlocker) UnlockingScript(context.Context, *transaction.Tx, transaction.UnlockerParams) (*bscript.Script, error) {
+	return nil, ErrUnspendable
+}
@@ -0,0 +1,100 @@
+// Package template provides reusable pairs of locking and unlocking scripts.
+//
+// A ScriptTemplate knows how to build a locking script, how to recognise one it
+// built, and how to produce a `transaction.Unlocker` able to spend it. The Getter
+// holds a set of templates and implements `transaction.UnlockerGetter`, so
+// `tx.FillAllInputs` routes each input to the template matching its previous
+// locking script.
+//
+// P2PKH, P2PK and OpReturn are provided as built-in templates. A custom template
+// is registered by implementing ScriptTemplate and passing it to Getter.Register:
+//
+//	g := template.NewGetter(privKey).Register(&MyContract{})
+//	if err := tx.FillAllInputs(ctx, g); err != nil {}
+package template
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/pkg/errors"
+)
+
+// Sentinel errors reported by the templates.
+var (
+	ErrMissingPublicKey = errors.New("template params require a public key or address")
+	ErrUnspendable      = errors.New("locking script is unspendable")
+)
+
+// Params are the values a template builds its scripts from. Each template uses
+// the fields relevant to it and ignores the rest; Custom is free for use by
+// templates outside of this package.
+type Params struct {
+	// PublicKey used by P2PKH and P2PK.
+	PublicKey *ec.PublicKey
+	// Address used by P2PKH when no PublicKey is set.
+	Address string
+	// Data pushed by OpReturn.
+	Data [][]byte
+	// Custom holds any params needed by a custom template.
+	Custom interface{}
+}
+
+// ScriptTemplate is a reusable locking/unlocking script pair.
+type ScriptTemplate interface {
+	// Matches returns true if the locking script is of this template's type.
+	Matches(lockingScript *bscript.Script) bool
+	// Lock builds a locking script from the params.
+	Lock(params Params) (*bscript.Script, error)
+	// Unlock returns an unlocker spending a locking script of this template's
+	// type with the private key.
+	Unlock(privKey *ec.PrivateKey, params Params) transaction.Unlocker
+}
+
+// Getter implements the `transaction.UnlockerGetter` interface, returning the
+// unlocker of the first registered template matching a locking script.
+type Getter struct {
+	PrivateKey *ec.PrivateKey
+	// Params passed to ScriptTemplate.Unlock.
+	Params    Params
+	templates []ScriptTemplate
+}
+
+// NewGetter returns a Getter unlocking with the private key. If no templates
+// are provided, the built-in P2PKH, P2PK and OpReturn templates are used.
+func NewGetter(privKey *ec.PrivateKey, templates ...ScriptTemplate) *Getter {
+	if len(templates) == 0 {
+		templates = []ScriptTemplate{&P2PKH{}, &P2PK{}, &OpReturn{}}
+	}
+	return &Getter{
+		PrivateKey: privKey,
+		templates:  templates,
+	}
+}
+
+// Register adds a template to the Getter. Registered templates are checked
+// before those already held, so a custom template takes precedence over a
+// built-in one matching the same script.
+func (g *Getter) Register(t ScriptTemplate) *Getter {
+	g.templates = append([]ScriptTemplate{t}, g.templates...)
+	return g
+}
+
+// Unlocker returns the unlocker of the first template matching the locking script.
+//
+// If no template matches, a `transaction.ErrUnsupportedScriptType` error is returned.
+func (g *Getter) Unlocker(ctx context.Context, lockingScript *bscript.Script) (transaction.Unlocker, error) {
+	if lockingScript == nil {
+		return nil, transaction.ErrEmptyPreviousTxScript
+	}
+	for _, t := range g.templates {
+		if t.Matches(lockingScript) {
+			return t.Unlock(g.PrivateKey, g.Params), nil
+		}
+	}
+	return nil, fmt.Errorf("%w '%s', no matching template registered",
+		transaction.ErrUnsupportedScriptType, lockingScript.ScriptType())
+}
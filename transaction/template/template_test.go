@@ -0,0 +1,88 @@
+package template_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/ec/wif"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/template"
+	"github.com/stretchr/testify/assert"
+)
+
+type trueTemplate struct{}
+
+func (trueTemplate) Matches(lockingScript *bscript.Script) bool {
+	return len(*lockingScript) == 1 && (*lockingScript)[0] == bscript.OpTRUE
+}
+
+func (trueTemplate) Lock(template.Params) (*bscript.Script, error) {
+	return &bscript.Script{bscript.OpTRUE}, nil
+}
+
+func (trueTemplate) Unlock(*ec.PrivateKey, template.Params) transaction.Unlocker {
+	return trueUnlocker{}
+}
+
+type trueUnlocker struct{}
+
+func (trueUnlocker) UnlockingScript(context.Context, *transaction.Tx, transaction.UnlockerParams) (*bscript.Script, error) {
+	return &bscript.Script{}, nil
+}
+
+func TestGetter_FillAllInputs(t *testing.T) {
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	params := template.Params{PublicKey: w.PrivKey.PubKey()}
+
+	p2pkh, err := (&template.P2PKH{}).Lock(params)
+	assert.NoError(t, err)
+	assert.True(t, p2pkh.IsP2PKH())
+	p2pk, err := (&template.P2PK{}).Lock(params)
+	assert.NoError(t, err)
+	assert.True(t, p2pk.IsP2PK())
+	custom, err := trueTemplate{}.Lock(template.Params{})
+	assert.NoError(t, err)
+
+	tx := transaction.NewTx()
+	for i, s := range []*bscript.Script{p2pkh, p2pk, custom} {
+		assert.NoError(t, tx.From(
+			"3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", uint32(i), s.String(), 1000))
+	}
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+
+	t.Run("unregistered template", func(t *testing.T) {
+		err := tx.FillAllInputs(context.Background(), template.NewGetter(w.PrivKey))
+		assert.ErrorIs(t, err, transaction.ErrUnsupportedScriptType)
+	})
+
+	t.Run("registered template", func(t *testing.T) {
+		g := template.NewGetter(w.PrivKey).Register(trueTemplate{})
+		assert.NoError(t, tx.FillAllInputs(context.Background(), g))
+		assert.NoError(t, tx.VerifyInputSignatures())
+		assert.Empty(t, *tx.Inputs[2].UnlockingScript)
+	})
+}
+
+func TestOpReturn(t *testing.T) {
+	s, err := (&template.OpReturn{}).Lock(template.Params{Data: [][]byte{[]byte("hello")}})
+	assert.NoError(t, err)
+	assert.True(t, s.IsData())
+	assert.True(t, (&template.OpReturn{}).Matches(s))
+
+	u, err := template.NewGetter(nil).Unlocker(context.Background(), s)
+	assert.NoError(t, err)
+	_, err = u.UnlockingScript(context.Background(), transaction.NewTx(), transaction.UnlockerParams{})
+	assert.ErrorIs(t, err, template.ErrUnspendable)
+}
+
+func TestP2PKH_Lock(t *testing.T) {
+	s, err := (&template.P2PKH{}).Lock(template.Params{Address: "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd"})
+	assert.NoError(t, err)
+	assert.True(t, s.IsP2PKH())
+
+	_, err = (&template.P2PKH{}).Lock(template.Params{})
+	assert.ErrorIs(t, err, template.ErrMissingPublicKey)
+}
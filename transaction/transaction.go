@@ -2,23 +2,42 @@ package transaction
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
+	"time"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
-	"github.com/bitcoin-sv/go-sdk/crypto"
 	"github.com/bitcoin-sv/go-sdk/util"
 )
 
+// Tx is a bitcoin transaction.
+//
+// Version is read directly from the field. Txs of every version are valid, but
+// some features are only interpreted from a minimum version:
+//   - version 1 and above: absolute timelocks with LockTime
+//   - version 2 and above (MinVersionRelativeLockTime): relative timelocks
+//     encoded in input sequence numbers, see HasRelativeLockTime
+//
+// SetVersion and RequireMinVersion guard against setting a version too low for
+// the features the tx uses.
 type Tx struct {
 	Version    uint32      `json:"version"`
 	Inputs     []*Input    `json:"inputs"`
 	Outputs    []*Output   `json:"outputs"`
 	LockTime   uint32      `json:"locktime"`
 	MerklePath *MerklePath `json:"merklePath"`
+
+	// Metadata holds application specific annotations, such as labels or the
+	// source account, alongside the transaction. It is never serialised into
+	// the transaction bytes, does not affect the txid and so never hits the
+	// chain. It is only included in JSON by MarshalJSONWithMetadata.
+	Metadata map[string]string `json:"-"`
 }
 
 // Transactions a collection of *bt.Tx.
@@ -67,6 +86,10 @@ func NewTxFromStream(b []byte) (*Tx, int, error) {
 }
 
 // ReadFrom reads from the `io.Reader` into the `bt.Tx`.
+//
+// When r reports how many bytes remain, as the *bytes.Reader used by NewTxFromBytes
+// does, an input or output count too large for the remaining bytes is rejected with
+// an ErrTxTooManyInputs or ErrTxTooManyOutputs error, both of which match ErrMalformedTx.
 func (tx *Tx) ReadFrom(r io.Reader) (int64, error) {
 	*tx = Tx{}
 	var bytesRead int64
@@ -136,6 +159,13 @@ func (tx *Tx) ReadFrom(r io.Reader) (int64, error) {
 	// We can now proceed with reading the rest of the transaction.
 	// ----------------------------------------------------------------------------------
 
+	if err = checkCount(r, inputCount, minInputSize, ErrTxTooManyInputs); err != nil {
+		return bytesRead, err
+	}
+	if inputCount > 0 {
+		tx.Inputs = make([]*Input, 0, preallocCount(inputCount))
+	}
+
 	// create Inputs
 	for i := uint64(0); i < uint64(inputCount); i++ {
 		input := &Input{}
@@ -156,6 +186,13 @@ func (tx *Tx) ReadFrom(r io.Reader) (int64, error) {
 		}
 	}
 
+	if err = checkCount(r, outputCount, minOutputSize, ErrTxTooManyOutputs); err != nil {
+		return bytesRead, err
+	}
+	if outputCount > 0 {
+		tx.Outputs = make([]*Output, 0, preallocCount(outputCount))
+	}
+
 	for i := uint64(0); i < uint64(outputCount); i++ {
 		output := new(Output)
 		n64, err = output.ReadFrom(r)
@@ -189,7 +226,10 @@ func (tt *Transactions) ReadFrom(r io.Reader) (int64, error) {
 		return bytesRead, err
 	}
 
-	*tt = make([]*Tx, txCount)
+	if err = checkCount(r, txCount, minTxSize, ErrMalformedTx); err != nil {
+		return bytesRead, err
+	}
+	*tt = make([]*Tx, 0, preallocCount(txCount))
 
 	for i := uint64(0); i < uint64(txCount); i++ {
 		tx := new(Tx)
@@ -199,12 +239,75 @@ func (tt *Transactions) ReadFrom(r io.Reader) (int64, error) {
 			return bytesRead, err
 		}
 
-		(*tt)[i] = tx
+		*tt = append(*tt, tx)
 	}
 
 	return bytesRead, nil
 }
 
+const (
+	// minTxSize, minInputSize and minOutputSize are the smallest possible
+	// serialised sizes, used to reject implausible counts while parsing.
+	minTxSize     = 10
+	minInputSize  = 32 + 4 + 1 + 4
+	minOutputSize = 8 + 1
+
+	// maxPrealloc caps how many elements, or bytes, are allocated up front for
+	// a count or length read from untrusted input. Anything larger grows as the
+	// data is actually read.
+	maxPrealloc = 1 << 16
+)
+
+// lenReader is implemented by readers, such as *bytes.Reader, which know
+// how many unread bytes remain.
+type lenReader interface {
+	Len() int
+}
+
+// checkCount returns err, wrapped in ErrMalformedTx, if the reader knows how many
+// bytes remain and too few remain to hold count items of at least minSize bytes.
+func checkCount(r io.Reader, count VarInt, minSize int, err error) error {
+	lr, ok := r.(lenReader)
+	if !ok {
+		return nil
+	}
+	if max := uint64(lr.Len() / minSize); uint64(count) > max {
+		if err == ErrMalformedTx {
+			return fmt.Errorf("%w: %d declared, at most %d possible", err, count, max)
+		}
+		return fmt.Errorf("%w: %w: %d declared, at most %d possible", ErrMalformedTx, err, count, max)
+	}
+	return nil
+}
+
+func preallocCount(count VarInt) int {
+	if count > maxPrealloc {
+		return maxPrealloc
+	}
+	return int(count)
+}
+
+// readBytes reads a field of l bytes, declared by untrusted input, from r. Large
+// fields are read incrementally so a bogus length cannot force a huge allocation.
+// The number of bytes read is returned alongside any error.
+func readBytes(r io.Reader, l VarInt) ([]byte, int, error) {
+	if uint64(l) > math.MaxUint32 {
+		return nil, 0, fmt.Errorf("%w: %d byte field", ErrMalformedTx, l)
+	}
+	if l <= maxPrealloc {
+		b := make([]byte, l)
+		n, err := io.ReadFull(r, b)
+		return b, n, err
+	}
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, r, int64(l))
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return buf.Bytes(), int(n), err
+}
+
 // HasDataOutputs returns true if the transaction has
 // at least one data (OP_RETURN) output in it.
 func (tx *Tx) HasDataOutputs() bool {
@@ -216,6 +319,51 @@ func (tx *Tx) HasDataOutputs() bool {
 	return false
 }
 
+// OpReturnOutputCount returns the number of data (OP_RETURN) outputs in the tx.
+func (tx *Tx) OpReturnOutputCount() int {
+	n := 0
+	for _, out := range tx.Outputs {
+		if out.LockingScript != nil && out.LockingScript.IsData() {
+			n++
+		}
+	}
+	return n
+}
+
+// DataByteCount returns the total size in bytes of the payloads of the tx's data
+// (OP_RETURN) outputs, being the bytes of each locking script after its OP_RETURN,
+// including any push data opcodes. Zero is returned if there are no data outputs.
+func (tx *Tx) DataByteCount() int {
+	n := 0
+	for _, out := range tx.Outputs {
+		if !out.IsDataCarrier() {
+			continue
+		}
+		script := *out.LockingScript
+		if script[0] == bscript.OpFALSE {
+			script = script[1:]
+		}
+		n += len(script) - 1
+	}
+	return n
+}
+
+// CheckDataOutputs checks that the tx has no more than maxDataOutputs data
+// (OP_RETURN) outputs, for miners whose policy limits them. BSV node policy sets
+// no limit, so a maxDataOutputs of zero or less allows any number.
+//
+// If there are too many an ErrTooManyDataOutputs error is returned, detailing the
+// count and the limit.
+func (tx *Tx) CheckDataOutputs(maxDataOutputs int) error {
+	if maxDataOutputs <= 0 {
+		return nil
+	}
+	if n := tx.OpReturnOutputCount(); n > maxDataOutputs {
+		return fmt.Errorf("%w: %d data outputs, limit is %d", ErrTooManyDataOutputs, n, maxDataOutputs)
+	}
+	return nil
+}
+
 // InputIdx will return the input at the specified index.
 //
 // This will consume an overflow error and simply return nil if the input
@@ -258,16 +406,132 @@ func (tx *Tx) IsCoinbase() bool {
 	return false
 }
 
+// IsFinal determines if the transaction is final, and can therefore be mined,
+// in a block at the given height and time.
+//
+// A tx is final if its LockTime is zero, or if every input has a sequence number
+// of 0xFFFFFFFF. Otherwise, the LockTime must be less than the block height when
+// it is below LockTimeThreshold (500,000,000), or less than the block time as a
+// unix timestamp when it is at or above the threshold.
+func (tx *Tx) IsFinal(blockHeight uint32, blockTime time.Time) bool {
+	if tx.LockTime == 0 {
+		return true
+	}
+
+	blockTimeOrHeight := int64(blockHeight)
+	if tx.LockTime >= LockTimeThreshold {
+		blockTimeOrHeight = blockTime.Unix()
+	}
+	if int64(tx.LockTime) < blockTimeOrHeight {
+		return true
+	}
+
+	for _, in := range tx.Inputs {
+		if in.SequenceNumber != MaxTxInSequenceNum {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateLockTimeEffective checks that a non-zero LockTime will be enforced.
+// Consensus ignores the LockTime when every input has a final sequence number of
+// 0xFFFFFFFF, so such a tx can be mined immediately, which is easy to miss when
+// setting a timelock.
+//
+// An ErrLockTimeIneffective error is returned if LockTime is set but no input has
+// a sequence number below 0xFFFFFFFF. A LockTime of zero is always valid.
+func (tx *Tx) ValidateLockTimeEffective() error {
+	if tx.LockTime == 0 {
+		return nil
+	}
+	for _, in := range tx.Inputs {
+		if in.SequenceNumber != MaxTxInSequenceNum {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: locktime %d needs at least one input with a sequence number below 0xFFFFFFFF",
+		ErrLockTimeIneffective, tx.LockTime)
+}
+
+// HasRelativeLockTime reports whether any input's sequence number encodes a
+// relative timelock: its SequenceLockTimeDisabled flag is clear and the lock,
+// masked by SequenceLockTimeMask, is non-zero. Such locks are only interpreted
+// in txs of at least MinVersionRelativeLockTime, and only on networks whose
+// consensus rules enforce BIP 68.
+func (tx *Tx) HasRelativeLockTime() bool {
+	for _, in := range tx.Inputs {
+		if isRelativeLockTime(in.SequenceNumber) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRelativeLockTime reports whether the sequence number encodes a relative
+// timelock, see HasRelativeLockTime.
+func isRelativeLockTime(seq uint32) bool {
+	return seq&SequenceLockTimeDisabled == 0 && seq&SequenceLockTimeMask != 0
+}
+
+// SetVersion sets the tx version to v, raised to MinVersionRelativeLockTime if
+// the tx has relative timelocks, so they are not silently ignored. Setting the
+// Version field directly is not checked. FromUTXOsWithSequence calls it when
+// adding inputs with a relative timelock.
+func (tx *Tx) SetVersion(v uint32) {
+	if v < MinVersionRelativeLockTime && tx.HasRelativeLockTime() {
+		v = MinVersionRelativeLockTime
+	}
+	tx.Version = v
+}
+
+// RequireMinVersion checks the tx version is at least v, as a miner or
+// application policy may require, and at least MinVersionRelativeLockTime if the
+// tx has relative timelocks.
+//
+// An ErrTxVersionTooLow error is returned, detailing the version required and
+// why, if it is not.
+func (tx *Tx) RequireMinVersion(v uint32) error {
+	if tx.Version < v {
+		return fmt.Errorf("%w: version %d, at least %d is required", ErrTxVersionTooLow, tx.Version, v)
+	}
+	if tx.Version < MinVersionRelativeLockTime && tx.HasRelativeLockTime() {
+		return fmt.Errorf("%w: version %d, at least %d is required for relative timelocks",
+			ErrTxVersionTooLow, tx.Version, MinVersionRelativeLockTime)
+	}
+	return nil
+}
+
 // TxIDBytes returns the transaction ID of the transaction as bytes
 // (which is also the transaction hash).
+//
+// The tx is serialised straight into the hasher, rather than into a byte
+// slice first, so large txs are hashed without holding a second copy.
 func (tx *Tx) TxIDBytes() []byte {
-	return util.ReverseBytes(crypto.Sha256d(tx.Bytes()))
+	h := sha256.New()
+	_, _ = tx.WriteTo(h) // hash.Hash never returns an error
+	first := h.Sum(nil)
+	second := sha256.Sum256(first)
+	return util.ReverseBytes(second[:])
 }
 
 // TxID returns the transaction ID of the transaction
 // (which is also the transaction hash).
 func (tx *Tx) TxID() string {
-	return hex.EncodeToString(util.ReverseBytes(crypto.Sha256d(tx.Bytes())))
+	return hex.EncodeToString(tx.TxIDBytes())
+}
+
+// NormalizedTxID returns the hash of the transaction serialised with every
+// unlocking script (scriptSig) blanked, as BytesWithoutUnlockingScripts, in the
+// same byte order as TxID.
+//
+// As signatures are not included, the id is stable under signature malleability
+// and can identify a transaction before it has been signed. It is not the
+// consensus txid and cannot be used to reference the transaction's outputs.
+func (tx *Tx) NormalizedTxID() string {
+	first := sha256.Sum256(tx.BytesWithoutUnlockingScripts())
+	second := sha256.Sum256(first[:])
+	return hex.EncodeToString(util.ReverseBytes(second[:]))
 }
 
 // String encodes the transaction into a hex string.
@@ -294,6 +558,46 @@ func (tx *Tx) ExtendedBytes() []byte {
 	return tx.toBytesHelper(0, nil, true)
 }
 
+// BytesWithoutUnlockingScripts encodes the transaction as Bytes does but with every
+// unlocking script (scriptSig) empty, so the structure of a tx can be shared, such
+// as a template while negotiating a collaborative tx, without revealing signatures.
+// NormalizedTxID is the hash of these bytes.
+//
+// The result is not a valid signed tx and cannot be broadcast.
+func (tx *Tx) BytesWithoutUnlockingScripts() []byte {
+	// an index of -1 with an empty locking script clears every input.
+	return tx.toBytesHelper(-1, []byte{}, false)
+}
+
+// HeaderBytes returns the fields of the tx outside its inputs and outputs: the
+// 4 byte little endian version, the varint input and output counts, and the 4 byte
+// little endian locktime, in that order. This is enough for light clients and
+// protocols which need the shape of a tx but not its body.
+func (tx *Tx) HeaderBytes() []byte {
+	b := make([]byte, 0, 8+VarInt(len(tx.Inputs)).Length()+VarInt(len(tx.Outputs)).Length())
+	b = binary.LittleEndian.AppendUint32(b, tx.Version)
+	b = append(b, VarInt(len(tx.Inputs)).Bytes()...)
+	b = append(b, VarInt(len(tx.Outputs)).Bytes()...)
+	return binary.LittleEndian.AppendUint32(b, tx.LockTime)
+}
+
+// Skeleton returns a copy of the tx with every unlocking and locking script
+// emptied, keeping the version, locktime, outpoints, sequence numbers and
+// satoshis, for analysing the shape and fixed size of a tx.
+//
+// The skeleton is not a valid tx and must not be broadcast; its txid differs
+// from that of the tx it was made from.
+func (tx *Tx) Skeleton() *Tx {
+	skeleton := tx.Clone()
+	for _, in := range skeleton.Inputs {
+		in.UnlockingScript = &bscript.Script{}
+	}
+	for _, out := range skeleton.Outputs {
+		out.LockingScript = &bscript.Script{}
+	}
+	return skeleton
+}
+
 // BytesWithClearedInputs encodes the transaction into a byte array but clears its Inputs first.
 // This is used when signing transactions.
 func (tx *Tx) BytesWithClearedInputs(index int, lockingScript []byte) []byte {
@@ -311,11 +615,73 @@ func (tx *Tx) Clone() *Tx {
 	for i, input := range tx.Inputs {
 		clone.Inputs[i].PreviousTxSatoshis = input.PreviousTxSatoshis
 		clone.Inputs[i].PreviousTxScript = input.PreviousTxScript
+		clone.Inputs[i].EstimatedUnlockingScriptSize = input.EstimatedUnlockingScriptSize
+	}
+
+	if tx.Metadata != nil {
+		clone.Metadata = make(map[string]string, len(tx.Metadata))
+		for k, v := range tx.Metadata {
+			clone.Metadata[k] = v
+		}
 	}
 
 	return clone
 }
 
+// Merge appends the inputs and outputs of other to the receiver, allowing a
+// collaborative transaction to be assembled from separately built parts.
+//
+// An input of other spending an outpoint already spent by the receiver is skipped
+// if it is identical (same previous satoshis, previous script and sequence number),
+// otherwise an ErrInputConflict error is returned and the receiver is left unchanged.
+// Previous output data is kept so the merged tx can still be signed.
+//
+// As the merged tx differs from both originals, any existing signatures are invalid,
+// so the unlocking scripts of all inputs are cleared and must be filled again.
+// The inputs and outputs of other are copied and other is not modified.
+func (tx *Tx) Merge(other *Tx) error {
+	if other == nil {
+		return ErrTxNil
+	}
+
+	spent := make(map[[36]byte]*Input, len(tx.Inputs)+len(other.Inputs))
+	for _, in := range tx.Inputs {
+		spent[in.OutpointKey()] = in
+	}
+
+	inputs := make([]*Input, 0, len(other.Inputs))
+	for i, in := range other.Inputs {
+		op := in.OutpointKey()
+		if prev, ok := spent[op]; ok {
+			if !sameSpend(prev, in) {
+				return fmt.Errorf("%w: input %d spends %s", ErrInputConflict, i, in.Outpoint())
+			}
+			continue
+		}
+		spent[op] = in
+
+		cp := *in
+		inputs = append(inputs, &cp)
+	}
+
+	tx.Inputs = append(tx.Inputs, inputs...)
+	for _, o := range other.Outputs {
+		cp := *o
+		tx.Outputs = append(tx.Outputs, &cp)
+	}
+	for _, in := range tx.Inputs {
+		in.UnlockingScript = nil
+	}
+
+	return nil
+}
+
+func sameSpend(a, b *Input) bool {
+	return a.PreviousTxSatoshis == b.PreviousTxSatoshis &&
+		a.SequenceNumber == b.SequenceNumber &&
+		equalScripts(a.PreviousTxScript, b.PreviousTxScript)
+}
+
 // NodeJSON returns a wrapped *bt.Tx for marshalling/unmarshalling into a node tx format.
 //
 // Marshalling usage example:
@@ -344,24 +710,55 @@ func (tt *Transactions) NodeJSON() interface{} {
 	return (*nodeTxsWrapper)(tt)
 }
 
+// WriteTo writes the serialised transaction to the `io.Writer`, implementing
+// the `io.WriterTo` interface. It returns the number of bytes written.
+func (tx *Tx) WriteTo(w io.Writer) (int64, error) {
+	return tx.writeTo(w, 0, nil, false)
+}
+
+// WriteToExtended writes the transaction to the `io.Writer` in extended format
+// (with PreviousTxSatoshis and PreviousTXScript included). It returns the number
+// of bytes written.
+func (tx *Tx) WriteToExtended(w io.Writer) (int64, error) {
+	return tx.writeTo(w, 0, nil, true)
+}
+
 func (tx *Tx) toBytesHelper(index int, lockingScript []byte, extended bool) []byte {
-	h := make([]byte, 0)
+	var buf bytes.Buffer
+	// writing to a bytes.Buffer never returns an error
+	_, _ = tx.writeTo(&buf, index, lockingScript, extended)
+	return buf.Bytes()
+}
 
-	h = append(h, util.LittleEndianBytes(tx.Version, 4)...)
+func (tx *Tx) writeTo(w io.Writer, index int, lockingScript []byte, extended bool) (int64, error) {
+	var bytesWritten int64
+	write := func(b []byte) error {
+		n, err := w.Write(b)
+		bytesWritten += int64(n)
+		return err
+	}
+
+	if err := write(util.LittleEndianBytes(tx.Version, 4)); err != nil {
+		return bytesWritten, err
+	}
 
 	if extended {
-		h = append(h, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0xEF}...)
+		if err := write([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0xEF}); err != nil {
+			return bytesWritten, err
+		}
 	}
 
-	h = append(h, VarInt(uint64(len(tx.Inputs))).Bytes()...)
+	if err := write(VarInt(uint64(len(tx.Inputs))).Bytes()); err != nil {
+		return bytesWritten, err
+	}
 
 	for i, in := range tx.Inputs {
-		s := in.Bytes(lockingScript != nil)
+		var h []byte
 		if i == index && lockingScript != nil {
 			h = append(h, VarInt(uint64(len(lockingScript))).Bytes()...)
 			h = append(h, lockingScript...)
 		} else {
-			h = append(h, s...)
+			h = in.Bytes(lockingScript != nil)
 		}
 
 		if extended {
@@ -377,17 +774,67 @@ func (tx *Tx) toBytesHelper(index int, lockingScript []byte, extended bool) []by
 				h = append(h, 0x00) // The length of the script is zero
 			}
 		}
+
+		if err := write(h); err != nil {
+			return bytesWritten, err
+		}
 	}
 
-	h = append(h, VarInt(uint64(len(tx.Outputs))).Bytes()...)
+	if err := write(VarInt(uint64(len(tx.Outputs))).Bytes()); err != nil {
+		return bytesWritten, err
+	}
 	for _, out := range tx.Outputs {
-		h = append(h, out.Bytes()...)
+		// write the locking script directly, rather than using out.Bytes(),
+		// to avoid copying large data scripts.
+		if err := write(out.SatoshiBytes()); err != nil {
+			return bytesWritten, err
+		}
+		if err := write(VarInt(uint64(len(*out.LockingScript))).Bytes()); err != nil {
+			return bytesWritten, err
+		}
+		if err := write(*out.LockingScript); err != nil {
+			return bytesWritten, err
+		}
 	}
 
 	lt := make([]byte, 4)
 	binary.LittleEndian.PutUint32(lt, tx.LockTime)
 
-	return append(h, lt...)
+	err := write(lt)
+	return bytesWritten, err
+}
+
+// CheckScriptSizes checks that no input unlocking script is larger than maxScriptSig
+// and no output locking script is larger than maxScriptPubKey. A limit of zero or less
+// falls back to DefaultMaxScriptSigSize or DefaultMaxScriptPubKeySize respectively.
+//
+// If a script is too large an ErrScriptTooLarge error is returned, detailing the
+// offending input or output index and the script size.
+func (tx *Tx) CheckScriptSizes(maxScriptSig, maxScriptPubKey int) error {
+	if maxScriptSig <= 0 {
+		maxScriptSig = DefaultMaxScriptSigSize
+	}
+	if maxScriptPubKey <= 0 {
+		maxScriptPubKey = DefaultMaxScriptPubKeySize
+	}
+
+	for i, in := range tx.Inputs {
+		if size := in.UnlockingScriptSize(); size > maxScriptSig {
+			return fmt.Errorf("%w: input %d unlocking script is %d bytes, limit is %d",
+				ErrScriptTooLarge, i, size, maxScriptSig)
+		}
+	}
+	for i, out := range tx.Outputs {
+		if out.LockingScript == nil {
+			continue
+		}
+		if size := len(*out.LockingScript); size > maxScriptPubKey {
+			return fmt.Errorf("%w: output %d locking script is %d bytes, limit is %d",
+				ErrScriptTooLarge, i, size, maxScriptPubKey)
+		}
+	}
+
+	return nil
 }
 
 // TxSize contains the size breakdown of a transaction
@@ -407,6 +854,29 @@ func (tx *Tx) Size() int {
 	return len(tx.Bytes())
 }
 
+// ExactSize returns the serialised size of tx in bytes, calculated from the
+// length of each component rather than by serialising the tx, so it always
+// equals len(tx.Bytes()). Unsigned inputs are counted as they are, with an
+// empty unlocking script; use EstimateSize to allow for their signatures.
+func (tx *Tx) ExactSize() int {
+	size := 4 + VarInt(len(tx.Inputs)).Length()
+	for _, in := range tx.Inputs {
+		scriptLen := in.UnlockingScriptSize()
+		size += 32 + 4 + VarInt(scriptLen).Length() + scriptLen + 4
+	}
+
+	size += VarInt(len(tx.Outputs)).Length()
+	for _, out := range tx.Outputs {
+		scriptLen := 0
+		if out.LockingScript != nil {
+			scriptLen = len(*out.LockingScript)
+		}
+		size += 8 + VarInt(scriptLen).Length() + scriptLen
+	}
+
+	return size + 4
+}
+
 // SizeWithTypes will return the size of tx in bytes
 // and include the different data types (std/data/etc.).
 func (tx *Tx) SizeWithTypes() *TxSize {
@@ -426,9 +896,9 @@ func (tx *Tx) SizeWithTypes() *TxSize {
 	}
 }
 
-// EstimateSize will return the size of tx in bytes and will add 107 bytes
-// to the unlocking script of any unsigned inputs (only P2PKH for now) found
-// to give a final size estimate of the tx size.
+// EstimateSize will return the size of tx in bytes and will add an estimated
+// unlocking script to any unsigned inputs found to give a final size estimate
+// of the tx size. See estimateUnlockingScriptSize for the sizes assumed.
 func (tx *Tx) EstimateSize() (int, error) {
 	tempTx, err := tx.estimatedFinalTx()
 	if err != nil {
@@ -439,9 +909,8 @@ func (tx *Tx) EstimateSize() (int, error) {
 }
 
 // EstimateSizeWithTypes will return the size of tx in bytes, including the
-// different data types (std/data/etc.), and will add 107 bytes to the unlocking
-// script of any unsigned inputs (only P2PKH for now) found to give a final size
-// estimate of the tx size.
+// different data types (std/data/etc.), and will add an estimated unlocking
+// script to any unsigned inputs found to give a final size estimate of the tx size.
 func (tx *Tx) EstimateSizeWithTypes() (*TxSize, error) {
 	tempTx, err := tx.estimatedFinalTx()
 	if err != nil {
@@ -458,18 +927,57 @@ func (tx *Tx) estimatedFinalTx() (*Tx, error) {
 		if in.PreviousTxScript == nil {
 			return nil, fmt.Errorf("%w at index %d in order to calc expected UnlockingScript", ErrEmptyPreviousTxScript, i)
 		}
-		if !(in.PreviousTxScript.IsP2PKH() || in.PreviousTxScript.IsP2PKHInscription()) {
-			return nil, ErrUnsupportedScript
+		if in.UnlockingScript != nil && len(*in.UnlockingScript) > 0 {
+			continue
 		}
-		if in.UnlockingScript == nil || len(*in.UnlockingScript) == 0 {
-			//nolint:lll // insert dummy p2pkh unlocking script (sig + pubkey)
-			dummyUnlockingScript, _ := hex.DecodeString("4830450221009c13cbcbb16f2cfedc7abf3a4af1c3fe77df1180c0e7eee30d9bcc53ebda39da02207b258005f1bc3cf9dffa06edb358d6db2bcfc87f50516fac8e3f4686fc2a03df412103107feff22788a1fc8357240bf450fd7bca4bd45d5f8bac63818c5a7b67b03876")
-			in.UnlockingScript = bscript.NewFromBytes(dummyUnlockingScript)
+
+		size := int(in.EstimatedUnlockingScriptSize)
+		if size == 0 {
+			var err error
+			if size, err = estimateUnlockingScriptSize(in.PreviousTxScript); err != nil {
+				return nil, err
+			}
 		}
+		// insert a dummy unlocking script of the estimated size
+		in.UnlockingScript = bscript.NewFromBytes(make([]byte, size))
 	}
 	return tempTx, nil
 }
 
+// The sizes of a pushed signature, at most 72 bytes including the sighash flag,
+// and of a P2PKH unlocking script, a signature and compressed public key.
+const (
+	sigPushSize              = 1 + 72
+	p2pkhUnlockingScriptSize = sigPushSize + 1 + 33
+)
+
+// estimateUnlockingScriptSize returns the expected size of the unlocking script
+// for the locking script provided:
+//
+//   - P2PKH: a signature and compressed public key, 107 bytes.
+//   - P2PK: a signature, 73 bytes.
+//   - multisig: OP_0 followed by a signature for each required key, 1 + 73 bytes per signature.
+//
+// Signatures are assumed to be at most 72 bytes, including the sighash flag, plus a
+// 1 byte push. ErrUnsupportedScript is returned for any other script type, in which
+// case the input's EstimatedUnlockingScriptSize can be set.
+func estimateUnlockingScriptSize(lockingScript *bscript.Script) (int, error) {
+	switch {
+	case lockingScript.IsP2PKH(), lockingScript.IsP2PKHInscription():
+		return p2pkhUnlockingScriptSize, nil
+	case lockingScript.IsP2PK():
+		return sigPushSize, nil
+	case lockingScript.IsMultiSigOut():
+		m, _, err := lockingScript.MultisigInfo()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrUnsupportedScript, err)
+		}
+		return 1 + m*sigPushSize, nil
+	}
+
+	return 0, ErrUnsupportedScript
+}
+
 // TxFees is returned when CalculateFee is called and contains
 // a breakdown of the fees including the total and the size breakdown of
 // the tx in bytes.
@@ -489,8 +997,10 @@ func (tx *Tx) IsFeePaidEnough(fees *FeeQuote) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	totalInputSatoshis := tx.TotalInputSatoshis()
-	totalOutputSatoshis := tx.TotalOutputSatoshis()
+	totalInputSatoshis, totalOutputSatoshis, err := tx.totalSatoshisChecked()
+	if err != nil {
+		return false, err
+	}
 
 	if totalInputSatoshis < totalOutputSatoshis {
 		return false, nil
@@ -513,8 +1023,10 @@ func (tx *Tx) EstimateIsFeePaidEnough(fees *FeeQuote) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	totalInputSatoshis := tempTx.TotalInputSatoshis()
-	totalOutputSatoshis := tempTx.TotalOutputSatoshis()
+	totalInputSatoshis, totalOutputSatoshis, err := tempTx.totalSatoshisChecked()
+	if err != nil {
+		return false, err
+	}
 
 	if totalInputSatoshis < totalOutputSatoshis {
 		return false, nil
@@ -535,6 +1047,67 @@ func (tx *Tx) EstimateFeesPaid(fees *FeeQuote) (*TxFees, error) {
 	return tx.feesPaid(size, fees)
 }
 
+// FeeBreakdown returns the fee the FeeQuote charges for the tx as it is, split
+// into the fee for its standard bytes and the fee for its data bytes, the locking
+// scripts of data (OP_RETURN) outputs, classified as SizeWithTypes. Every other
+// byte, including all input bytes, is standard. The total fee is their sum.
+//
+// Unsigned inputs are counted with their current unlocking scripts; use
+// EstimateFeesPaid to allow for their signatures.
+func (tx *Tx) FeeBreakdown(fq *FeeQuote) (standardFee, dataFee uint64, err error) {
+	fees, err := tx.feesPaid(tx.SizeWithTypes(), fq)
+	if err != nil {
+		return 0, 0, err
+	}
+	return fees.StdFeePaid, fees.DataFeePaid, nil
+}
+
+// AssertFeeWithin checks that the fee paid by the tx, its input satoshis less its
+// output satoshis, is within tolerancePercent of the fee expected from the FeeQuote,
+// as a guard against change calculation mistakes sending funds to miners. The
+// expected fee allows for the unlocking scripts of unsigned inputs, as EstimateFeesPaid.
+//
+// Every input must have its previous satoshis set, otherwise ErrInputSatsZero is
+// returned. If the fee is outside the tolerance a *FeeOutOfRangeError detailing the
+// actual and expected fees is returned, matching ErrFeeOutOfRange with errors.Is.
+func (tx *Tx) AssertFeeWithin(fq *FeeQuote, tolerancePercent float64) error {
+	if tolerancePercent < 0 || math.IsNaN(tolerancePercent) {
+		return fmt.Errorf("%w: %g", ErrInvalidFeeTolerance, tolerancePercent)
+	}
+	for i, in := range tx.Inputs {
+		if in.PreviousTxSatoshis == 0 {
+			return fmt.Errorf("%w: input %d", ErrInputSatsZero, i)
+		}
+	}
+
+	expected, err := tx.EstimateFeesPaid(fq)
+	if err != nil {
+		return err
+	}
+	totalInputSatoshis, totalOutputSatoshis, err := tx.totalSatoshisChecked()
+	if err != nil {
+		return err
+	}
+	if totalInputSatoshis < totalOutputSatoshis {
+		return ErrInsufficientInputs
+	}
+
+	actual := totalInputSatoshis - totalOutputSatoshis
+	diff := actual - expected.TotalFeePaid
+	if actual < expected.TotalFeePaid {
+		diff = expected.TotalFeePaid - actual
+	}
+	if float64(diff) > float64(expected.TotalFeePaid)*tolerancePercent/100 {
+		return &FeeOutOfRangeError{
+			Actual:           actual,
+			Expected:         expected.TotalFeePaid,
+			TolerancePercent: tolerancePercent,
+		}
+	}
+
+	return nil
+}
+
 func (tx *Tx) feesPaid(size *TxSize, fees *FeeQuote) (*TxFees, error) {
 	// get fees
 	stdFee, err := fees.Fee(FeeTypeStandard)
@@ -546,26 +1119,47 @@ func (tx *Tx) feesPaid(size *TxSize, fees *FeeQuote) (*TxFees, error) {
 		return nil, err
 	}
 
-	txFees := &TxFees{
-		StdFeePaid:  size.TotalStdBytes * uint64(stdFee.MiningFee.Satoshis) / uint64(stdFee.MiningFee.Bytes),
-		DataFeePaid: size.TotalDataBytes * uint64(dataFee.MiningFee.Satoshis) / uint64(dataFee.MiningFee.Bytes),
+	txFees := &TxFees{}
+	if txFees.StdFeePaid, err = mulDivSatoshis(size.TotalStdBytes, uint64(stdFee.MiningFee.Satoshis), uint64(stdFee.MiningFee.Bytes)); err != nil {
+		return nil, err
+	}
+	if txFees.DataFeePaid, err = mulDivSatoshis(size.TotalDataBytes, uint64(dataFee.MiningFee.Satoshis), uint64(dataFee.MiningFee.Bytes)); err != nil {
+		return nil, err
+	}
+	if txFees.TotalFeePaid, err = addSatoshis(txFees.StdFeePaid, txFees.DataFeePaid); err != nil {
+		return nil, err
 	}
-	txFees.TotalFeePaid = txFees.StdFeePaid + txFees.DataFeePaid
 	return txFees, nil
 }
 
-func (tx *Tx) estimateDeficit(fees *FeeQuote) (uint64, error) {
-	totalInputSatoshis := tx.TotalInputSatoshis()
-	totalOutputSatoshis := tx.TotalOutputSatoshis()
+// estimateDeficit returns the satoshis still needed to cover the outputs and the
+// estimated fees. extraStdBytes are added to the estimated size as standard bytes,
+// allowing for outputs which are yet to be added, such as change.
+func (tx *Tx) estimateDeficit(fees *FeeQuote, extraStdBytes uint64) (uint64, error) {
+	totalInputSatoshis, totalOutputSatoshis, err := tx.totalSatoshisChecked()
+	if err != nil {
+		return 0, err
+	}
 
-	expFeesPaid, err := tx.EstimateFeesPaid(fees)
+	size, err := tx.EstimateSizeWithTypes()
 	if err != nil {
 		return 0, err
 	}
+	size.TotalBytes += extraStdBytes
+	size.TotalStdBytes += extraStdBytes
 
-	if totalInputSatoshis > totalOutputSatoshis+expFeesPaid.TotalFeePaid {
+	expFeesPaid, err := tx.feesPaid(size, fees)
+	if err != nil {
+		return 0, err
+	}
+
+	required, err := addSatoshis(totalOutputSatoshis, expFeesPaid.TotalFeePaid)
+	if err != nil {
+		return 0, err
+	}
+	if totalInputSatoshis > required {
 		return 0, nil
 	}
 
-	return totalOutputSatoshis + expFeesPaid.TotalFeePaid - totalInputSatoshis, nil
+	return required - totalInputSatoshis, nil
 }
@@ -0,0 +1,689 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/sighash"
+	"github.com/bitcoin-sv/go-sdk/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_CheckScriptSizes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		scriptSigLen       int
+		scriptPubKeyLen    int
+		maxScriptSigSize   int
+		maxScriptPubKeyLen int
+		expErr             error
+		expErrMsgs         []string
+	}{
+		"within default limits": {
+			scriptSigLen:    107,
+			scriptPubKeyLen: 25,
+		},
+		"unlocking script too large": {
+			scriptSigLen:     200,
+			scriptPubKeyLen:  25,
+			maxScriptSigSize: 100,
+			expErr:           ErrScriptTooLarge,
+			expErrMsgs:       []string{"input 0", "200 bytes"},
+		},
+		"locking script too large": {
+			scriptSigLen:    107,
+			scriptPubKeyLen: DefaultMaxScriptPubKeySize + 1,
+			expErr:          ErrScriptTooLarge,
+			expErrMsgs:      []string{"output 0"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := NewTx()
+			tx.Inputs = append(tx.Inputs, &Input{UnlockingScript: bscript.NewFromBytes(make([]byte, test.scriptSigLen))})
+			tx.AddOutput(&Output{LockingScript: bscript.NewFromBytes(make([]byte, test.scriptPubKeyLen))})
+
+			err := tx.CheckScriptSizes(test.maxScriptSigSize, test.maxScriptPubKeyLen)
+			assert.ErrorIs(t, err, test.expErr)
+			for _, msg := range test.expErrMsgs {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestTx_ExactSize(t *testing.T) {
+	t.Parallel()
+
+	signed, err := NewTxFromHex("0100000001abad53d72f342dd3f338e5e3346b492440f8ea821f8b8800e318f461cc5ea5a2010000006a4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8ffffffff02000000000000000008006a0548656c6c6f7f030000000000001976a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac00000000")
+	assert.NoError(t, err)
+
+	unsigned := NewTx()
+	assert.NoError(t, unsigned.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 0, "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac", 2000000))
+	assert.NoError(t, unsigned.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+
+	large := NewTx()
+	for i := 0; i < 300; i++ {
+		large.Inputs = append(large.Inputs, &Input{
+			previousTxID:    make([]byte, 32),
+			UnlockingScript: bscript.NewFromBytes(make([]byte, 300)),
+		})
+	}
+	assert.NoError(t, large.AddOpReturnOutput(make([]byte, 70000)))
+
+	tests := map[string]*Tx{
+		"empty":    NewTx(),
+		"signed":   signed,
+		"unsigned": unsigned,
+		"large":    large,
+	}
+	for name, tx := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, len(tx.Bytes()), tx.ExactSize())
+		})
+	}
+}
+
+func TestTx_EstimateSize_UnlockingScripts(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*ec.PrivateKey, 3)
+	multisig := &bscript.Script{}
+	assert.NoError(t, multisig.AppendOpcodes(bscript.Op2))
+	for i := range keys {
+		keys[i], _ = ec.PrivateKeyFromBytes(crypto.Sha256([]byte{byte(i)}))
+		assert.NoError(t, multisig.AppendPushData(keys[i].PubKey().SerialiseCompressed()))
+	}
+	assert.NoError(t, multisig.AppendOpcodes(bscript.Op3, bscript.OpCHECKMULTISIG))
+	p2pk := &bscript.Script{}
+	assert.NoError(t, p2pk.AppendPushData(keys[0].PubKey().SerialiseCompressed()))
+	assert.NoError(t, p2pk.AppendOpcodes(bscript.OpCHECKSIG))
+
+	tests := map[string]struct {
+		lockingScript *bscript.Script
+		estimatedSize uint32
+		expExtra      int
+		expErr        error
+	}{
+		"multisig": {
+			lockingScript: multisig,
+			expExtra:      1 + 2*73,
+		},
+		"p2pk": {
+			lockingScript: p2pk,
+			expExtra:      73,
+		},
+		"unsupported script": {
+			lockingScript: bscript.NewFromBytes([]byte{bscript.OpTRUE}),
+			expErr:        ErrUnsupportedScript,
+		},
+		"unsupported script with an estimate": {
+			lockingScript: bscript.NewFromBytes([]byte{bscript.OpTRUE}),
+			estimatedSize: 300,
+			expExtra:      300 + 2,
+		},
+		"override": {
+			lockingScript: multisig,
+			estimatedSize: 10,
+			expExtra:      10,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := NewTx()
+			assert.NoError(t, tx.FromUTXOs(&UTXO{
+				TxID:          make([]byte, 32),
+				LockingScript: test.lockingScript,
+				Satoshis:      10000,
+			}))
+			assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 9000))
+			tx.Inputs[0].EstimatedUnlockingScriptSize = test.estimatedSize
+
+			estimate, err := tx.EstimateSize()
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tx.Size()+test.expExtra, estimate)
+		})
+	}
+
+	t.Run("multisig covers a signed input", func(t *testing.T) {
+		tx := NewTx()
+		assert.NoError(t, tx.FromUTXOs(&UTXO{TxID: make([]byte, 32), LockingScript: multisig, Satoshis: 10000}))
+		assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 9000))
+		estimate, err := tx.EstimateSize()
+		assert.NoError(t, err)
+
+		sh, err := tx.CalcInputSignatureHash(0, sighash.AllForkID)
+		assert.NoError(t, err)
+		unlocking := &bscript.Script{}
+		assert.NoError(t, unlocking.AppendOpcodes(bscript.OpZERO))
+		for _, k := range keys[:2] {
+			sig, err := k.Sign(sh)
+			assert.NoError(t, err)
+			assert.NoError(t, unlocking.AppendPushData(append(sig.Serialise(), byte(sighash.AllForkID))))
+		}
+		tx.Inputs[0].UnlockingScript = unlocking
+
+		assert.LessOrEqual(t, tx.Size(), estimate)
+		assert.GreaterOrEqual(t, tx.Size(), estimate-6)
+
+		signedEstimate, err := tx.EstimateSize()
+		assert.NoError(t, err)
+		assert.Equal(t, tx.Size(), signedEstimate)
+	})
+}
+
+func TestNewTxFromBytes_MaliciousCounts(t *testing.T) {
+	input := "abad53d72f342dd3f338e5e3346b492440f8ea821f8b8800e318f461cc5ea5a2" + "01000000" + "00" + "ffffffff"
+
+	t.Run("huge input count", func(t *testing.T) {
+		b, err := hex.DecodeString("01000000" + "ffffffffffffffffff" + input)
+		assert.NoError(t, err)
+
+		_, err = NewTxFromBytes(b)
+		assert.ErrorIs(t, err, ErrMalformedTx)
+		assert.ErrorIs(t, err, ErrTxTooManyInputs)
+	})
+
+	t.Run("huge output count", func(t *testing.T) {
+		b, err := hex.DecodeString("01000000" + "01" + input + "feffffffff" + "0000000000000000" + "00")
+		assert.NoError(t, err)
+
+		_, err = NewTxFromBytes(b)
+		assert.ErrorIs(t, err, ErrMalformedTx)
+		assert.ErrorIs(t, err, ErrTxTooManyOutputs)
+	})
+
+	t.Run("huge script length from an unsized reader", func(t *testing.T) {
+		b, err := hex.DecodeString("01000000" + "01" + input[:72] + "feffffff7f" + "0000")
+		assert.NoError(t, err)
+
+		tx := &Tx{}
+		allocs := testing.AllocsPerRun(1, func() {
+			_, err = tx.ReadFrom(io.MultiReader(bytes.NewReader(b)))
+		})
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Less(t, allocs, float64(50))
+	})
+
+	t.Run("huge tx count", func(t *testing.T) {
+		var txs Transactions
+		_, err := txs.ReadFrom(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}))
+		assert.ErrorIs(t, err, ErrMalformedTx)
+	})
+}
+
+func largeDataTx(t testing.TB, size int) *Tx {
+	tx := NewTx()
+	assert.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 0, "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac", 2000000))
+	assert.NoError(t, tx.AddOpReturnOutput(make([]byte, size)))
+	assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+	return tx
+}
+
+func TestTx_TxIDBytes(t *testing.T) {
+	t.Parallel()
+
+	for name, tx := range map[string]*Tx{
+		"empty": NewTx(),
+		"large": largeDataTx(t, 5_000_000),
+	} {
+		t.Run(name, func(t *testing.T) {
+			exp := util.ReverseBytes(crypto.Sha256d(tx.Bytes()))
+			assert.Equal(t, exp, tx.TxIDBytes())
+			assert.Equal(t, hex.EncodeToString(exp), tx.TxID())
+		})
+	}
+}
+
+func TestTx_HeaderBytes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "01000000000000000000", hex.EncodeToString(NewTx().HeaderBytes()))
+
+	tx := NewTx()
+	tx.Version = 2
+	tx.LockTime = 0x01020304
+	assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 1000))
+	assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 500))
+	assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 400))
+	assert.Equal(t, "02000000010204030201", hex.EncodeToString(tx.HeaderBytes()))
+}
+
+func TestTx_Skeleton(t *testing.T) {
+	t.Parallel()
+
+	tx := NewTx()
+	tx.LockTime = 100
+	assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 1000))
+	tx.Inputs[0].UnlockingScript = bscript.NewFromBytes(make([]byte, 107))
+	tx.Inputs[0].SequenceNumber = 1
+	assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 500))
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+	txID := tx.TxID()
+
+	skeleton := tx.Skeleton()
+	assert.Equal(t, txID, tx.TxID())
+	assert.NotEqual(t, txID, skeleton.TxID())
+	assert.Equal(t, tx.HeaderBytes(), skeleton.HeaderBytes())
+	assert.Equal(t, 10+41+2*9, skeleton.Size())
+
+	assert.Equal(t, tx.Inputs[0].PreviousTxIDStr(), skeleton.Inputs[0].PreviousTxIDStr())
+	assert.Equal(t, uint32(1), skeleton.Inputs[0].SequenceNumber)
+	assert.Zero(t, skeleton.Inputs[0].UnlockingScriptSize())
+	assert.Equal(t, uint64(500), skeleton.Outputs[0].Satoshis)
+	for _, out := range skeleton.Outputs {
+		assert.Empty(t, *out.LockingScript)
+	}
+}
+
+func TestTx_BytesWithoutUnlockingScripts(t *testing.T) {
+	t.Parallel()
+
+	tx := NewTx()
+	for vout := uint32(0); vout < 2; vout++ {
+		assert.NoError(t, tx.From(
+			"3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+			vout,
+			"76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac",
+			10000,
+		))
+	}
+	assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 9000))
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+	tx.LockTime = 800000
+	tx.Inputs[1].SequenceNumber = 0xfffffffe
+	unsigned := tx.Bytes()
+	for _, in := range tx.Inputs {
+		in.UnlockingScript = bscript.NewFromBytes(bytes.Repeat([]byte{0x51}, 107))
+	}
+
+	b := tx.BytesWithoutUnlockingScripts()
+	assert.Equal(t, unsigned, b)
+	assert.Len(t, b, len(tx.Bytes())-2*107)
+	assert.Equal(t, hex.EncodeToString(util.ReverseBytes(crypto.Sha256d(b))), tx.NormalizedTxID())
+
+	parsed, err := NewTxFromBytes(b)
+	assert.NoError(t, err)
+	assert.Len(t, parsed.Inputs, 2)
+	for i, in := range parsed.Inputs {
+		assert.Zero(t, in.UnlockingScriptSize())
+		assert.Equal(t, tx.Inputs[i].PreviousTxIDStr(), in.PreviousTxIDStr())
+		assert.Equal(t, tx.Inputs[i].PreviousTxOutIndex, in.PreviousTxOutIndex)
+		assert.Equal(t, tx.Inputs[i].SequenceNumber, in.SequenceNumber)
+	}
+	assert.Equal(t, tx.Outputs, parsed.Outputs)
+	assert.Equal(t, tx.LockTime, parsed.LockTime)
+
+	// the tx itself is not modified
+	assert.Len(t, *tx.Inputs[0].UnlockingScript, 107)
+}
+
+func TestTx_NormalizedTxID(t *testing.T) {
+	t.Parallel()
+
+	tx := NewTx()
+	assert.NoError(t, tx.From(
+		"3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+		0,
+		"76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac",
+		10000,
+	))
+	assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 9000))
+
+	unsignedID := tx.NormalizedTxID()
+	assert.Equal(t, tx.TxID(), unsignedID)
+
+	key, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	sign := func(flag sighash.Flag) {
+		sh, err := tx.CalcInputSignatureHash(0, flag)
+		assert.NoError(t, err)
+		sig, err := key.Sign(sh)
+		assert.NoError(t, err)
+
+		s := &bscript.Script{}
+		assert.NoError(t, s.AppendPushData(append(sig.Serialise(), byte(flag))))
+		assert.NoError(t, s.AppendPushData(key.PubKey().SerialiseCompressed()))
+		tx.Inputs[0].UnlockingScript = s
+	}
+
+	sign(sighash.AllForkID)
+	firstID := tx.TxID()
+	assert.NotEqual(t, unsignedID, firstID)
+	assert.Equal(t, unsignedID, tx.NormalizedTxID())
+
+	sign(sighash.NoneForkID)
+	assert.NotEqual(t, firstID, tx.TxID())
+	assert.Equal(t, unsignedID, tx.NormalizedTxID())
+
+	tx.Outputs[0].Satoshis--
+	assert.NotEqual(t, unsignedID, tx.NormalizedTxID())
+}
+
+func BenchmarkTx_TxIDBytes(b *testing.B) {
+	tx := largeDataTx(b, 5_000_000)
+
+	b.Run("streamed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = tx.TxIDBytes()
+		}
+	})
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = util.ReverseBytes(crypto.Sha256d(tx.Bytes()))
+		}
+	})
+}
+
+func TestTx_IsFinal(t *testing.T) {
+	t.Parallel()
+
+	blockTime := time.Unix(1700000000, 0)
+	tests := map[string]struct {
+		lockTime uint32
+		sequence uint32
+		exp      bool
+	}{
+		"zero locktime is final": {
+			lockTime: 0, sequence: 0, exp: true,
+		},
+		"height locktime in the past is final": {
+			lockTime: 799999, sequence: 0, exp: true,
+		},
+		"height locktime in the future is not final": {
+			lockTime: 800000, sequence: 0, exp: false,
+		},
+		"height locktime in the future with final sequences is final": {
+			lockTime: 800000, sequence: MaxTxInSequenceNum, exp: true,
+		},
+		"time locktime in the past is final": {
+			lockTime: 1699999999, sequence: 0, exp: true,
+		},
+		"time locktime in the future is not final": {
+			lockTime: 1700000000, sequence: 0, exp: false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := NewTx()
+			tx.LockTime = test.lockTime
+			tx.Inputs = append(tx.Inputs, &Input{SequenceNumber: test.sequence})
+			assert.Equal(t, test.exp, tx.IsFinal(800000, blockTime))
+		})
+	}
+}
+
+func TestTx_ValidateLockTimeEffective(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		lockTime  uint32
+		sequences []uint32
+		expErr    bool
+	}{
+		"zero locktime": {
+			lockTime: 0, sequences: []uint32{MaxTxInSequenceNum},
+		},
+		"locktime with a non-final input": {
+			lockTime: 800000, sequences: []uint32{MaxTxInSequenceNum, MaxTxInSequenceNum - 1},
+		},
+		"locktime with only final inputs": {
+			lockTime: 800000, sequences: []uint32{MaxTxInSequenceNum, MaxTxInSequenceNum}, expErr: true,
+		},
+		"locktime with no inputs": {
+			lockTime: 800000, expErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := NewTx()
+			tx.LockTime = test.lockTime
+			for _, seq := range test.sequences {
+				tx.Inputs = append(tx.Inputs, &Input{SequenceNumber: seq})
+			}
+			err := tx.ValidateLockTimeEffective()
+			if test.expErr {
+				assert.ErrorIs(t, err, ErrLockTimeIneffective)
+				assert.Contains(t, err.Error(), "below 0xFFFFFFFF")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+type failingWriter struct {
+	limit int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > f.limit {
+		n := f.limit
+		f.limit = 0
+		return n, errors.New("write limit reached")
+	}
+	f.limit -= len(p)
+	return len(p), nil
+}
+
+func TestTx_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	tx := NewTx()
+	assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 1000))
+	assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 900))
+
+	t.Run("standard format", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := tx.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(tx.Size()), n)
+		assert.Equal(t, tx.Bytes(), buf.Bytes())
+	})
+
+	t.Run("extended format", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := tx.WriteToExtended(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(tx.ExtendedBytes())), n)
+		assert.Equal(t, tx.ExtendedBytes(), buf.Bytes())
+	})
+
+	t.Run("writer error is returned with bytes written", func(t *testing.T) {
+		n, err := tx.WriteTo(&failingWriter{limit: 10})
+		assert.Error(t, err)
+		assert.Equal(t, int64(10), n)
+	})
+}
+
+func TestTx_Merge(t *testing.T) {
+	t.Parallel()
+
+	const (
+		txID   = "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5"
+		script = "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac"
+	)
+
+	type input struct {
+		vout     uint32
+		satoshis uint64
+	}
+	tests := map[string]struct {
+		tx, other input
+		expErr    error
+		expInputs int
+		expTotal  uint64
+	}{
+		"appends inputs and outputs": {
+			tx:        input{vout: 0, satoshis: 2000},
+			other:     input{vout: 1, satoshis: 3000},
+			expInputs: 2,
+			expTotal:  5000,
+		},
+		"identical inputs are deduplicated": {
+			tx:        input{vout: 0, satoshis: 2000},
+			other:     input{vout: 0, satoshis: 2000},
+			expInputs: 1,
+			expTotal:  2000,
+		},
+		"conflicting inputs": {
+			tx:        input{vout: 0, satoshis: 2000},
+			other:     input{vout: 0, satoshis: 2500},
+			expErr:    ErrInputConflict,
+			expInputs: 1,
+			expTotal:  2000,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			txs := make([]*Tx, 2)
+			for i, in := range []input{test.tx, test.other} {
+				txs[i] = NewTx()
+				assert.NoError(t, txs[i].From(txID, in.vout, script, in.satoshis))
+				txs[i].Inputs[0].UnlockingScript = bscript.NewFromBytes([]byte{0x51})
+				assert.NoError(t, txs[i].PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+			}
+			tx, other := txs[0], txs[1]
+
+			err := tx.Merge(other)
+			assert.ErrorIs(t, err, test.expErr)
+			assert.Equal(t, test.expInputs, tx.InputCount())
+			assert.Equal(t, test.expTotal, tx.TotalInputSatoshis())
+			assert.NotNil(t, other.Inputs[0].UnlockingScript)
+			if test.expErr != nil {
+				// a failed merge leaves the tx as it was
+				assert.Equal(t, 1, tx.OutputCount())
+				assert.NotNil(t, tx.Inputs[0].UnlockingScript)
+				return
+			}
+			assert.Equal(t, 2, tx.OutputCount())
+			for _, in := range tx.Inputs {
+				assert.Equal(t, script, in.PreviousTxScript.String())
+				// merging invalidates any signatures
+				assert.Nil(t, in.UnlockingScript)
+			}
+		})
+	}
+}
+
+func TestTx_DataByteCount(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dataOutputs [][][]byte
+		exp         int
+	}{
+		"no data outputs": {},
+		"single data output": {
+			dataOutputs: [][][]byte{{[]byte("hello")}},
+			// a one byte push opcode and the five bytes pushed
+			exp: 6,
+		},
+		"multiple data outputs": {
+			dataOutputs: [][][]byte{{[]byte("hello")}, {[]byte("a"), bytes.Repeat([]byte{0x01}, 100)}},
+			// pushes of 1 byte, and of 100 bytes with OP_PUSHDATA1
+			exp: 6 + (2 + 102),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := NewTx()
+			assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+			for _, parts := range test.dataOutputs {
+				assert.NoError(t, tx.AddOpReturnPartsOutput(parts))
+			}
+			assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+			assert.Equal(t, test.exp, tx.DataByteCount())
+		})
+	}
+
+	t.Run("empty tx", func(t *testing.T) {
+		assert.Zero(t, NewTx().DataByteCount())
+	})
+
+	t.Run("OP_RETURN without OP_FALSE", func(t *testing.T) {
+		tx := NewTx()
+		opReturn, err := bscript.NewFromHex("6a0568656c6c6f")
+		assert.NoError(t, err)
+		tx.AddOutput(&Output{LockingScript: opReturn})
+		assert.Equal(t, 6, tx.DataByteCount())
+	})
+}
+
+func TestTx_Version(t *testing.T) {
+	t.Parallel()
+
+	const script = "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac"
+
+	tests := map[string]struct {
+		seq         uint32
+		expRelative bool
+	}{
+		"final":                 {seq: DefaultSequenceNumber},
+		"absolute locktime":     {seq: 0xfffffffe},
+		"zero":                  {seq: 0},
+		"disabled flag":         {seq: SequenceLockTimeDisabled | 10},
+		"relative block height": {seq: 10, expRelative: true},
+		"relative time":         {seq: SequenceLockTimeIsSeconds | 10, expRelative: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := NewTx()
+			assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, script, 1000))
+			tx.Inputs[0].SequenceNumber = test.seq
+			assert.Equal(t, test.expRelative, tx.HasRelativeLockTime())
+
+			tx.SetVersion(1)
+			if test.expRelative {
+				assert.Equal(t, MinVersionRelativeLockTime, tx.Version)
+			} else {
+				assert.Equal(t, uint32(1), tx.Version)
+			}
+			assert.NoError(t, tx.RequireMinVersion(1))
+
+			tx.Version = 1
+			err := tx.RequireMinVersion(1)
+			if test.expRelative {
+				assert.ErrorIs(t, err, ErrTxVersionTooLow)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("require min version", func(t *testing.T) {
+		tx := NewTx()
+		assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, script, 1000))
+		tx.SetVersion(3)
+		assert.Equal(t, uint32(3), tx.Version)
+		assert.NoError(t, tx.RequireMinVersion(3))
+		assert.ErrorIs(t, tx.RequireMinVersion(4), ErrTxVersionTooLow)
+	})
+
+	t.Run("from utxos with relative sequence", func(t *testing.T) {
+		lockingScript, err := bscript.NewFromHex(script)
+		assert.NoError(t, err)
+		utxo := &UTXO{TxID: bytes.Repeat([]byte{0x01}, 32), LockingScript: lockingScript, Satoshis: 1000}
+
+		tx := NewTx()
+		assert.NoError(t, tx.FromUTXOsWithSequence(0xfffffffe, utxo))
+		assert.Equal(t, uint32(1), tx.Version)
+		assert.NoError(t, tx.FromUTXOsWithSequence(10, utxo))
+		assert.Equal(t, MinVersionRelativeLockTime, tx.Version)
+	})
+}
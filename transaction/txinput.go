@@ -6,9 +6,11 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
 	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
 	"github.com/bitcoin-sv/go-sdk/sighash"
 	"github.com/bitcoin-sv/go-sdk/util"
 	"github.com/pkg/errors"
@@ -24,6 +26,9 @@ import (
 type UTXOGetterFunc func(ctx context.Context, deficit uint64) ([]*UTXO, error)
 
 // TotalInputSatoshis returns the total Satoshis inputted to the transaction.
+//
+// The total wraps if it overflows, use TotalInputSatoshisChecked for transactions
+// from untrusted sources.
 func (tx *Tx) TotalInputSatoshis() (total uint64) {
 	for _, in := range tx.Inputs {
 		total += in.PreviousTxSatoshis
@@ -31,6 +36,19 @@ func (tx *Tx) TotalInputSatoshis() (total uint64) {
 	return
 }
 
+// TotalInputSatoshisChecked returns the total Satoshis inputted to the transaction,
+// or ErrSatoshiOverflow if the total overflows.
+func (tx *Tx) TotalInputSatoshisChecked() (uint64, error) {
+	var total uint64
+	var err error
+	for _, in := range tx.Inputs {
+		if total, err = addSatoshis(total, in.PreviousTxSatoshis); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
 func (tx *Tx) addInput(input *Input) {
 	tx.Inputs = append(tx.Inputs, input)
 }
@@ -61,9 +79,31 @@ func (tx *Tx) AddP2PKHInputsFromTx(pvsTx *Tx, matchPK []byte) error {
 	return nil
 }
 
+// FromTx adds a new input to the transaction spending output vout of the in-memory
+// previous transaction, taking its locking script and satoshis from prevTx. This allows
+// chaining transactions without prevTx having been broadcast. As with From, the default
+// finalised sequence number is used.
+//
+// If prevTx has no output at vout an ErrOutputNoExist error is returned.
+func (tx *Tx) FromTx(prevTx *Tx, vout uint32) error {
+	if prevTx == nil {
+		return ErrTxNil
+	}
+	if int(vout) >= prevTx.OutputCount() {
+		return errors.Wrapf(ErrOutputNoExist, "vout %d, tx has %d outputs", vout, prevTx.OutputCount())
+	}
+
+	o := prevTx.Outputs[vout]
+	return tx.FromUTXOs(&UTXO{
+		TxID:          prevTx.TxIDBytes(),
+		Vout:          vout,
+		Satoshis:      o.Satoshis,
+		LockingScript: o.LockingScript,
+	})
+}
+
 // From adds a new input to the transaction from the specified UTXO fields, using the default
-// finalised sequence number (0xFFFFFFFF). If you want a different nSeq, change it manually
-// afterwards.
+// finalised sequence number (0xFFFFFFFF). If you want a different nSeq, use FromUTXOsWithSequence.
 func (tx *Tx) From(prevTxID string, vout uint32, prevTxLockingScript string, satoshis uint64) error {
 	pts, err := bscript.NewFromHex(prevTxLockingScript)
 	if err != nil {
@@ -83,15 +123,30 @@ func (tx *Tx) From(prevTxID string, vout uint32, prevTxLockingScript string, sat
 }
 
 // FromUTXOs adds a new input to the transaction from the specified *bt.UTXO fields, using the default
-// finalised sequence number (0xFFFFFFFF). If you want a different nSeq, change it manually
-// afterwards.
+// finalised sequence number (0xFFFFFFFF). If you want a different nSeq, use FromUTXOsWithSequence.
 func (tx *Tx) FromUTXOs(utxos ...*UTXO) error {
+	return tx.FromUTXOsWithSequence(DefaultSequenceNumber, utxos...)
+}
+
+// FromUTXOsWithSequence adds a new input to the transaction from each of the specified *bt.UTXO
+// fields, all using the sequence number seq.
+//
+// The tx LockTime is only enforced if at least one input has a non-final sequence number, that
+// is below 0xFFFFFFFF. Use a non-final sequence for timelocked transactions; while the
+// locktime has not been reached, such inputs may be replaced by a version with a higher sequence.
+//
+// Note this changes tx.Version as a side effect: if seq encodes a relative timelock (see
+// Tx.HasRelativeLockTime) and the version is below MinVersionRelativeLockTime, it is raised
+// to MinVersionRelativeLockTime with SetVersion, as the timelock would otherwise be ignored.
+// The version is never lowered, and is left unchanged for final or absolute locktime
+// sequences. Set tx.Version afterwards to override it.
+func (tx *Tx) FromUTXOsWithSequence(seq uint32, utxos ...*UTXO) error {
 	for _, utxo := range utxos {
 		i := &Input{
 			PreviousTxOutIndex: utxo.Vout,
 			PreviousTxSatoshis: utxo.Satoshis,
 			PreviousTxScript:   utxo.LockingScript,
-			SequenceNumber:     DefaultSequenceNumber, // use default finalised sequence number
+			SequenceNumber:     seq,
 		}
 		if err := i.PreviousTxIDAdd(utxo.TxID); err != nil {
 			return err
@@ -99,10 +154,29 @@ func (tx *Tx) FromUTXOs(utxos ...*UTXO) error {
 
 		tx.addInput(i)
 	}
+	if len(utxos) > 0 && isRelativeLockTime(seq) {
+		tx.SetVersion(tx.Version)
+	}
 
 	return nil
 }
 
+// FundOptions configure optional behaviour of Fund.
+type FundOptions struct {
+	// AutoChange, when set, makes Fund also cover the fee of a change output and
+	// add that output, paying to ChangeScript, once funding completes. The change
+	// output is not added if the leftover would be below the fee quote's DustThreshold.
+	AutoChange bool
+	// ChangeScript is the locking script of the change output. It is required
+	// when AutoChange is set.
+	ChangeScript *bscript.Script
+	// SpentChecker, if set, is consulted for every utxo returned by the UTXOGetterFunc
+	// and spent utxos are skipped, with more requested from the UTXOGetterFunc as
+	// needed. This guards against funding from a stale utxo set, at the cost of a
+	// lookup per utxo, which adds the checker's latency to Fund for every candidate.
+	SpentChecker SpentChecker
+}
+
 // Fund continuously calls the provided bt.UTXOGetterFunc, adding each returned input
 // as an input via tx.From(...), until it is estimated that inputs cover the outputs + fees.
 //
@@ -110,7 +184,17 @@ func (tx *Tx) FromUTXOs(utxos ...*UTXO) error {
 // Note, this function works under the assumption that receiver *bt.Tx already has all the outputs
 // which need covered.
 //
-// If insufficient utxos are provided from the UTXOGetterFunc, a bt.ErrInsufficientFunds is returned.
+// Optional FundOptions may be passed, only the first is used. With AutoChange set the
+// change output is accounted for and added by Fund, leaving the receiver ready to be signed.
+// If no options are passed, no change is added.
+//
+// If insufficient utxos are provided from the UTXOGetterFunc, an *InsufficientFundsError is returned
+// detailing the satoshis required and available. It matches bt.ErrInsufficientFunds with errors.Is.
+//
+// The context is checked before each call to the UTXOGetterFunc. If it is done, Fund returns
+// promptly with an error wrapping both bt.ErrFundCancelled and ctx.Err(). In that case the
+// receiver keeps every input added by previous calls, each batch of utxos being added whole,
+// but may not yet cover its outputs and fees; calling Fund again resumes from that state.
 //
 // Example usage:
 //
@@ -133,33 +217,131 @@ func (tx *Tx) FromUTXOs(utxos ...*UTXO) error {
 //	    if errors.Is(err, bt.ErrInsufficientFunds) { /* handle */ }
 //	    return err
 //	}
-func (tx *Tx) Fund(ctx context.Context, fq *FeeQuote, next UTXOGetterFunc) error {
-	deficit, err := tx.estimateDeficit(fq)
+func (tx *Tx) Fund(ctx context.Context, fq *FeeQuote, next UTXOGetterFunc, opts ...FundOptions) error {
+	var opt FundOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var changeBytes uint64
+	if opt.AutoChange {
+		if opt.ChangeScript == nil {
+			return ErrNoChangeScript
+		}
+		changeBytes = outputSize(opt.ChangeScript)
+	}
+
+	deficit, err := tx.estimateDeficit(fq, changeBytes)
 	if err != nil {
 		return err
 	}
 	for deficit != 0 {
+		if err = ctx.Err(); err != nil {
+			return fmt.Errorf("%w: %w", ErrFundCancelled, err)
+		}
+
 		utxos, err := next(ctx, deficit)
 		if err != nil {
 			if errors.Is(err, ErrNoUTXO) {
 				break
 			}
+			if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				return fmt.Errorf("%w: %w", ErrFundCancelled, err)
+			}
 
 			return err
 		}
 
+		if opt.SpentChecker != nil {
+			if utxos, err = unspentUTXOs(ctx, opt.SpentChecker, utxos); err != nil {
+				if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+					return fmt.Errorf("%w: %w", ErrFundCancelled, err)
+				}
+				return err
+			}
+		}
+
 		if err = tx.FromUTXOs(utxos...); err != nil {
 			return err
 		}
 
-		deficit, err = tx.estimateDeficit(fq)
+		deficit, err = tx.estimateDeficit(fq, changeBytes)
 		if err != nil {
 			return err
 		}
 	}
 	if deficit != 0 {
+		available := tx.TotalInputSatoshis()
+		return &InsufficientFundsError{Required: available + deficit, Available: available}
+	}
+
+	if opt.AutoChange {
+		return tx.Change(opt.ChangeScript, fq)
+	}
+
+	return nil
+}
+
+// TopUp adds inputs from the bt.UTXOGetterFunc to a tx which has been left underfunded,
+// for example after its outputs were edited, until the outputs and fees are covered again.
+// Existing inputs and outputs are left untouched and no change is added. The number of
+// inputs added is returned.
+//
+// If the tx is already funded the UTXOGetterFunc is not called and zero is returned, so
+// TopUp is safe to call repeatedly. Errors are as for Fund, with the count reflecting any
+// inputs added before the error.
+func (tx *Tx) TopUp(ctx context.Context, fq *FeeQuote, next UTXOGetterFunc) (int, error) {
+	before := tx.InputCount()
+	err := tx.Fund(ctx, fq, next)
+	return tx.InputCount() - before, err
+}
+
+// SweepTo adds every utxo provided by the bt.UTXOGetterFunc as an input and pays
+// the total, minus the fee, to a single new P2PKH output for the address provided.
+// The swept amount is the Satoshis of that output, which is the last on the tx.
+//
+// Unlike Fund there is no target amount, so the UTXOGetterFunc is passed a deficit of
+// math.MaxUint64 and is called until it returns bt.ErrNoUTXO or no utxos. The output is
+// added before the fee is calculated, so the fee covers the output itself.
+//
+// Any outputs already on the receiver are paid first. If what is left after fees would
+// be dust, ErrInsufficientFunds is returned and no output is added, however the inputs
+// remain on the tx.
+func (tx *Tx) SweepTo(ctx context.Context, destAddr string, fq *FeeQuote, next UTXOGetterFunc) error {
+	s, err := bscript.NewP2PKHFromAddress(destAddr)
+	if err != nil {
+		return err
+	}
+
+	for {
+		utxos, err := next(ctx, math.MaxUint64)
+		if err != nil {
+			if errors.Is(err, ErrNoUTXO) {
+				break
+			}
+
+			return err
+		}
+		if len(utxos) == 0 {
+			break
+		}
+
+		if err = tx.FromUTXOs(utxos...); err != nil {
+			return err
+		}
+	}
+
+	tx.AddOutput(&Output{LockingScript: s})
+	idx := tx.OutputCount() - 1
+
+	swept, ok, err := tx.change(fq, nil)
+	if err != nil || !ok {
+		tx.Outputs = tx.Outputs[:idx]
+		if err != nil {
+			return err
+		}
 		return ErrInsufficientFunds
 	}
+	tx.Outputs[idx].Satoshis = swept
 
 	return nil
 }
@@ -207,6 +389,47 @@ func (tx *Tx) InsertInputUnlockingScript(index uint32, s *bscript.Script) error
 	return fmt.Errorf("no input at index %d", index)
 }
 
+// InsertAndVerifyUnlockingScript applies a script to the input at index, as
+// InsertInputUnlockingScript does, and then checks the signature in it against
+// the P2PKH or P2PK output the input spends, as VerifyInputSignatures does. This
+// catches a bad signature from an external signer when it is inserted rather than
+// when the tx is broadcast.
+//
+// If the signature does not verify an ErrInvalidSignature error detailing the
+// reason is returned and the input's previous unlocking script is restored. An
+// ErrInputNoExist, ErrEmptyPreviousTxScript or ErrUnsupportedScriptType error is
+// returned, and nothing inserted, if there is no such input, its previous script
+// is not set, or it is neither P2PKH nor P2PK.
+func (tx *Tx) InsertAndVerifyUnlockingScript(index uint32, s *bscript.Script) error {
+	if int(index) >= tx.InputCount() {
+		return errors.Wrapf(ErrInputNoExist, "input %d", index)
+	}
+
+	return tx.insertVerifiedUnlockingScript(index, s)
+}
+
+// insertVerifiedUnlockingScript sets the unlocking script of the input at idx to
+// s if the signature in it verifies against the P2PKH or P2PK output the input
+// spends, leaving the input unchanged otherwise. The input must exist.
+func (tx *Tx) insertVerifiedUnlockingScript(idx uint32, s *bscript.Script) error {
+	in := tx.Inputs[idx]
+	if in.PreviousTxScript == nil {
+		return errors.Wrapf(ErrEmptyPreviousTxScript, "input %d", idx)
+	}
+	if !in.PreviousTxScript.IsP2PKH() && !in.PreviousTxScript.IsP2PK() {
+		return errors.Wrapf(ErrUnsupportedScriptType, "input %d is %s", idx, in.PreviousTxScript.ScriptType())
+	}
+
+	previous := in.UnlockingScript
+	in.UnlockingScript = s
+	if err := tx.verifyInputSignature(idx); err != nil {
+		in.UnlockingScript = previous
+		return fmt.Errorf("%w: input %d: %s", ErrInvalidSignature, idx, err.Error())
+	}
+
+	return nil
+}
+
 // FillInput is used to unlock the transaction at a specific input index.
 // It takes an Unlocker interface as a parameter so that different
 // unlocking implementations can be used to unlock the transaction -
@@ -229,25 +452,292 @@ func (tx *Tx) FillInput(ctx context.Context, unlocker Unlocker, params UnlockerP
 	return tx.InsertInputUnlockingScript(params.InputIdx, unlockingScript)
 }
 
+// CheckKeyForInput reports whether pub can unlock the P2PKH or P2PK output spent
+// by the input at inputIdx, so a wrong key can be caught before an expensive
+// signing operation such as on a hardware wallet. Either the compressed or
+// uncompressed serialisation of the key is accepted, as by OutputsForPublicKey.
+// No signature is made.
+//
+// A *KeyMismatchError, matching ErrKeyMismatch, is returned with false if the key
+// does not match, detailing the expected and actual key hashes. ErrInputNoExist,
+// ErrEmptyPreviousTxScript and ErrUnsupportedScriptType errors are returned if
+// there is no such input, it has no previous script, or the script is neither
+// P2PKH nor P2PK.
+func (tx *Tx) CheckKeyForInput(inputIdx uint32, pub *ec.PublicKey) (bool, error) {
+	if int(inputIdx) >= tx.InputCount() {
+		return false, errors.Wrapf(ErrInputNoExist, "input %d", inputIdx)
+	}
+	script := tx.Inputs[inputIdx].PreviousTxScript
+	if script == nil {
+		return false, errors.Wrapf(ErrEmptyPreviousTxScript, "input %d", inputIdx)
+	}
+
+	var expected []byte
+	switch {
+	case script.IsP2PKH():
+		pkh, err := script.PublicKeyHash()
+		if err != nil {
+			return false, errors.Wrapf(err, "input %d", inputIdx)
+		}
+		expected = pkh
+	case script.IsP2PK():
+		parts, err := bscript.DecodeParts(*script)
+		if err != nil {
+			return false, errors.Wrapf(err, "input %d", inputIdx)
+		}
+		expected = crypto.Hash160(parts[0])
+	default:
+		return false, errors.Wrapf(ErrUnsupportedScriptType, "input %d is %s", inputIdx, script.ScriptType())
+	}
+
+	actual := crypto.Hash160(pub.Compressed())
+	if bytes.Equal(expected, actual) || bytes.Equal(expected, crypto.Hash160(pub.Uncompressed())) {
+		return true, nil
+	}
+
+	return false, &KeyMismatchError{InputIdx: inputIdx, Expected: expected, Actual: actual}
+}
+
+// FillOptions are options for FillAllInputs.
+type FillOptions struct {
+	// CollectErrors, when set, makes FillAllInputs attempt every input rather
+	// than stop at the first failure, returning an InputErrors listing each input
+	// which failed. Inputs which succeed are signed either way.
+	CollectErrors bool
+}
+
 // FillAllInputs is used to sign all inputs. It takes an UnlockerGetter interface
 // as a parameter so that different unlocking implementations can
 // be used to sign the transaction - for example local/external
 // signing, or P2PKH/contract signing.
 //
 // Given this signs inputs and outputs, sighash `ALL|FORKID` is used.
-func (tx *Tx) FillAllInputs(ctx context.Context, ug UnlockerGetter) error {
+//
+// Any error returned is annotated with the index of the input that failed. By
+// default signing stops at the first failure, leaving the later inputs unsigned;
+// see FillOptions.CollectErrors to attempt them all.
+func (tx *Tx) FillAllInputs(ctx context.Context, ug UnlockerGetter, opts ...FillOptions) error {
+	collect := len(opts) > 0 && opts[0].CollectErrors
+
+	var errs InputErrors
 	for i, in := range tx.Inputs {
+		u, err := ug.Unlocker(ctx, in.PreviousTxScript)
+		if err == nil {
+			err = tx.FillInput(ctx, u, UnlockerParams{
+				InputIdx:     uint32(i),
+				SigHashFlags: sighash.AllForkID, // use SIGHASHALLFORFORKID to sign automatically
+			})
+		}
+		if err == nil {
+			continue
+		}
+		if !collect {
+			return errors.Wrapf(err, "input %d", i)
+		}
+		errs = append(errs, &InputError{Index: i, Err: err})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// FillUnsignedInputs signs every input which does not yet have an unlocking script,
+// as FillAllInputs, leaving inputs already signed by another party untouched.
+//
+// Any error returned is annotated with the index of the input that failed.
+func (tx *Tx) FillUnsignedInputs(ctx context.Context, ug UnlockerGetter) error {
+	for i, in := range tx.Inputs {
+		if in.UnlockingScriptSize() > 0 {
+			continue
+		}
 		u, err := ug.Unlocker(ctx, in.PreviousTxScript)
 		if err != nil {
-			return err
+			return errors.Wrapf(err, "input %d", i)
 		}
 
 		if err = tx.FillInput(ctx, u, UnlockerParams{
 			InputIdx:     uint32(i),
-			SigHashFlags: sighash.AllForkID, // use SIGHASHALLFORFORKID to sign automatically
+			SigHashFlags: sighash.AllForkID,
 		}); err != nil {
+			return errors.Wrapf(err, "input %d", i)
+		}
+	}
+
+	return nil
+}
+
+// SignedCheckOptions are options for IsFullySigned and UnsignedInputIndices.
+type SignedCheckOptions struct {
+	// Scriptless lists the indices of inputs deliberately left with an empty
+	// unlocking script, such as those spending an anyone-can-spend output, which
+	// are treated as signed.
+	Scriptless []int
+}
+
+// IsFullySigned returns true if every input has a non-empty unlocking script, or
+// is listed as scriptless in opts, so the tx is ready to be finalised and
+// broadcast. A tx with no inputs is not signed. The signatures themselves are not
+// checked, see VerifyInputSignatures.
+func (tx *Tx) IsFullySigned(opts ...SignedCheckOptions) bool {
+	return tx.InputCount() > 0 && len(tx.UnsignedInputIndices(opts...)) == 0
+}
+
+// UnsignedInputIndices returns the indices, in order, of the inputs with an empty
+// unlocking script, other than those listed as scriptless in opts.
+func (tx *Tx) UnsignedInputIndices(opts ...SignedCheckOptions) []int {
+	scriptless := make(map[int]bool)
+	if len(opts) > 0 {
+		for _, i := range opts[0].Scriptless {
+			scriptless[i] = true
+		}
+	}
+
+	unsigned := make([]int, 0)
+	for i, in := range tx.Inputs {
+		if in.UnlockingScriptSize() == 0 && !scriptless[i] {
+			unsigned = append(unsigned, i)
+		}
+	}
+	return unsigned
+}
+
+// VerifyInputSignatures checks that the signature in the unlocking script of each
+// standard P2PKH or P2PK input verifies against the output it is spending. This is
+// a cheap sanity check to run after signing, catching key/script mismatches before
+// broadcast; it is not a full script interpretation. Inputs spending other script
+// types are skipped.
+//
+// If an input fails verification an ErrInvalidSignature error is returned detailing
+// the input index and the reason.
+func (tx *Tx) VerifyInputSignatures() error {
+	for i, in := range tx.Inputs {
+		if in.PreviousTxScript == nil {
+			return errors.Wrapf(ErrEmptyPreviousTxScript, "input %d", i)
+		}
+		if !in.PreviousTxScript.IsP2PKH() && !in.PreviousTxScript.IsP2PK() {
+			continue
+		}
+		if err := tx.verifyInputSignature(uint32(i)); err != nil {
+			return fmt.Errorf("%w: input %d: %s", ErrInvalidSignature, i, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// HasNonCommittingSignatures reports whether any input is signed with a sighash
+// flag which lets the outputs be changed without invalidating the signature, and
+// returns the indices of those inputs in order.
+//
+// A signature with SIGHASH_NONE commits to no outputs, and one with SIGHASH_SINGLE
+// commits only to the output at the same index as its input, and to none when
+// there is no such output. Either lets anyone holding the tx redirect the funds,
+// so a tx received as payment should be rejected if this returns true.
+//
+// Only P2PKH and P2PK inputs are scanned. Inputs whose previous locking script is
+// known to be another type, or which are unsigned, are skipped. Where the previous
+// locking script is not known, the unlocking script is scanned if it has the shape
+// of a P2PKH or P2PK unlocking script.
+func (tx *Tx) HasNonCommittingSignatures() (bool, []int) {
+	var indices []int
+	for i, in := range tx.Inputs {
+		shf, ok := inputSigHashFlag(in)
+		if !ok {
+			continue
+		}
+		switch shf & sighash.Mask {
+		case sighash.None:
+			indices = append(indices, i)
+		case sighash.Single:
+			if i >= tx.OutputCount() {
+				indices = append(indices, i)
+			}
+		}
+	}
+	return len(indices) > 0, indices
+}
+
+// inputSigHashFlag returns the sighash flag of a P2PKH or P2PK input's signature,
+// and false if the input is not of either type or has no parsable signature.
+func inputSigHashFlag(in *Input) (sighash.Flag, bool) {
+	if in.PreviousTxScript != nil && !in.PreviousTxScript.IsP2PKH() && !in.PreviousTxScript.IsP2PK() {
+		return 0, false
+	}
+	if _, shf, err := in.ExtractSignature(); err == nil {
+		return shf, true
+	}
+	if in.UnlockingScript == nil {
+		return 0, false
+	}
+
+	// a P2PK unlocking script is the signature alone
+	parts, err := bscript.DecodeParts(*in.UnlockingScript)
+	if err != nil || len(parts) != 1 || len(parts[0]) < 2 {
+		return 0, false
+	}
+	if _, err = ec.ParseDERSignature(parts[0][:len(parts[0])-1]); err != nil {
+		return 0, false
+	}
+	shf, err := sighash.FromSignature(parts[0])
+	return shf, err == nil
+}
+
+func (tx *Tx) verifyInputSignature(idx uint32) error {
+	in := tx.Inputs[idx]
+	if in.UnlockingScript == nil || len(*in.UnlockingScript) == 0 {
+		return errors.New("input is not signed")
+	}
+	parts, err := bscript.DecodeParts(*in.UnlockingScript)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode unlocking script")
+	}
+
+	var pubKeyBytes []byte
+	if in.PreviousTxScript.IsP2PKH() {
+		if len(parts) != 2 {
+			return fmt.Errorf("expected signature and public key, got %d pushes", len(parts))
+		}
+		pubKeyBytes = parts[1]
+		pkh, err := in.PreviousTxScript.PublicKeyHash()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(pkh, crypto.Hash160(pubKeyBytes)) {
+			return errors.New("public key does not match the previous locking script")
+		}
+	} else {
+		if len(parts) != 1 {
+			return fmt.Errorf("expected signature, got %d pushes", len(parts))
+		}
+		lockingParts, err := bscript.DecodeParts(*in.PreviousTxScript)
+		if err != nil {
 			return err
 		}
+		pubKeyBytes = lockingParts[0]
+	}
+
+	sigBytes := parts[0]
+	if len(sigBytes) < 2 {
+		return errors.New("signature is too short")
+	}
+	shf := sighash.Flag(sigBytes[len(sigBytes)-1])
+
+	pubKey, err := ec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse public key")
+	}
+	sig, err := ec.ParseDERSignature(sigBytes[:len(sigBytes)-1])
+	if err != nil {
+		return errors.Wrap(err, "failed to parse signature")
+	}
+	sh, err := tx.CalcInputSignatureHash(idx, shf)
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(sh, pubKey) {
+		return errors.New("signature does not verify")
 	}
 
 	return nil
@@ -0,0 +1,429 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/ec/wif"
+	"github.com/bitcoin-sv/go-sdk/sighash"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/txtest"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_FromTx(t *testing.T) {
+	t.Parallel()
+
+	prevTx := transaction.NewTx()
+	assert.NoError(t, prevTx.From(
+		"3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+		0,
+		"76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac",
+		10000,
+	))
+	assert.NoError(t, prevTx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+	assert.NoError(t, prevTx.PayToAddress("mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd", 8000))
+
+	t.Run("spends the given vout", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.FromTx(prevTx, 1))
+		assert.Equal(t, 1, tx.InputCount())
+
+		in := tx.Inputs[0]
+		assert.Equal(t, prevTx.TxID(), in.PreviousTxIDStr())
+		assert.Equal(t, uint32(1), in.PreviousTxOutIndex)
+		assert.Equal(t, uint64(8000), in.PreviousTxSatoshis)
+		assert.Equal(t, prevTx.Outputs[1].LockingScript, in.PreviousTxScript)
+		assert.Equal(t, transaction.DefaultSequenceNumber, in.SequenceNumber)
+	})
+
+	t.Run("vout out of range", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.ErrorIs(t, tx.FromTx(prevTx, 2), transaction.ErrOutputNoExist)
+		assert.Equal(t, 0, tx.InputCount())
+	})
+
+	t.Run("nil tx", func(t *testing.T) {
+		assert.ErrorIs(t, transaction.NewTx().FromTx(nil, 0), transaction.ErrTxNil)
+	})
+}
+
+func TestTx_FromUTXOsWithSequence(t *testing.T) {
+	t.Parallel()
+
+	txID, err := hex.DecodeString("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac")
+	assert.NoError(t, err)
+
+	tx := transaction.NewTx()
+	tx.LockTime = 800000
+	assert.NoError(t, tx.FromUTXOsWithSequence(0xfffffffe,
+		&transaction.UTXO{TxID: txID, Vout: 0, LockingScript: script, Satoshis: 1000},
+		&transaction.UTXO{TxID: txID, Vout: 1, LockingScript: script, Satoshis: 2000},
+	))
+	assert.NoError(t, tx.FromUTXOs(&transaction.UTXO{TxID: txID, Vout: 2, LockingScript: script, Satoshis: 3000}))
+
+	assert.Equal(t, 3, tx.InputCount())
+	assert.Equal(t, uint32(0xfffffffe), tx.Inputs[0].SequenceNumber)
+	assert.Equal(t, uint32(0xfffffffe), tx.Inputs[1].SequenceNumber)
+	assert.Equal(t, uint32(1), tx.Inputs[1].PreviousTxOutIndex)
+	assert.Equal(t, uint64(2000), tx.Inputs[1].PreviousTxSatoshis)
+	assert.Equal(t, transaction.DefaultSequenceNumber, tx.Inputs[2].SequenceNumber)
+
+	t.Run("invalid txid", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.Error(t, tx.FromUTXOsWithSequence(0, &transaction.UTXO{TxID: []byte{0x01}, LockingScript: script}))
+		assert.Equal(t, 0, tx.InputCount())
+	})
+}
+
+func TestTx_IsFullySigned(t *testing.T) {
+	t.Parallel()
+
+	txID, err := hex.DecodeString("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac")
+	assert.NoError(t, err)
+
+	tx := transaction.NewTx()
+	assert.False(t, tx.IsFullySigned())
+	assert.Empty(t, tx.UnsignedInputIndices())
+
+	for vout := uint32(0); vout < 3; vout++ {
+		assert.NoError(t, tx.FromUTXOs(&transaction.UTXO{TxID: txID, Vout: vout, LockingScript: script, Satoshis: 1000}))
+	}
+	assert.False(t, tx.IsFullySigned())
+	assert.Equal(t, []int{0, 1, 2}, tx.UnsignedInputIndices())
+
+	tx.Inputs[0].UnlockingScript = bscript.NewFromBytes([]byte{bscript.Op1})
+	tx.Inputs[2].UnlockingScript = bscript.NewFromBytes([]byte{bscript.Op1})
+	assert.False(t, tx.IsFullySigned())
+	assert.Equal(t, []int{1}, tx.UnsignedInputIndices())
+
+	t.Run("scriptless inputs", func(t *testing.T) {
+		opts := transaction.SignedCheckOptions{Scriptless: []int{1}}
+		assert.True(t, tx.IsFullySigned(opts))
+		assert.Empty(t, tx.UnsignedInputIndices(opts))
+	})
+
+	t.Run("empty script is unsigned", func(t *testing.T) {
+		tx.Inputs[2].UnlockingScript = &bscript.Script{}
+		assert.Equal(t, []int{1, 2}, tx.UnsignedInputIndices())
+	})
+}
+
+var errNoPreviousScript = errors.New("no previous script")
+
+// scriptCheckingGetter fails for inputs with no previous script, and otherwise
+// signs with its Getter.
+type scriptCheckingGetter struct {
+	unlocker.Getter
+}
+
+func (g *scriptCheckingGetter) Unlocker(ctx context.Context, lockingScript *bscript.Script) (transaction.Unlocker, error) {
+	if lockingScript == nil {
+		return nil, errNoPreviousScript
+	}
+	return g.Getter.Unlocker(ctx, lockingScript)
+}
+
+func TestTx_FillAllInputs(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	getter := &scriptCheckingGetter{unlocker.Getter{PrivateKey: w.PrivKey}}
+
+	tests := map[string]struct {
+		inputs       int
+		noPrevScript []int
+		opts         []transaction.FillOptions
+		expErr       string
+		expFailed    []int
+		expUnsigned  []int
+	}{
+		"stops at first error by default": {
+			inputs:       4,
+			noPrevScript: []int{1, 3},
+			expErr:       "input 1: no previous script",
+			expUnsigned:  []int{1, 2, 3},
+		},
+		"collects every error": {
+			inputs:       4,
+			noPrevScript: []int{1, 3},
+			opts:         []transaction.FillOptions{{CollectErrors: true}},
+			expErr:       "2 inputs failed: input 1: no previous script; input 3: no previous script",
+			expFailed:    []int{1, 3},
+			expUnsigned:  []int{1, 3},
+		},
+		"no errors": {
+			inputs: 2,
+		},
+		"collecting with no errors": {
+			inputs: 2,
+			opts:   []transaction.FillOptions{{CollectErrors: true}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := transaction.NewTx()
+			for i := 0; i < test.inputs; i++ {
+				assert.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+					uint32(i), "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac", 1000))
+			}
+			assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+			for _, i := range test.noPrevScript {
+				tx.Inputs[i].PreviousTxScript = nil
+			}
+
+			err := tx.FillAllInputs(context.Background(), getter, test.opts...)
+			if test.expErr == "" {
+				assert.NoError(t, err)
+				assert.True(t, tx.IsFullySigned())
+				return
+			}
+			assert.ErrorIs(t, err, errNoPreviousScript)
+			assert.Contains(t, err.Error(), test.expErr)
+			assert.Equal(t, test.expUnsigned, tx.UnsignedInputIndices())
+
+			var inputErrs transaction.InputErrors
+			assert.Equal(t, test.expFailed != nil, errors.As(err, &inputErrs))
+			for i, inputErr := range inputErrs {
+				assert.Equal(t, test.expFailed[i], inputErr.Index)
+			}
+			assert.Len(t, inputErrs.Unwrap(), len(test.expFailed))
+		})
+	}
+}
+
+func TestTx_CheckKeyForInput(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	other, err := wif.DecodeWIF("KznvCNc6Yf4iztSThoMH6oHWzH9EgjfodKxmeuUGPq5DEX5maspS")
+	assert.NoError(t, err)
+	pub := w.PrivKey.PubKey()
+
+	p2pkh, err := bscript.NewP2PKHFromPubKeyEC(pub)
+	assert.NoError(t, err)
+	uncompressed, err := bscript.NewP2PKHFromPubKeyHash(crypto.Hash160(pub.Uncompressed()))
+	assert.NoError(t, err)
+	p2pk := &bscript.Script{}
+	assert.NoError(t, p2pk.AppendPushData(pub.Compressed()))
+	assert.NoError(t, p2pk.AppendOpcodes(bscript.OpCHECKSIG))
+	data, err := bscript.NewFromASM("OP_FALSE OP_RETURN 68656c6c6f")
+	assert.NoError(t, err)
+
+	tx := transaction.NewTx()
+	for _, s := range []*bscript.Script{p2pkh, uncompressed, p2pk, data} {
+		assert.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 0, s.String(), 1000))
+	}
+	tx.Inputs = append(tx.Inputs, &transaction.Input{})
+
+	for idx := uint32(0); idx < 3; idx++ {
+		ok, err := tx.CheckKeyForInput(idx, pub)
+		assert.NoError(t, err, "input %d", idx)
+		assert.True(t, ok, "input %d", idx)
+
+		ok, err = tx.CheckKeyForInput(idx, other.PrivKey.PubKey())
+		assert.False(t, ok, "input %d", idx)
+		assert.ErrorIs(t, err, transaction.ErrKeyMismatch)
+
+		var mismatch *transaction.KeyMismatchError
+		assert.True(t, errors.As(err, &mismatch))
+		assert.Equal(t, idx, mismatch.InputIdx)
+		assert.Equal(t, crypto.Hash160(other.PrivKey.PubKey().Compressed()), mismatch.Actual)
+	}
+
+	_, err = tx.CheckKeyForInput(3, pub)
+	assert.ErrorIs(t, err, transaction.ErrUnsupportedScriptType)
+	_, err = tx.CheckKeyForInput(4, pub)
+	assert.ErrorIs(t, err, transaction.ErrEmptyPreviousTxScript)
+	_, err = tx.CheckKeyForInput(5, pub)
+	assert.ErrorIs(t, err, transaction.ErrInputNoExist)
+}
+
+func TestTx_InsertAndVerifyUnlockingScript(t *testing.T) {
+	t.Parallel()
+
+	toP2PK := func(f *txtest.Fixture) {
+		s := &bscript.Script{}
+		assert.NoError(t, s.AppendPushData(f.Keys[1].PubKey().Compressed()))
+		assert.NoError(t, s.AppendOpcodes(bscript.OpCHECKSIG))
+		f.Tx.Inputs[1].PreviousTxScript = s
+	}
+
+	tests := map[string]struct {
+		setup     func(f *txtest.Fixture)
+		idx       uint32
+		signer    int
+		script    *bscript.Script
+		tamper    func(f *txtest.Fixture)
+		expErr    error
+		expErrMsg string
+	}{
+		"valid p2pkh signature": {
+			idx: 0,
+		},
+		"valid p2pk signature": {
+			setup:  toP2PK,
+			idx:    1,
+			signer: 1,
+		},
+		"wrong key": {
+			idx:       0,
+			signer:    1,
+			expErr:    transaction.ErrInvalidSignature,
+			expErrMsg: "public key does not match",
+		},
+		"stale p2pk signature": {
+			setup:  toP2PK,
+			idx:    1,
+			signer: 1,
+			tamper: func(f *txtest.Fixture) {
+				f.Tx.Outputs[0].Satoshis--
+			},
+			expErr: transaction.ErrInvalidSignature,
+		},
+		"empty script": {
+			idx:    0,
+			script: &bscript.Script{},
+			expErr: transaction.ErrInvalidSignature,
+		},
+		"unsupported script type": {
+			tamper: func(f *txtest.Fixture) {
+				f.Tx.Inputs[0].PreviousTxScript = bscript.NewFromBytes([]byte{bscript.OpFALSE, bscript.OpRETURN})
+			},
+			idx:    0,
+			expErr: transaction.ErrUnsupportedScriptType,
+		},
+		"no previous script": {
+			tamper: func(f *txtest.Fixture) {
+				f.Tx.Inputs[0].PreviousTxScript = nil
+			},
+			idx:    0,
+			expErr: transaction.ErrEmptyPreviousTxScript,
+		},
+		"no such input": {
+			idx:    2,
+			script: &bscript.Script{},
+			expErr: transaction.ErrInputNoExist,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := txtest.NewFixture(t, 2, 1)
+			for _, in := range f.Tx.Inputs {
+				in.UnlockingScript = nil
+			}
+			if test.setup != nil {
+				test.setup(f)
+			}
+			s := test.script
+			if s == nil {
+				s = signExternally(t, f.Tx, test.idx, f.Keys[test.signer])
+			}
+			if test.tamper != nil {
+				test.tamper(f)
+			}
+
+			err := f.Tx.InsertAndVerifyUnlockingScript(test.idx, s)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				assert.Contains(t, err.Error(), test.expErrMsg)
+				if int(test.idx) < f.Tx.InputCount() {
+					assert.Zero(t, f.Tx.Inputs[test.idx].UnlockingScriptSize())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, s, f.Tx.Inputs[test.idx].UnlockingScript)
+		})
+	}
+}
+
+// signExternally returns an unlocking script for the P2PKH or P2PK input idx of tx
+// signed with key, as an external signer would, without changing tx.
+func signExternally(t *testing.T, tx *transaction.Tx, idx uint32, key *ec.PrivateKey) *bscript.Script {
+	if tx.Inputs[idx].PreviousTxScript.IsP2PKH() {
+		external := tx.Clone()
+		assert.NoError(t, external.FillInput(context.Background(), &unlocker.Simple{PrivateKey: key},
+			transaction.UnlockerParams{InputIdx: idx}))
+		return external.Inputs[idx].UnlockingScript
+	}
+	sh, err := tx.CalcInputSignatureHash(idx, sighash.AllForkID)
+	assert.NoError(t, err)
+	sig, err := key.Sign(sh)
+	assert.NoError(t, err)
+	s := &bscript.Script{}
+	assert.NoError(t, s.AppendPushData(append(sig.Serialise(), byte(sighash.AllForkID))))
+	return s
+}
+
+func TestTx_HasNonCommittingSignatures(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	script, err := bscript.NewP2PKHFromPubKeyEC(priv.PubKey())
+	assert.NoError(t, err)
+
+	sign := func(t *testing.T, flags ...sighash.Flag) *transaction.Tx {
+		tx := transaction.NewTx()
+		for i := range flags {
+			assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", uint32(i), script.String(), 1000))
+		}
+		assert.NoError(t, tx.PayTo(script, 500))
+		assert.NoError(t, tx.PayTo(script, 500))
+		for i, f := range flags {
+			assert.NoError(t, tx.FillInput(context.Background(), &unlocker.Simple{PrivateKey: priv},
+				transaction.UnlockerParams{InputIdx: uint32(i), SigHashFlags: f}))
+		}
+		return tx
+	}
+
+	t.Run("all committing", func(t *testing.T) {
+		tx := sign(t, sighash.AllForkID, sighash.SingleForkID, sighash.AllForkID|sighash.AnyOneCanPay)
+		ok, indices := tx.HasNonCommittingSignatures()
+		assert.False(t, ok)
+		assert.Empty(t, indices)
+	})
+
+	t.Run("none and single without an output", func(t *testing.T) {
+		tx := sign(t, sighash.AllForkID, sighash.NoneForkID|sighash.AnyOneCanPay, sighash.SingleForkID)
+		ok, indices := tx.HasNonCommittingSignatures()
+		assert.True(t, ok)
+		assert.Equal(t, []int{1, 2}, indices)
+	})
+
+	t.Run("p2pk signature", func(t *testing.T) {
+		tx := sign(t, sighash.NoneForkID)
+		sig, flag, err := tx.Inputs[0].ExtractSignature()
+		assert.NoError(t, err)
+		unlocking := &bscript.Script{}
+		assert.NoError(t, unlocking.AppendPushData(append(sig, byte(flag))))
+		tx.Inputs[0].UnlockingScript = unlocking
+		tx.Inputs[0].PreviousTxScript = nil
+
+		ok, indices := tx.HasNonCommittingSignatures()
+		assert.True(t, ok)
+		assert.Equal(t, []int{0}, indices)
+	})
+
+	t.Run("unsigned and non-standard inputs are skipped", func(t *testing.T) {
+		tx := sign(t, sighash.NoneForkID)
+		assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 5, script.String(), 1000))
+		tx.Inputs[0].PreviousTxScript = bscript.NewFromBytes([]byte{bscript.Op1})
+		ok, indices := tx.HasNonCommittingSignatures()
+		assert.False(t, ok)
+		assert.Empty(t, indices)
+	})
+}
@@ -15,6 +15,8 @@ type txJSON struct {
 	Outputs  []*Output `json:"outputs"`
 	Version  uint32    `json:"version"`
 	LockTime uint32    `json:"lockTime"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 type inputJSON struct {
@@ -30,21 +32,40 @@ type outputJSON struct {
 }
 
 // MarshalJSON will serialise a transaction to json.
+// The transaction Metadata is not included, see MarshalJSONWithMetadata.
 func (tx *Tx) MarshalJSON() ([]byte, error) {
 	if tx == nil {
 		return nil, errors.Wrap(ErrTxNil, "cannot marshal tx")
 	}
-	return json.Marshal(txJSON{
+	return json.Marshal(tx.toJSON())
+}
+
+// MarshalJSONWithMetadata will serialise a transaction to json in the same
+// format as MarshalJSON, with the transaction Metadata added under a
+// "metadata" key. This is intended for persisting a transaction alongside
+// its application data, the metadata is never part of the transaction itself.
+func (tx *Tx) MarshalJSONWithMetadata() ([]byte, error) {
+	if tx == nil {
+		return nil, errors.Wrap(ErrTxNil, "cannot marshal tx")
+	}
+	txj := tx.toJSON()
+	txj.Metadata = tx.Metadata
+	return json.Marshal(txj)
+}
+
+func (tx *Tx) toJSON() txJSON {
+	return txJSON{
 		TxID:     tx.TxID(),
 		Hex:      tx.String(),
 		Inputs:   tx.Inputs,
 		Outputs:  tx.Outputs,
 		LockTime: tx.LockTime,
 		Version:  tx.Version,
-	})
+	}
 }
 
 // UnmarshalJSON will unmarshall a transaction that has been marshalled with this library.
+// Metadata written by MarshalJSONWithMetadata is restored.
 func (tx *Tx) UnmarshalJSON(b []byte) error {
 	var txj txJSON
 	if err := json.Unmarshal(b, &txj); err != nil {
@@ -57,10 +78,12 @@ func (tx *Tx) UnmarshalJSON(b []byte) error {
 			return err
 		}
 		*tx = *t
+		tx.Metadata = txj.Metadata
 		return nil
 	}
 	tx.LockTime = txj.LockTime
 	tx.Version = txj.Version
+	tx.Metadata = txj.Metadata
 	return nil
 }
 
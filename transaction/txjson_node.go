@@ -3,6 +3,8 @@ package transaction
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
 )
@@ -29,6 +31,7 @@ type nodeTxJSON struct {
 }
 
 type nodeInputJSON struct {
+	Coinbase  string `json:"coinbase,omitempty"`
 	ScriptSig *struct {
 		Asm string `json:"asm"`
 		Hex string `json:"hex"`
@@ -36,6 +39,9 @@ type nodeInputJSON struct {
 	TxID     string `json:"txid"`
 	Vout     uint32 `json:"vout"`
 	Sequence uint32 `json:"sequence"`
+	// Prevout is the output being spent, included by some node and indexer
+	// responses.
+	Prevout *nodeOutputJSON `json:"prevout,omitempty"`
 }
 
 type nodeOutputJSON struct {
@@ -101,11 +107,80 @@ func (n *nodeTxWrapper) UnmarshalJSON(b []byte) error {
 		*tx = *t
 		return nil
 	}
+	t, err := txj.toTx()
+	if err != nil {
+		return err
+	}
+	*tx = *t
+	return nil
+}
+
+// NewTxFromRPCJSON returns a transaction from the verbose JSON returned by a node's
+// getrawtransaction RPC, either on its own or within the JSON-RPC response envelope.
+//
+// The transaction is parsed from the hex field if present, otherwise it is built
+// from the version, locktime, vin and vout fields, using the scriptSig and
+// scriptPubKey hex. Coinbase inputs are supported. Where a vin entry includes the
+// prevout being spent, as some nodes and indexers return, the input's previous
+// script and satoshis are populated from it.
+//
+// If the response includes a txid which does not match the parsed transaction, an
+// ErrInvalidTxID error is returned. A response with a null result, or without any
+// hex, vin or vout, returns an ErrMalformedTx error.
+func NewTxFromRPCJSON(b []byte) (*Tx, error) {
+	var rpc map[string]json.RawMessage
+	if err := json.Unmarshal(b, &rpc); err != nil {
+		return nil, err
+	}
+	if e, ok := rpc["error"]; ok && string(e) != "null" {
+		return nil, fmt.Errorf("rpc error: %s", e)
+	}
+	if result, ok := rpc["result"]; ok {
+		if string(result) == "null" {
+			return nil, fmt.Errorf("%w: rpc response has no result", ErrMalformedTx)
+		}
+		b = result
+	}
+
+	var txj nodeTxJSON
+	if err := json.Unmarshal(b, &txj); err != nil {
+		return nil, err
+	}
+	if txj.Hex == "" && txj.Inputs == nil && txj.Outputs == nil {
+		return nil, fmt.Errorf("%w: response has no hex, vin or vout", ErrMalformedTx)
+	}
+
+	var tx *Tx
+	var err error
+	if txj.Hex != "" {
+		if tx, err = NewTxFromHex(txj.Hex); err != nil {
+			return nil, err
+		}
+		if len(txj.Inputs) > 0 && len(txj.Inputs) != len(tx.Inputs) {
+			return nil, fmt.Errorf("%w: hex has %d inputs but vin has %d", ErrMalformedTx, len(tx.Inputs), len(txj.Inputs))
+		}
+		for i, in := range txj.Inputs {
+			if err = in.setPrevout(tx.Inputs[i]); err != nil {
+				return nil, err
+			}
+		}
+	} else if tx, err = txj.toTx(); err != nil {
+		return nil, err
+	}
+
+	if txj.TxID != "" && txj.TxID != tx.TxID() {
+		return nil, fmt.Errorf("%w: response txid %s does not match parsed txid %s", ErrInvalidTxID, txj.TxID, tx.TxID())
+	}
+
+	return tx, nil
+}
+
+func (txj *nodeTxJSON) toTx() (*Tx, error) {
 	oo := make([]*Output, 0, len(txj.Outputs))
 	for _, o := range txj.Outputs {
 		out, err := o.toOutput()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		oo = append(oo, out)
 	}
@@ -113,15 +188,17 @@ func (n *nodeTxWrapper) UnmarshalJSON(b []byte) error {
 	for _, i := range txj.Inputs {
 		in, err := i.toInput()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		ii = append(ii, in)
 	}
-	tx.Inputs = ii
-	tx.Outputs = oo
-	tx.LockTime = txj.LockTime
-	tx.Version = txj.Version
-	return nil
+
+	return &Tx{
+		Version:  txj.Version,
+		LockTime: txj.LockTime,
+		Inputs:   ii,
+		Outputs:  oo,
+	}, nil
 }
 
 func (o *nodeOutputJSON) fromOutput(out *Output) error {
@@ -154,18 +231,38 @@ func (o *nodeOutputJSON) fromOutput(out *Output) error {
 }
 
 func (o *nodeOutputJSON) toOutput() (*Output, error) {
+	if o.ScriptPubKey == nil {
+		return nil, fmt.Errorf("%w: output %d has no scriptPubKey", ErrMalformedTx, o.Index)
+	}
 	out := &Output{}
 	s, err := bscript.NewFromHex(o.ScriptPubKey.Hex)
 	if err != nil {
 		return nil, err
 	}
-	out.Satoshis = uint64(o.Value * 100000000)
+	// round, as the BSV value cannot always be represented exactly
+	out.Satoshis = uint64(math.Round(o.Value * 100000000))
 	out.LockingScript = s
 	return out, nil
 }
 
 func (i *nodeInputJSON) toInput() (*Input, error) {
-	input := &Input{}
+	input := &Input{SequenceNumber: i.Sequence}
+	if i.Coinbase != "" {
+		s, err := bscript.NewFromHex(i.Coinbase)
+		if err != nil {
+			return nil, err
+		}
+		input.UnlockingScript = s
+		input.PreviousTxOutIndex = math.MaxUint32
+		if err = input.PreviousTxIDAdd(make([]byte, 32)); err != nil {
+			return nil, err
+		}
+		return input, nil
+	}
+
+	if i.ScriptSig == nil {
+		return nil, fmt.Errorf("%w: input spending %s:%d has no scriptSig", ErrMalformedTx, i.TxID, i.Vout)
+	}
 	s, err := bscript.NewFromHex(i.ScriptSig.Hex)
 	if err != nil {
 		return nil, err
@@ -173,14 +270,30 @@ func (i *nodeInputJSON) toInput() (*Input, error) {
 
 	input.UnlockingScript = s
 	input.PreviousTxOutIndex = i.Vout
-	input.SequenceNumber = i.Sequence
 	if err = input.PreviousTxIDAddStr(i.TxID); err != nil {
 		return nil, err
 	}
+	if err = i.setPrevout(input); err != nil {
+		return nil, err
+	}
 
 	return input, nil
 }
 
+// setPrevout sets the previous script and satoshis of input from the prevout, if any.
+func (i *nodeInputJSON) setPrevout(input *Input) error {
+	if i.Prevout == nil {
+		return nil
+	}
+	prev, err := i.Prevout.toOutput()
+	if err != nil {
+		return err
+	}
+	input.PreviousTxScript = prev.LockingScript
+	input.PreviousTxSatoshis = prev.Satoshis
+	return nil
+}
+
 func (i *nodeInputJSON) fromInput(input *Input) error {
 	asm, err := input.UnlockingScript.ToASM()
 	if err != nil {
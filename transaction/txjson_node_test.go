@@ -766,3 +766,120 @@ func TestOutput_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTxFromRPCJSON(t *testing.T) {
+	t.Parallel()
+
+	tx := transaction.NewTx()
+	assert.NoError(t, tx.From(
+		"3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+		0,
+		"76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac",
+		2000000,
+	))
+	assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 29000000))
+	w, err := wif.DecodeWIF("KznvCNc6Yf4iztSThoMH6oHWzH9EgjfodKxmeuUGPq5DEX5maspS")
+	assert.NoError(t, err)
+	assert.NoError(t, tx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: w.PrivKey}))
+
+	// verbose returns the node's getrawtransaction response for tx, with the
+	// prevout of each input as some nodes and indexers include.
+	verbose := func(t *testing.T) map[string]interface{} {
+		bb, err := json.Marshal(tx.NodeJSON())
+		assert.NoError(t, err)
+		var m map[string]interface{}
+		assert.NoError(t, json.Unmarshal(bb, &m))
+		m["vin"].([]interface{})[0].(map[string]interface{})["prevout"] = map[string]interface{}{
+			"value":        0.02,
+			"scriptPubKey": map[string]interface{}{"hex": "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac"},
+		}
+		return m
+	}
+	parse := func(t *testing.T, v interface{}) (*transaction.Tx, error) {
+		bb, err := json.Marshal(v)
+		assert.NoError(t, err)
+		return transaction.NewTxFromRPCJSON(bb)
+	}
+	check := func(t *testing.T, got *transaction.Tx) {
+		assert.Equal(t, tx.TxID(), got.TxID())
+		assert.Equal(t, uint64(29000000), got.Outputs[0].Satoshis)
+		assert.Equal(t, uint64(2000000), got.Inputs[0].PreviousTxSatoshis)
+		assert.Equal(t, tx.Inputs[0].PreviousTxScript.String(), got.Inputs[0].PreviousTxScript.String())
+	}
+
+	t.Run("structured", func(t *testing.T) {
+		m := verbose(t)
+		delete(m, "hex")
+		got, err := parse(t, m)
+		assert.NoError(t, err)
+		check(t, got)
+	})
+
+	t.Run("hex", func(t *testing.T) {
+		got, err := parse(t, verbose(t))
+		assert.NoError(t, err)
+		check(t, got)
+	})
+
+	t.Run("rpc envelope", func(t *testing.T) {
+		got, err := parse(t, map[string]interface{}{"result": verbose(t), "error": nil, "id": 1})
+		assert.NoError(t, err)
+		check(t, got)
+
+		_, err = parse(t, map[string]interface{}{
+			"result": nil,
+			"error":  map[string]interface{}{"code": -5, "message": "No such mempool or blockchain transaction"},
+			"id":     1,
+		})
+		assert.ErrorContains(t, err, "No such mempool")
+	})
+
+	t.Run("txid mismatch", func(t *testing.T) {
+		m := verbose(t)
+		delete(m, "hex")
+		m["vout"].([]interface{})[0].(map[string]interface{})["value"] = 0.3
+		_, err := parse(t, m)
+		assert.ErrorIs(t, err, transaction.ErrInvalidTxID)
+	})
+
+	t.Run("coinbase", func(t *testing.T) {
+		got, err := parse(t, map[string]interface{}{
+			"version":  1,
+			"locktime": 0,
+			"vin":      []interface{}{map[string]interface{}{"coinbase": "0350ed0c", "sequence": 4294967295}},
+			"vout": []interface{}{map[string]interface{}{
+				"value": 6.25, "n": 0,
+				"scriptPubKey": map[string]interface{}{"hex": "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac"},
+			}},
+		})
+		assert.NoError(t, err)
+		assert.True(t, got.IsCoinbase())
+		assert.Equal(t, uint64(625000000), got.Outputs[0].Satoshis)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		tests := map[string]struct {
+			body string
+		}{
+			"input without scriptSig": {
+				body: `{"vin":[{"txid":"` + tx.Inputs[0].PreviousTxIDStr() + `","vout":0}]}`,
+			},
+			"empty object": {
+				body: `{}`,
+			},
+			"null result": {
+				body: `{"result":null,"error":null}`,
+			},
+			"result without a tx": {
+				body: `{"result":{"txid":"` + tx.TxID() + `"},"error":null}`,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				got, err := transaction.NewTxFromRPCJSON([]byte(test.body))
+				assert.ErrorIs(t, err, transaction.ErrMalformedTx)
+				assert.Nil(t, got)
+			})
+		}
+	})
+}
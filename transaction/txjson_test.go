@@ -246,3 +246,31 @@ func TestTx_ToJson(t *testing.T) {
 	_, err := json.MarshalIndent(tx, "", "\t")
 	assert.NoError(t, err)
 }
+
+func TestTx_MarshalJSONWithMetadata(t *testing.T) {
+	tx, err := transaction.NewTxFromHex("0100000001abad53d72f342dd3f338e5e3346b492440f8ea821f8b8800e318f461cc5ea5a2010000006a4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8ffffffff02000000000000000008006a0548656c6c6f7f030000000000001976a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac00000000")
+	assert.NoError(t, err)
+
+	txID := tx.TxID()
+	bb := tx.Bytes()
+
+	tx.Metadata = map[string]string{
+		"label":   "rent",
+		"account": "savings",
+	}
+	assert.Equal(t, txID, tx.TxID())
+	assert.Equal(t, bb, tx.Bytes())
+
+	plain, err := json.Marshal(tx)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(plain), "metadata")
+
+	withMeta, err := tx.MarshalJSONWithMetadata()
+	assert.NoError(t, err)
+	assert.Contains(t, string(withMeta), `"metadata":{"account":"savings","label":"rent"}`)
+
+	var decoded transaction.Tx
+	assert.NoError(t, json.Unmarshal(withMeta, &decoded))
+	assert.Equal(t, txID, decoded.TxID())
+	assert.Equal(t, tx.Metadata, decoded.Metadata)
+}
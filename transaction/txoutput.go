@@ -1,32 +1,54 @@
 package transaction
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 
+	"github.com/bitcoin-sv/go-sdk/base58"
 	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/chaincfg"
 	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
 	"github.com/pkg/errors"
 )
 
-// newOutputFromBytes returns a transaction Output from the bytes provided
-func newOutputFromBytes(bytes []byte) (*Output, int, error) {
-	if len(bytes) < 8 {
-		return nil, 0, fmt.Errorf("%w < 8", ErrOutputTooShort)
+// OutputFromBytes parses a single transaction output from the start of the bytes
+// provided, returning the output and the number of bytes consumed. Any bytes after
+// the output are ignored, so it can be used to walk a buffer of outputs.
+//
+// An ErrOutputTooShort error is returned if the bytes end before the 8 byte satoshi
+// value, the script length varint or the script itself is complete.
+func OutputFromBytes(bytes []byte) (*Output, int, error) {
+	if len(bytes) < 9 {
+		return nil, 0, fmt.Errorf("%w < 9", ErrOutputTooShort)
 	}
 
 	offset := 8
-	l, size := NewVarIntFromBytes(bytes[offset:])
-	offset += size
+	size := 1
+	switch bytes[offset] {
+	case 0xff:
+		size = 9
+	case 0xfe:
+		size = 5
+	case 0xfd:
+		size = 3
+	}
+	if len(bytes) < offset+size {
+		return nil, 0, fmt.Errorf("%w < 8 + varint(%d)", ErrOutputTooShort, size)
+	}
 
-	totalLength := offset + int(l)
+	l, _ := NewVarIntFromBytes(bytes[offset:])
+	offset += size
 
-	if len(bytes) < totalLength {
-		return nil, 0, fmt.Errorf("%w < 8 + script", ErrInputTooShort)
+	if uint64(l) > uint64(len(bytes)-offset) {
+		return nil, 0, fmt.Errorf("%w < 8 + script(%d)", ErrOutputTooShort, l)
 	}
+	totalLength := offset + int(l)
 
-	s := bscript.Script(bytes[offset:totalLength])
+	s := make(bscript.Script, l)
+	copy(s, bytes[offset:totalLength])
 
 	return &Output{
 		Satoshis:      binary.LittleEndian.Uint64(bytes[0:8]),
@@ -35,6 +57,9 @@ func newOutputFromBytes(bytes []byte) (*Output, int, error) {
 }
 
 // TotalOutputSatoshis returns the total Satoshis outputted from the transaction.
+//
+// The total wraps if it overflows, use TotalOutputSatoshisChecked for transactions
+// from untrusted sources.
 func (tx *Tx) TotalOutputSatoshis() (total uint64) {
 	for _, o := range tx.Outputs {
 		total += o.Satoshis
@@ -42,6 +67,114 @@ func (tx *Tx) TotalOutputSatoshis() (total uint64) {
 	return
 }
 
+// TotalOutputSatoshisChecked returns the total Satoshis outputted from the transaction,
+// or ErrSatoshiOverflow if the total overflows.
+func (tx *Tx) TotalOutputSatoshisChecked() (uint64, error) {
+	var total uint64
+	var err error
+	for _, o := range tx.Outputs {
+		if total, err = addSatoshis(total, o.Satoshis); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// totalSatoshisChecked returns the checked input and output totals of the transaction.
+func (tx *Tx) totalSatoshisChecked() (uint64, uint64, error) {
+	in, err := tx.TotalInputSatoshisChecked()
+	if err != nil {
+		return 0, 0, err
+	}
+	out, err := tx.TotalOutputSatoshisChecked()
+	if err != nil {
+		return 0, 0, err
+	}
+	return in, out, nil
+}
+
+// OutputsForPublicKey returns the indices of the P2PKH and P2PK outputs of the
+// transaction which can be spent by the given public key. Both the compressed
+// and uncompressed serialisations of the key (and their hashes) are matched.
+func (tx *Tx) OutputsForPublicKey(pub *ec.PublicKey) []int {
+	compressed := pub.Compressed()
+	uncompressed := pub.Uncompressed()
+	compressedHash := crypto.Hash160(compressed)
+	uncompressedHash := crypto.Hash160(uncompressed)
+
+	matches := make([]int, 0)
+	for i, o := range tx.Outputs {
+		if o.LockingScript == nil {
+			continue
+		}
+
+		switch {
+		case o.LockingScript.IsP2PKH():
+			pkh, err := o.LockingScript.PublicKeyHash()
+			if err != nil {
+				continue
+			}
+			if bytes.Equal(pkh, compressedHash) || bytes.Equal(pkh, uncompressedHash) {
+				matches = append(matches, i)
+			}
+		case o.LockingScript.IsP2PK():
+			parts, err := bscript.DecodeParts(*o.LockingScript)
+			if err != nil {
+				continue
+			}
+			if bytes.Equal(parts[0], compressed) || bytes.Equal(parts[0], uncompressed) {
+				matches = append(matches, i)
+			}
+		}
+	}
+
+	return matches
+}
+
+// PaidTo returns the total satoshis paid to addr by the P2PKH and P2PK outputs
+// of the transaction, or zero if no output pays it. A P2PK output is counted if
+// addr is derived from either the compressed or uncompressed serialisation of its
+// public key. An error is returned only if addr cannot be decoded.
+func (tx *Tx) PaidTo(addr string) (uint64, error) {
+	a, err := bscript.NewAddressFromString(addr)
+	if err != nil {
+		return 0, err
+	}
+
+	var total uint64
+	for _, o := range tx.Outputs {
+		if o.LockingScript == nil {
+			continue
+		}
+
+		switch {
+		case o.LockingScript.IsP2PKH():
+			pkh, err := o.LockingScript.PublicKeyHash()
+			if err != nil {
+				continue
+			}
+			if bytes.Equal(pkh, a.PublicKeyHash) {
+				total += o.Satoshis
+			}
+		case o.LockingScript.IsP2PK():
+			parts, err := bscript.DecodeParts(*o.LockingScript)
+			if err != nil {
+				continue
+			}
+			pub, err := ec.ParsePubKey(parts[0])
+			if err != nil {
+				continue
+			}
+			if bytes.Equal(crypto.Hash160(pub.Compressed()), a.PublicKeyHash) ||
+				bytes.Equal(crypto.Hash160(pub.Uncompressed()), a.PublicKeyHash) {
+				total += o.Satoshis
+			}
+		}
+	}
+
+	return total, nil
+}
+
 // AddP2PKHOutputFromPubKeyHashStr makes an output to a PKH with a value.
 func (tx *Tx) AddP2PKHOutputFromPubKeyHashStr(publicKeyHash string, satoshis uint64) error {
 	s, err := bscript.NewP2PKHFromPubKeyHashStr(publicKeyHash)
@@ -85,7 +218,12 @@ func (tx *Tx) AddP2PKHOutputFromPubKeyStr(publicKey string, satoshis uint64) err
 }
 
 // AddP2PKHOutputFromAddress makes an output to a PKH with a value.
+//
+// A P2PKH output is spendable, so an ErrZeroValueOutput error is returned if satoshis is zero.
 func (tx *Tx) AddP2PKHOutputFromAddress(addr string, satoshis uint64) error {
+	if satoshis == 0 {
+		return ErrZeroValueOutput
+	}
 	s, err := bscript.NewP2PKHFromAddress(addr)
 	if err != nil {
 		return err
@@ -99,10 +237,15 @@ func (tx *Tx) AddP2PKHOutputFromAddress(addr string, satoshis uint64) error {
 }
 
 // AddP2PKHOutputFromScript makes an output to a P2PKH script paid to the provided locking script with a value.
+//
+// A P2PKH output is spendable, so an ErrZeroValueOutput error is returned if satoshis is zero.
 func (tx *Tx) AddP2PKHOutputFromScript(script *bscript.Script, satoshis uint64) error {
 	if !script.IsP2PKH() {
 		return errors.Wrapf(ErrInvalidScriptType, "'%s' is not a valid P2PKH script", script.ScriptType())
 	}
+	if satoshis == 0 {
+		return ErrZeroValueOutput
+	}
 	tx.AddOutput(&Output{
 		Satoshis:      satoshis,
 		LockingScript: script,
@@ -175,6 +318,23 @@ func CreateOpReturnOutput(data [][]byte) (*Output, error) {
 	return &Output{LockingScript: s}, nil
 }
 
+// CheckOutputValues checks each output with Output.Validate against the fee quote's
+// DustThreshold. Data carrier (OP_RETURN) outputs are exempt and may be zero value.
+//
+// An ErrZeroValueOutput error is returned for a zero value spendable output, and an
+// ErrDustOutput error for a spendable output below the threshold, detailing the output index.
+// Outputs with no locking script or a value above MaxSatoshis are also rejected.
+func (tx *Tx) CheckOutputValues(f *FeeQuote) error {
+	threshold := f.DustThreshold()
+	for i, o := range tx.Outputs {
+		if err := o.Validate(threshold); err != nil {
+			return fmt.Errorf("output %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
 // OutputCount returns the number of transaction Inputs.
 func (tx *Tx) OutputCount() int {
 	return len(tx.Outputs)
@@ -185,6 +345,36 @@ func (tx *Tx) AddOutput(output *Output) {
 	tx.Outputs = append(tx.Outputs, output)
 }
 
+// OutputOpts are options for AddOutputWithOpts.
+type OutputOpts struct {
+	// RequireStandard rejects locking scripts which are not of a standard type,
+	// P2PKH, P2PK, multisig, data or P2PKH inscription, or whose data pushes are
+	// not minimally encoded, as such outputs may not be relayed or easily spent.
+	// It is off by default, allowing custom scripts.
+	RequireStandard bool
+}
+
+// AddOutputWithOpts adds an output paying satoshis to lockingScript, checking the
+// script as set out by opts. If OutputOpts.RequireStandard is set and the script
+// is not standard, an error matching ErrNonStandardScript is returned and no
+// output is added.
+func (tx *Tx) AddOutputWithOpts(satoshis uint64, lockingScript *bscript.Script, opts OutputOpts) error {
+	if lockingScript == nil {
+		return bscript.ErrEmptyScript
+	}
+	if opts.RequireStandard {
+		if err := lockingScript.CheckMinimalPushes(); err != nil {
+			return fmt.Errorf("%w: %w", ErrNonStandardScript, err)
+		}
+		if t := lockingScript.ScriptType(); t == bscript.ScriptTypeNonStandard || t == bscript.ScriptTypeEmpty {
+			return fmt.Errorf("%w: script type is %s", ErrNonStandardScript, t)
+		}
+	}
+
+	tx.AddOutput(&Output{LockingScript: lockingScript, Satoshis: satoshis})
+	return nil
+}
+
 // PayTo creates a new P2PKH output from a BitCoin address (base58)
 // and the satoshis amount and adds that to the transaction.
 func (tx *Tx) PayTo(script *bscript.Script, satoshis uint64) error {
@@ -196,3 +386,127 @@ func (tx *Tx) PayTo(script *bscript.Script, satoshis uint64) error {
 func (tx *Tx) PayToAddress(addr string, satoshis uint64) error {
 	return tx.AddP2PKHOutputFromAddress(addr, satoshis)
 }
+
+// Payment is a single payment made by AddPayments.
+type Payment struct {
+	// Address is the base58 P2PKH address to pay.
+	Address string
+	// Satoshis is the amount to pay, which must not be zero.
+	Satoshis uint64
+	// Data is optional OP_RETURN data, which is added in its own zero value
+	// OP_FALSE OP_RETURN output directly after the payment output.
+	Data [][]byte
+}
+
+// AddPayments adds the outputs for each payment, in order, and returns the index
+// of the first output added. Either every payment is added or, if any payment is
+// invalid, none are and an error detailing the payment index is returned.
+func (tx *Tx) AddPayments(payments []Payment) (int, error) {
+	pending := &Tx{}
+	for i, p := range payments {
+		if err := pending.PayToAddress(p.Address, p.Satoshis); err != nil {
+			return 0, errors.Wrapf(err, "payment %d", i)
+		}
+		if len(p.Data) == 0 {
+			continue
+		}
+		if err := pending.AddOpReturnPartsOutput(p.Data); err != nil {
+			return 0, errors.Wrapf(err, "payment %d", i)
+		}
+	}
+
+	start := len(tx.Outputs)
+	tx.Outputs = append(tx.Outputs, pending.Outputs...)
+	return start, nil
+}
+
+// SplitOutput replaces the output at index with count outputs to the same locking
+// script, for creating smaller denominations. The new outputs share the original
+// value, less the fee for the bytes added by the extra outputs at the fee quote's
+// standard rate. Any remainder from dividing the value evenly is added to the first
+// of the new outputs, which takes the place of the original output.
+//
+// An ErrDustOutput error is returned, and the tx left unchanged, if the outputs
+// would be below the fee quote's DustThreshold. A count of one leaves the tx unchanged.
+func (tx *Tx) SplitOutput(index int, count int, fq *FeeQuote) error {
+	if index < 0 || index >= tx.OutputCount() {
+		return ErrOutputNoExist
+	}
+	if count < 1 {
+		return ErrInvalidSplitCount
+	}
+	if count == 1 {
+		return nil
+	}
+
+	o := tx.Outputs[index]
+	if o.LockingScript == nil {
+		return bscript.ErrEmptyScript
+	}
+	stdFee, err := fq.Fee(FeeTypeStandard)
+	if err != nil {
+		return err
+	}
+
+	extra := uint64(count - 1)
+	addedBytes := extra*outputSize(o.LockingScript) +
+		uint64(VarInt(uint64(tx.OutputCount())+extra).Length()-VarInt(uint64(tx.OutputCount())).Length())
+	fee, err := mulDivSatoshis(addedBytes, uint64(stdFee.MiningFee.Satoshis), uint64(stdFee.MiningFee.Bytes))
+	if err != nil {
+		return err
+	}
+	if fee >= o.Satoshis {
+		return fmt.Errorf("%w: %d satoshis cannot cover the %d satoshi fee for %d outputs", ErrDustOutput, o.Satoshis, fee, count)
+	}
+
+	total := o.Satoshis - fee
+	each := total / uint64(count)
+	if each < fq.DustThreshold() {
+		return fmt.Errorf("%w: %d outputs of %d satoshis, threshold is %d", ErrDustOutput, count, each, fq.DustThreshold())
+	}
+
+	// each output gets its own copy of the script, so editing one leaves the
+	// others unchanged
+	split := make([]*Output, count)
+	for i := range split {
+		split[i] = &Output{Satoshis: each, LockingScript: bscript.NewFromBytes(append([]byte{}, *o.LockingScript...))}
+	}
+	split[0].Satoshis += total % uint64(count)
+
+	outputs := make([]*Output, 0, tx.OutputCount()+count-1)
+	outputs = append(outputs, tx.Outputs[:index]...)
+	outputs = append(outputs, split...)
+	tx.Outputs = append(outputs, tx.Outputs[index+1:]...)
+
+	return nil
+}
+
+// RetargetOutput replaces the locking script of the output at index with a P2PKH
+// script paying addr, keeping its value. Unlike removing the output and adding a
+// new one, the output keeps its index, so signatures using SIGHASH_SINGLE on other
+// inputs stay paired with the right outputs. Any signatures covering the output
+// are invalidated and need to be redone.
+//
+// An ErrOutputNoExist error is returned if there is no output at index, and an
+// ErrInvalidAddressNetwork error if addr is not a mainnet or testnet P2PKH address
+// with a valid checksum. The tx is left unchanged on error.
+func (tx *Tx) RetargetOutput(index int, addr string) error {
+	if index < 0 || index >= tx.OutputCount() {
+		return ErrOutputNoExist
+	}
+
+	_, version, err := base58.CheckDecode(addr)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddressNetwork, "%s: %s", addr, err)
+	}
+	if version != chaincfg.MainNet.LegacyPubKeyHashAddrID && version != chaincfg.TestNet.LegacyPubKeyHashAddrID {
+		return errors.Wrapf(ErrInvalidAddressNetwork, "%s has version %#02x", addr, version)
+	}
+	s, err := bscript.NewP2PKHFromAddress(addr)
+	if err != nil {
+		return err
+	}
+
+	tx.Outputs[index].LockingScript = s
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
 	"github.com/bitcoin-sv/go-sdk/transaction"
 	"github.com/stretchr/testify/assert"
 )
@@ -177,6 +179,46 @@ func TestTx_PayToAddress(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 1, tx.OutputCount())
 	})
+
+	t.Run("zero satoshis", func(t *testing.T) {
+		tx := transaction.NewTx()
+		err := tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 0)
+		assert.ErrorIs(t, err, transaction.ErrZeroValueOutput)
+		assert.Equal(t, 0, tx.OutputCount())
+	})
+}
+
+func TestTx_CheckOutputValues(t *testing.T) {
+	t.Parallel()
+
+	fq := transaction.NewFeeQuote()
+
+	t.Run("zero value data output", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+		assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 1000))
+		assert.True(t, tx.Outputs[0].IsDataCarrier())
+		assert.False(t, tx.Outputs[0].IsDust(fq))
+		assert.NoError(t, tx.CheckOutputValues(fq))
+	})
+
+	t.Run("zero value spendable output", func(t *testing.T) {
+		s, err := bscript.NewP2PKHFromAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi")
+		assert.NoError(t, err)
+		tx := transaction.NewTx()
+		tx.AddOutput(&transaction.Output{LockingScript: s})
+		assert.False(t, tx.Outputs[0].IsDataCarrier())
+		assert.ErrorIs(t, tx.CheckOutputValues(fq), transaction.ErrZeroValueOutput)
+	})
+
+	t.Run("dust output", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 1))
+		assert.True(t, tx.Outputs[0].IsDust(fq))
+		err := tx.CheckOutputValues(fq)
+		assert.ErrorIs(t, err, transaction.ErrDustOutput)
+		assert.Contains(t, err.Error(), "output 0")
+	})
 }
 
 func TestTx_PayTo(t *testing.T) {
@@ -221,3 +263,344 @@ func TestTx_PayTo(t *testing.T) {
 		})
 	}
 }
+
+func TestTx_OutputsForPublicKey(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	other, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	pub := priv.PubKey()
+
+	p2pk := func(pubKey []byte) *bscript.Script {
+		s := &bscript.Script{}
+		assert.NoError(t, s.AppendPushData(pubKey))
+		assert.NoError(t, s.AppendOpcodes(bscript.OpCHECKSIG))
+		return s
+	}
+
+	tx := transaction.NewTx()
+	assert.NoError(t, tx.AddP2PKHOutputFromPubKeyBytes(pub.Compressed(), 1000))
+	assert.NoError(t, tx.AddP2PKHOutputFromPubKeyBytes(other.PubKey().Compressed(), 1000))
+	assert.NoError(t, tx.AddP2PKHOutputFromPubKeyHashStr(hex.EncodeToString(crypto.Hash160(pub.Uncompressed())), 1000))
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+	tx.AddOutput(&transaction.Output{Satoshis: 1000, LockingScript: p2pk(pub.Compressed())})
+	tx.AddOutput(&transaction.Output{Satoshis: 1000, LockingScript: p2pk(pub.Uncompressed())})
+	tx.AddOutput(&transaction.Output{Satoshis: 1000, LockingScript: p2pk(other.PubKey().Compressed())})
+
+	assert.Equal(t, []int{0, 2, 4, 5}, tx.OutputsForPublicKey(pub))
+	assert.Equal(t, []int{1, 6}, tx.OutputsForPublicKey(other.PubKey()))
+}
+
+func TestTx_PaidTo(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	other, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	pub := priv.PubKey()
+
+	p2pk := func(pubKey []byte) *bscript.Script {
+		s := &bscript.Script{}
+		assert.NoError(t, s.AppendPushData(pubKey))
+		assert.NoError(t, s.AppendOpcodes(bscript.OpCHECKSIG))
+		return s
+	}
+
+	compressedAddr, err := bscript.NewAddressFromPublicKeyHash(crypto.Hash160(pub.Compressed()), true)
+	assert.NoError(t, err)
+	uncompressedAddr, err := bscript.NewAddressFromPublicKeyHash(crypto.Hash160(pub.Uncompressed()), false)
+	assert.NoError(t, err)
+	otherAddr, err := bscript.NewAddressFromPublicKey(other.PubKey(), true)
+	assert.NoError(t, err)
+	unusedAddr, err := bscript.NewAddressFromPublicKeyHash(make([]byte, 20), true)
+	assert.NoError(t, err)
+
+	tx := transaction.NewTx()
+	assert.NoError(t, tx.PayToAddress(compressedAddr.AddressString, 1000))
+	assert.NoError(t, tx.PayToAddress(otherAddr.AddressString, 2000))
+	assert.NoError(t, tx.PayToAddress(uncompressedAddr.AddressString, 3000))
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+	tx.AddOutput(&transaction.Output{Satoshis: 400, LockingScript: p2pk(pub.Compressed())})
+	tx.AddOutput(&transaction.Output{Satoshis: 50, LockingScript: p2pk(pub.Uncompressed())})
+
+	tests := map[string]struct {
+		addr string
+		exp  uint64
+	}{
+		"compressed key address":   {addr: compressedAddr.AddressString, exp: 1450},
+		"uncompressed key address": {addr: uncompressedAddr.AddressString, exp: 3450},
+		"other address":            {addr: otherAddr.AddressString, exp: 2000},
+		"unpaid address":           {addr: unusedAddr.AddressString, exp: 0},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			total, err := tx.PaidTo(test.addr)
+			assert.NoError(t, err)
+			assert.Equal(t, test.exp, total)
+		})
+	}
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := tx.PaidTo("not an address")
+		assert.Error(t, err)
+	})
+}
+
+func TestTx_AddPayments(t *testing.T) {
+	t.Parallel()
+
+	t.Run("payments with data", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 500))
+
+		start, err := tx.AddPayments([]transaction.Payment{
+			{Address: "n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", Satoshis: 1000, Data: [][]byte{[]byte("invoice"), []byte("42")}},
+			{Address: "1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", Satoshis: 2000},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, start)
+		assert.Equal(t, 4, tx.OutputCount())
+
+		assert.Equal(t, uint64(1000), tx.Outputs[1].Satoshis)
+		assert.True(t, tx.Outputs[2].IsDataCarrier())
+		assert.Equal(t, "006a07696e766f696365023432", tx.Outputs[2].LockingScriptHex())
+		assert.Equal(t, uint64(2000), tx.Outputs[3].Satoshis)
+	})
+
+	t.Run("invalid payment adds nothing", func(t *testing.T) {
+		tx := transaction.NewTx()
+		_, err := tx.AddPayments([]transaction.Payment{
+			{Address: "n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", Satoshis: 1000},
+			{Address: "n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", Satoshis: 0},
+		})
+		assert.ErrorIs(t, err, transaction.ErrZeroValueOutput)
+		assert.Contains(t, err.Error(), "payment 1")
+		assert.Zero(t, tx.OutputCount())
+
+		_, err = tx.AddPayments([]transaction.Payment{{Address: "invalid", Satoshis: 1000}})
+		assert.Error(t, err)
+		assert.Zero(t, tx.OutputCount())
+	})
+}
+
+func TestTx_SplitOutput(t *testing.T) {
+	t.Parallel()
+
+	script, err := bscript.NewP2PKHFromAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		sats          uint64
+		index         int
+		n             int
+		dustThreshold uint64
+		expErr        error
+		expOutputs    int
+	}{
+		"splits into three": {
+			sats:       10000,
+			index:      1,
+			n:          3,
+			expOutputs: 5,
+		},
+		"split into one is a no-op": {
+			sats:       1000,
+			index:      1,
+			n:          1,
+			expOutputs: 3,
+		},
+		"dust": {
+			sats:          1000,
+			index:         1,
+			n:             10,
+			dustThreshold: 100,
+			expErr:        transaction.ErrDustOutput,
+			expOutputs:    3,
+		},
+		"dust at the default threshold": {
+			sats:       1000,
+			index:      1,
+			n:          1000,
+			expErr:     transaction.ErrDustOutput,
+			expOutputs: 3,
+		},
+		"index too large": {
+			sats:       1000,
+			index:      3,
+			n:          2,
+			expErr:     transaction.ErrOutputNoExist,
+			expOutputs: 3,
+		},
+		"negative index": {
+			sats:       1000,
+			index:      -1,
+			n:          2,
+			expErr:     transaction.ErrOutputNoExist,
+			expOutputs: 3,
+		},
+		"zero count": {
+			sats:       1000,
+			index:      1,
+			n:          0,
+			expErr:     transaction.ErrInvalidSplitCount,
+			expOutputs: 3,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := transaction.NewTx()
+			assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 100))
+			assert.NoError(t, tx.PayTo(script, test.sats))
+			assert.NoError(t, tx.AddOpReturnOutput([]byte("hi")))
+			fq := transaction.NewFeeQuote()
+			if test.dustThreshold > 0 {
+				fq.SetDustThreshold(test.dustThreshold)
+			}
+			before := tx.Size()
+
+			err := tx.SplitOutput(test.index, test.n, fq)
+			assert.ErrorIs(t, err, test.expErr)
+			assert.Equal(t, test.expOutputs, tx.OutputCount())
+			assert.Equal(t, uint64(100), tx.Outputs[0].Satoshis)
+			assert.True(t, tx.Outputs[tx.OutputCount()-1].LockingScript.IsData())
+			if test.expErr != nil || test.n == 1 {
+				assert.Equal(t, test.sats, tx.Outputs[1].Satoshis)
+				return
+			}
+
+			stdFee, err := fq.Fee(transaction.FeeTypeStandard)
+			assert.NoError(t, err)
+			fee := uint64(tx.Size()-before) * uint64(stdFee.MiningFee.Satoshis) / uint64(stdFee.MiningFee.Bytes)
+			split := tx.Outputs[1 : 1+test.n]
+			var total uint64
+			for _, o := range split {
+				assert.Equal(t, script, o.LockingScript)
+				total += o.Satoshis
+			}
+			assert.Equal(t, test.sats-fee, total)
+			// any remainder goes to the first output
+			for _, o := range split[1:] {
+				assert.Equal(t, split[1].Satoshis, o.Satoshis)
+			}
+			assert.Equal(t, split[1].Satoshis+(test.sats-fee)%uint64(test.n), split[0].Satoshis)
+
+			// each output has its own copy of the script
+			(*split[0].LockingScript)[0] = bscript.OpRETURN
+			for _, o := range split[1:] {
+				assert.Equal(t, script, o.LockingScript)
+			}
+		})
+	}
+}
+
+func TestTx_AddOutputWithOpts(t *testing.T) {
+	t.Parallel()
+
+	p2pkh, err := bscript.NewFromHex("76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+	data, err := bscript.NewFromASM("OP_FALSE OP_RETURN 68656c6c6f")
+	assert.NoError(t, err)
+	custom, err := bscript.NewFromASM("OP_2 OP_3 OP_ADD OP_5 OP_EQUAL")
+	assert.NoError(t, err)
+	// a P2PKH script with its hash pushed by OP_PUSHDATA1
+	nonMinimal, err := bscript.NewFromHex("76a94c148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+
+	standard := transaction.OutputOpts{RequireStandard: true}
+
+	t.Run("standard scripts", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.AddOutputWithOpts(1000, p2pkh, standard))
+		assert.NoError(t, tx.AddOutputWithOpts(0, data, standard))
+		assert.Equal(t, 2, tx.OutputCount())
+		assert.Equal(t, uint64(1000), tx.Outputs[0].Satoshis)
+	})
+
+	t.Run("non-standard scripts rejected", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.ErrorIs(t, tx.AddOutputWithOpts(1000, custom, standard), transaction.ErrNonStandardScript)
+		err := tx.AddOutputWithOpts(1000, nonMinimal, standard)
+		assert.ErrorIs(t, err, transaction.ErrNonStandardScript)
+		assert.ErrorIs(t, err, bscript.ErrNonMinimalPush)
+		assert.ErrorIs(t, tx.AddOutputWithOpts(1000, &bscript.Script{}, standard), transaction.ErrNonStandardScript)
+		assert.Zero(t, tx.OutputCount())
+	})
+
+	t.Run("non-standard allowed by default", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.AddOutputWithOpts(1000, custom, transaction.OutputOpts{}))
+		assert.NoError(t, tx.AddOutputWithOpts(1000, nonMinimal, transaction.OutputOpts{}))
+		assert.Equal(t, 2, tx.OutputCount())
+	})
+
+	t.Run("nil script", func(t *testing.T) {
+		assert.ErrorIs(t, transaction.NewTx().AddOutputWithOpts(1000, nil, transaction.OutputOpts{}), bscript.ErrEmptyScript)
+	})
+}
+
+func TestTx_RetargetOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		index  int
+		addr   string
+		expErr error
+	}{
+		"mainnet address": {
+			index: 0,
+			addr:  "1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi",
+		},
+		"testnet address": {
+			index: 0,
+			addr:  "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd",
+		},
+		"index too large": {
+			index:  2,
+			addr:   "1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi",
+			expErr: transaction.ErrOutputNoExist,
+		},
+		"negative index": {
+			index:  -1,
+			addr:   "1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi",
+			expErr: transaction.ErrOutputNoExist,
+		},
+		"bad checksum": {
+			index:  0,
+			addr:   "1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZj",
+			expErr: transaction.ErrInvalidAddressNetwork,
+		},
+		"p2sh address": {
+			index:  0,
+			addr:   "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+			expErr: transaction.ErrInvalidAddressNetwork,
+		},
+		"empty address": {
+			index:  0,
+			expErr: transaction.ErrInvalidAddressNetwork,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := transaction.NewTx()
+			assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 100))
+			assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 200))
+			before := tx.String()
+
+			err := tx.RetargetOutput(test.index, test.addr)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				assert.Equal(t, before, tx.String())
+				return
+			}
+			assert.NoError(t, err)
+			want, err := bscript.NewP2PKHFromAddress(test.addr)
+			assert.NoError(t, err)
+			assert.Equal(t, want, tx.Outputs[test.index].LockingScript)
+			assert.Equal(t, uint64(100), tx.Outputs[0].Satoshis)
+			assert.Equal(t, uint64(200), tx.Outputs[1].Satoshis)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Package txtest builds deterministic, fully signed transactions for use in tests,
+// so that packages building on the SDK do not need to craft them by hand.
+//
+// Keys, utxos and amounts are generated from an RNG with the fixed Seed, and
+// signatures are deterministic (RFC6979), so the same arguments always produce the
+// same transaction. The utxos are dummies, spending txids which do not exist on
+// chain, but are consistent with the keys and the transaction spending them.
+package txtest
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+)
+
+// Seed is the seed of the RNG that fixtures are generated from.
+const Seed = 1
+
+// UTXOSatoshis is the value of each utxo spent by a fixture.
+const UTXOSatoshis = 10000
+
+// Fixture is a signed transaction along with the keys and utxos used to build it.
+type Fixture struct {
+	// Tx is the signed transaction, paying the default fee rate.
+	Tx *transaction.Tx
+	// Keys holds the key owning each utxo, Keys[i] owning UTXOs[i].
+	Keys []*ec.PrivateKey
+	// UTXOs holds the P2PKH utxos spent by the transaction, UTXOs[i] being spent
+	// by input i.
+	UTXOs []*transaction.UTXO
+	// Recipients holds the key owning each output, Recipients[i] owning output i.
+	Recipients []*ec.PrivateKey
+}
+
+// NewFundedTx returns a signed transaction with numInputs P2PKH inputs and
+// numOutputs P2PKH outputs, see NewFixture.
+func NewFundedTx(t testing.TB, numInputs, numOutputs int) *transaction.Tx {
+	t.Helper()
+	return NewFixture(t, numInputs, numOutputs).Tx
+}
+
+// NewFixture returns a Fixture with numInputs P2PKH inputs, each spending a utxo
+// of UTXOSatoshis, and numOutputs P2PKH outputs sharing the input value less the
+// fee charged by transaction.DefaultFeeQuote. Any remainder from sharing the value
+// goes to the first output.
+//
+// The test fails immediately if either count is less than one or the inputs cannot
+// cover the fee and an output of at least one satoshi each.
+func NewFixture(t testing.TB, numInputs, numOutputs int) *Fixture {
+	t.Helper()
+	if numInputs < 1 || numOutputs < 1 {
+		t.Fatalf("txtest: need at least one input and output, got %d and %d", numInputs, numOutputs)
+	}
+
+	rng := rand.New(rand.NewSource(Seed)) //nolint:gosec // deterministic fixtures, not keys for real funds
+	f := &Fixture{
+		Tx:         transaction.NewTx(),
+		Keys:       make([]*ec.PrivateKey, numInputs),
+		UTXOs:      make([]*transaction.UTXO, numInputs),
+		Recipients: make([]*ec.PrivateKey, numOutputs),
+	}
+
+	for i := range f.Keys {
+		f.Keys[i] = newKey(t, rng)
+		txID := make([]byte, 32)
+		fill(rng, txID)
+		f.UTXOs[i] = &transaction.UTXO{
+			TxID:          txID,
+			Vout:          uint32(rng.Intn(4)),
+			LockingScript: p2pkh(t, f.Keys[i]),
+			Satoshis:      UTXOSatoshis,
+		}
+	}
+	if err := f.Tx.FromUTXOs(f.UTXOs...); err != nil {
+		t.Fatalf("txtest: adding inputs: %v", err)
+	}
+
+	for i := range f.Recipients {
+		f.Recipients[i] = newKey(t, rng)
+		f.Tx.AddOutput(&transaction.Output{LockingScript: p2pkh(t, f.Recipients[i]), Satoshis: 1})
+	}
+
+	fees, err := f.Tx.EstimateFeesPaid(transaction.DefaultFeeQuote())
+	if err != nil {
+		t.Fatalf("txtest: estimating fees: %v", err)
+	}
+	total := uint64(numInputs) * UTXOSatoshis
+	if total < fees.TotalFeePaid+uint64(numOutputs) {
+		t.Fatalf("txtest: %d inputs cannot fund %d outputs", numInputs, numOutputs)
+	}
+	share := (total - fees.TotalFeePaid) / uint64(numOutputs)
+	for _, o := range f.Tx.Outputs {
+		o.Satoshis = share
+	}
+	f.Tx.Outputs[0].Satoshis += (total - fees.TotalFeePaid) % uint64(numOutputs)
+
+	for i, k := range f.Keys {
+		if err = f.Tx.FillInput(context.Background(), &unlocker.Simple{PrivateKey: k},
+			transaction.UnlockerParams{InputIdx: uint32(i)}); err != nil {
+			t.Fatalf("txtest: signing input %d: %v", i, err)
+		}
+	}
+
+	return f
+}
+
+func newKey(t testing.TB, rng *rand.Rand) *ec.PrivateKey {
+	t.Helper()
+	b := make([]byte, 32)
+	for {
+		fill(rng, b)
+		// out of range keys are vanishingly rare, but retry rather than fail
+		if k, err := ec.ParsePrivateKey(b); err == nil {
+			return k
+		}
+	}
+}
+
+// fill fills b, whose length must be a multiple of 8, from rng.
+func fill(rng *rand.Rand, b []byte) {
+	for i := 0; i < len(b); i += 8 {
+		binary.LittleEndian.PutUint64(b[i:], rng.Uint64())
+	}
+}
+
+func p2pkh(t testing.TB, k *ec.PrivateKey) *bscript.Script {
+	t.Helper()
+	s, err := bscript.NewP2PKHFromPubKeyEC(k.PubKey())
+	if err != nil {
+		t.Fatalf("txtest: building locking script: %v", err)
+	}
+	return s
+}
@@ -0,0 +1,44 @@
+package txtest_test
+
+import (
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/txtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFixture(t *testing.T) {
+	t.Parallel()
+
+	f := txtest.NewFixture(t, 3, 2)
+	assert.Equal(t, 3, f.Tx.InputCount())
+	assert.Equal(t, 2, f.Tx.OutputCount())
+	assert.Len(t, f.Keys, 3)
+	assert.Len(t, f.UTXOs, 3)
+	assert.Len(t, f.Recipients, 2)
+	assert.NoError(t, f.Tx.VerifyInputSignatures())
+
+	ok, err := f.Tx.IsFeePaidEnough(transaction.DefaultFeeQuote())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3*txtest.UTXOSatoshis), f.Tx.TotalInputSatoshis())
+
+	for i, u := range f.UTXOs {
+		assert.Equal(t, u.TxIDStr(), f.Tx.Inputs[i].PreviousTxIDStr())
+		assert.Equal(t, u.LockingScript.String(), f.Tx.Inputs[i].PreviousTxScript.String())
+	}
+	for i, k := range f.Recipients {
+		assert.Equal(t, []int{i}, f.Tx.OutputsForPublicKey(k.PubKey()))
+	}
+}
+
+func TestNewFundedTx_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	a := txtest.NewFundedTx(t, 2, 3)
+	b := txtest.NewFundedTx(t, 2, 3)
+	assert.Equal(t, a.TxID(), b.TxID())
+	assert.Equal(t, a.Bytes(), b.Bytes())
+	assert.NotEqual(t, a.TxID(), txtest.NewFundedTx(t, 1, 3).TxID())
+}
@@ -0,0 +1,88 @@
+package unlocker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+)
+
+// ErrRedeemScriptMismatch is returned when a redeem script does not hash to the
+// hash in the P2SH locking script being spent.
+var ErrRedeemScriptMismatch = errors.New("redeem script does not match the p2sh script hash")
+
+// P2SH implements the `transaction.Unlocker` interface for spending historical
+// P2SH (OP_HASH160 <hash> OP_EQUAL) outputs.
+//
+// The Unlocker builds the data satisfying the RedeemScript, as though the
+// RedeemScript were the locking script being spent, and the RedeemScript is then
+// pushed after it. For example, a `*unlocker.Simple` can spend a P2SH output whose
+// redeem script is P2PKH.
+type P2SH struct {
+	RedeemScript *bscript.Script
+	Unlocker     transaction.Unlocker
+}
+
+// UnlockingScript builds the unlocking script for a P2SH input, returning an
+// ErrRedeemScriptMismatch error if the RedeemScript does not hash to the script
+// hash being spent.
+//
+// The Unlocker signs against the RedeemScript, which is the script code signed
+// for a P2SH input, rather than the P2SH locking script.
+func (p *P2SH) UnlockingScript(ctx context.Context, tx *transaction.Tx, params transaction.UnlockerParams) (*bscript.Script, error) {
+	in := tx.Inputs[params.InputIdx]
+	if in.PreviousTxScript == nil {
+		return nil, transaction.ErrEmptyPreviousTxScript
+	}
+	if !in.PreviousTxScript.IsP2SH() {
+		return nil, fmt.Errorf("%w '%s', expected p2sh", transaction.ErrUnsupportedScriptType, in.PreviousTxScript.ScriptType())
+	}
+	if p.RedeemScript == nil || !matchesScriptHash(in.PreviousTxScript, p.RedeemScript) {
+		return nil, ErrRedeemScriptMismatch
+	}
+	if p.Unlocker == nil {
+		return nil, transaction.ErrNoUnlocker
+	}
+
+	// Let the unlocker see the redeem script as the script being spent, without
+	// touching the caller's tx.
+	redeemTx := *tx
+	redeemTx.Inputs = append([]*transaction.Input(nil), tx.Inputs...)
+	redeemIn := *in
+	redeemIn.PreviousTxScript = p.RedeemScript
+	redeemTx.Inputs[params.InputIdx] = &redeemIn
+
+	data, err := p.Unlocker.UnlockingScript(ctx, &redeemTx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	uscript := new(bscript.Script)
+	if data != nil {
+		*uscript = append(*uscript, *data...)
+	}
+	if err = uscript.AppendPushData(*p.RedeemScript); err != nil {
+		return nil, err
+	}
+
+	return uscript, nil
+}
+
+// redeemScriptFor returns the first of the redeem scripts matching the P2SH
+// locking script, or nil if none do.
+func redeemScriptFor(lockingScript *bscript.Script, redeemScripts []*bscript.Script) *bscript.Script {
+	for _, rs := range redeemScripts {
+		if rs != nil && matchesScriptHash(lockingScript, rs) {
+			return rs
+		}
+	}
+	return nil
+}
+
+func matchesScriptHash(p2sh, redeemScript *bscript.Script) bool {
+	return bytes.Equal((*p2sh)[2:22], crypto.Hash160(*redeemScript))
+}
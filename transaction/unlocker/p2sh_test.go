@@ -0,0 +1,96 @@
+package unlocker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/bscript/interpreter"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec/wif"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestP2SH_UnlockingScript(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	redeemScript, err := bscript.NewP2PKHFromPubKeyEC(w.PrivKey.PubKey())
+	assert.NoError(t, err)
+	lockingScript, err := bscript.NewP2SH(crypto.Hash160(*redeemScript))
+	assert.NoError(t, err)
+	withRedeemScript := &unlocker.Getter{PrivateKey: w.PrivKey, RedeemScripts: []*bscript.Script{redeemScript}}
+
+	tests := map[string]struct {
+		fill     func(tx *transaction.Tx) error
+		tamper   func(tx *transaction.Tx)
+		expErr   error
+		expValid bool
+	}{
+		"getter spends p2sh with a p2pkh redeem script": {
+			fill: func(tx *transaction.Tx) error {
+				return tx.FillAllInputs(context.Background(), withRedeemScript)
+			},
+			expValid: true,
+		},
+		"tampered tx fails": {
+			fill: func(tx *transaction.Tx) error {
+				return tx.FillAllInputs(context.Background(), withRedeemScript)
+			},
+			tamper: func(tx *transaction.Tx) {
+				tx.Outputs[0].Satoshis = 800
+			},
+		},
+		"getter without the redeem script": {
+			fill: func(tx *transaction.Tx) error {
+				return tx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: w.PrivKey})
+			},
+			expErr: unlocker.ErrRedeemScriptMismatch,
+		},
+		"unlocker with another redeem script": {
+			fill: func(tx *transaction.Tx) error {
+				return tx.FillInput(context.Background(), &unlocker.P2SH{
+					RedeemScript: bscript.NewFromBytes([]byte{bscript.Op1}),
+					Unlocker:     &unlocker.Simple{PrivateKey: w.PrivKey},
+				}, transaction.UnlockerParams{InputIdx: 0})
+			},
+			expErr: unlocker.ErrRedeemScriptMismatch,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := transaction.NewTx()
+			assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, lockingScript.String(), 1000))
+			assert.NoError(t, tx.PayTo(redeemScript, 900))
+
+			err := test.fill(tx)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			parts, err := bscript.DecodeParts(*tx.Inputs[0].UnlockingScript)
+			assert.NoError(t, err)
+			assert.Len(t, parts, 3)
+			assert.Equal(t, []byte(*redeemScript), parts[2])
+			assert.Equal(t, lockingScript, tx.Inputs[0].PreviousTxScript)
+
+			if test.tamper != nil {
+				test.tamper(tx)
+			}
+			err = interpreter.NewEngine().Execute(
+				interpreter.WithTx(tx, 0, &transaction.Output{LockingScript: lockingScript, Satoshis: 1000}),
+				interpreter.WithForkID(),
+				interpreter.WithP2SH(),
+			)
+			if test.expValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -3,7 +3,7 @@ package unlocker
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
 	"github.com/bitcoin-sv/go-sdk/ec"
@@ -13,22 +13,53 @@ import (
 
 // Getter implements the `bt.UnlockerGetter` interface. It unlocks a Tx locally,
 // using a bec PrivateKey.
+//
+// RedeemScripts optionally lists the redeem scripts of P2SH outputs to be spent.
+// A P2SH locking script is unlocked with a `*unlocker.P2SH` using the redeem script
+// matching its hash, which must itself be a script the `*unlocker.Simple` can unlock.
+//
+// LowR is passed to the unlockers returned, see Simple.
 type Getter struct {
-	PrivateKey *ec.PrivateKey
+	PrivateKey    *ec.PrivateKey
+	RedeemScripts []*bscript.Script
+	LowR          bool
 }
 
 // Unlocker builds a new `*unlocker.Local` with the same private key
 // as the calling `*local.Getter`.
 //
+// If the locking script is not of a type the `*unlocker.Simple` can unlock,
+// a `transaction.ErrUnsupportedScriptType` error naming the type is returned.
+//
 // For an example implementation, see `examples/unlocker_getter/`.
 func (g *Getter) Unlocker(ctx context.Context, lockingScript *bscript.Script) (transaction.Unlocker, error) {
-	return &Simple{PrivateKey: g.PrivateKey}, nil
+	if lockingScript == nil {
+		return nil, transaction.ErrEmptyPreviousTxScript
+	}
+	if lockingScript.IsP2SH() {
+		rs := redeemScriptFor(lockingScript, g.RedeemScripts)
+		if rs == nil {
+			return nil, fmt.Errorf("%w: no redeem script for p2sh script %s", ErrRedeemScriptMismatch, lockingScript)
+		}
+		if !isSupported(rs) {
+			return nil, unsupportedScriptTypeError(rs)
+		}
+		return &P2SH{RedeemScript: rs, Unlocker: &Simple{PrivateKey: g.PrivateKey, LowR: g.LowR}}, nil
+	}
+	if !isSupported(lockingScript) {
+		return nil, unsupportedScriptTypeError(lockingScript)
+	}
+	return &Simple{PrivateKey: g.PrivateKey, LowR: g.LowR}, nil
 }
 
 // Simple implements the a simple `bt.Unlocker` interface. It is used to build an unlocking script
 // using a bec Private Key.
+//
+// If LowR is set, signatures are made with `ec.PrivateKey.SignLowR`, grinding the
+// nonce so each signature is a byte smaller, at around twice the signing cost.
 type Simple struct {
 	PrivateKey *ec.PrivateKey
+	LowR       bool
 }
 
 // UnlockingScript create the unlocking script for a given input using the PrivateKey passed in through the
@@ -55,7 +86,11 @@ func (l *Simple) UnlockingScript(ctx context.Context, tx *transaction.Tx, params
 			return nil, err
 		}
 
-		sig, err := l.PrivateKey.Sign(sh)
+		sign := l.PrivateKey.Sign
+		if l.LowR {
+			sign = l.PrivateKey.SignLowR
+		}
+		sig, err := sign(sh)
 		if err != nil {
 			return nil, err
 		}
@@ -71,5 +106,19 @@ func (l *Simple) UnlockingScript(ctx context.Context, tx *transaction.Tx, params
 		return uscript, nil
 	}
 
-	return nil, errors.New("currently only p2pkh supported")
+	return nil, unsupportedScriptTypeError(tx.Inputs[params.InputIdx].PreviousTxScript)
+}
+
+// isSupported returns true if the locking script can be unlocked by `*unlocker.Simple`.
+func isSupported(lockingScript *bscript.Script) bool {
+	switch lockingScript.ScriptType() {
+	case bscript.ScriptTypePubKeyHash, bscript.ScriptTypePubKeyHashInscription:
+		return true
+	}
+	return false
+}
+
+func unsupportedScriptTypeError(lockingScript *bscript.Script) error {
+	return fmt.Errorf("%w '%s', currently only p2pkh supported",
+		transaction.ErrUnsupportedScriptType, lockingScript.ScriptType())
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/bitcoin-sv/go-sdk/ec/wif"
 	"github.com/bitcoin-sv/go-sdk/sighash"
 	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/txtest"
 	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
 	"github.com/stretchr/testify/assert"
 )
@@ -369,3 +370,88 @@ func TestLocalUnlocker_NonSignature(t *testing.T) {
 // 	}
 //
 // }
+
+func TestGetter_UnsupportedScriptType(t *testing.T) {
+	t.Parallel()
+
+	tx := transaction.NewTx()
+	assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 1000))
+	assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 1, "5221023ff1fd1e7b0b3e2a9d5b6ad8ec2b4bb1f4cdd1d7f0e3d8ad0f8c3f8c1a1b2c3d21023ff1fd1e7b0b3e2a9d5b6ad8ec2b4bb1f4cdd1d7f0e3d8ad0f8c3f8c1a1b2c3d52ae", 1000))
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+
+	err = tx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: priv})
+	assert.ErrorIs(t, err, transaction.ErrUnsupportedScriptType)
+	assert.Contains(t, err.Error(), "input 1")
+	assert.Contains(t, err.Error(), bscript.ScriptTypeMultiSig)
+}
+
+func TestTx_VerifyInputSignatures(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		tamper    func(t *testing.T, f *txtest.Fixture)
+		expErrMsg string
+	}{
+		"valid signatures": {},
+		"tampered output invalidates signature": {
+			tamper: func(t *testing.T, f *txtest.Fixture) {
+				f.Tx.Outputs[0].Satoshis--
+			},
+			expErrMsg: "input 0",
+		},
+		"wrong key": {
+			tamper: func(t *testing.T, f *txtest.Fixture) {
+				assert.NoError(t, f.Tx.FillInput(context.Background(), &unlocker.Simple{PrivateKey: f.Recipients[0]},
+					transaction.UnlockerParams{InputIdx: 1}))
+			},
+			expErrMsg: "public key does not match",
+		},
+		"unsigned input": {
+			tamper: func(t *testing.T, f *txtest.Fixture) {
+				f.Tx.Inputs[1].UnlockingScript = nil
+			},
+			expErrMsg: "input 1",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := txtest.NewFixture(t, 2, 1)
+			if test.tamper != nil {
+				test.tamper(t, f)
+			}
+
+			err := f.Tx.VerifyInputSignatures()
+			if test.expErrMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, transaction.ErrInvalidSignature)
+			assert.Contains(t, err.Error(), test.expErrMsg)
+		})
+	}
+}
+
+func TestSimple_LowR(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	script, err := bscript.NewP2PKHFromPubKeyEC(priv.PubKey())
+	assert.NoError(t, err)
+
+	tx := transaction.NewTx()
+	for i := uint32(0); i < 16; i++ {
+		assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", i, script.String(), 1000))
+	}
+	assert.NoError(t, tx.PayTo(script, 15000))
+	assert.NoError(t, tx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: priv, LowR: true}))
+	assert.NoError(t, tx.VerifyInputSignatures())
+
+	for i, in := range tx.Inputs {
+		parts, err := bscript.DecodeParts(*in.UnlockingScript)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(parts[0]), 71, "input %d", i)
+	}
+}
@@ -1,9 +1,12 @@
 package transaction
 
 import (
+	"context"
 	"encoding/hex"
+	"fmt"
 
 	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/crypto"
 )
 
 // UTXO an unspent transaction output, used for creating inputs
@@ -16,6 +19,28 @@ type UTXO struct {
 	Unlocker       *Unlocker       `json:"-"`
 }
 
+// SpentChecker reports whether an outpoint, in the form `txid:vout` as returned
+// by UTXO.Outpoint, has already been spent. It is used by Fund, through FundOptions,
+// to skip spent utxos from a cached utxo set.
+type SpentChecker interface {
+	IsSpent(ctx context.Context, outpoint string) (bool, error)
+}
+
+// unspentUTXOs returns the utxos which the SpentChecker reports as unspent.
+func unspentUTXOs(ctx context.Context, sc SpentChecker, utxos []*UTXO) ([]*UTXO, error) {
+	unspent := make([]*UTXO, 0, len(utxos))
+	for _, u := range utxos {
+		spent, err := sc.IsSpent(ctx, u.Outpoint())
+		if err != nil {
+			return nil, fmt.Errorf("checking %s is unspent: %w", u.Outpoint(), err)
+		}
+		if !spent {
+			unspent = append(unspent, u)
+		}
+	}
+	return unspent, nil
+}
+
 // UTXOs a collection of *bt.UTXO.
 type UTXOs []*UTXO
 
@@ -47,6 +72,65 @@ func (u *UTXOs) NodeJSON() interface{} {
 	return (*nodeUTXOsWrapper)(u)
 }
 
+// IndexOutputs scans the outputs of txs for those paying to any of pubKeyHashes,
+// and returns them as UTXOs ready to be spent, in the order of txs and then of
+// their outputs. It is the bulk version of Tx.OutputsForPublicKey, for building a
+// wallet's utxo set from its tx history.
+//
+// P2PKH outputs match on the hash in the script, and P2PK outputs on the hash160
+// of the public key. The hashes are held in a set, so the scan is linear in the
+// number of outputs however many hashes are given. Each UTXO holds a copy of the
+// locking script. Spent outputs are not excluded, see SpentChecker.
+func IndexOutputs(txs []*Tx, pubKeyHashes [][]byte) []*UTXO {
+	hashes := make(map[string]struct{}, len(pubKeyHashes))
+	for _, h := range pubKeyHashes {
+		hashes[string(h)] = struct{}{}
+	}
+
+	utxos := make([]*UTXO, 0)
+	if len(hashes) == 0 {
+		return utxos
+	}
+	for _, tx := range txs {
+		if tx == nil {
+			continue
+		}
+		var txID []byte
+		for vout, o := range tx.Outputs {
+			if o.LockingScript == nil {
+				continue
+			}
+			var pkh []byte
+			switch {
+			case o.LockingScript.IsP2PKH():
+				pkh = (*o.LockingScript)[3:23]
+			case o.LockingScript.IsP2PK():
+				parts, err := bscript.DecodeParts(*o.LockingScript)
+				if err != nil {
+					continue
+				}
+				pkh = crypto.Hash160(parts[0])
+			default:
+				continue
+			}
+			if _, ok := hashes[string(pkh)]; !ok {
+				continue
+			}
+
+			if txID == nil {
+				txID = tx.TxIDBytes()
+			}
+			utxos = append(utxos, &UTXO{
+				TxID:          txID,
+				Vout:          uint32(vout),
+				LockingScript: bscript.NewFromBytes(append([]byte(nil), *o.LockingScript...)),
+				Satoshis:      o.Satoshis,
+			})
+		}
+	}
+	return utxos
+}
+
 // TxIDStr return the tx id as a string.
 func (u *UTXO) TxIDStr() string {
 	return hex.EncodeToString(u.TxID)
@@ -56,3 +140,83 @@ func (u *UTXO) TxIDStr() string {
 func (u *UTXO) LockingScriptHex() string {
 	return u.LockingScript.String()
 }
+
+// Outpoint returns the outpoint of the utxo in the form `txid:vout`.
+func (u *UTXO) Outpoint() string {
+	return fmt.Sprintf("%s:%d", u.TxIDStr(), u.Vout)
+}
+
+// Validate checks the utxo can be used to build an input, returning an
+// ErrInvalidUTXO error if the txid is not 32 bytes, the locking script
+// is missing, or the satoshi value is zero.
+func (u *UTXO) Validate() error {
+	if !IsValidTxID(u.TxID) {
+		return fmt.Errorf("%w: txid must be 32 bytes, got %d", ErrInvalidUTXO, len(u.TxID))
+	}
+	if u.LockingScript == nil {
+		return fmt.Errorf("%w: locking script is nil", ErrInvalidUTXO)
+	}
+	if u.Satoshis == 0 {
+		return fmt.Errorf("%w: satoshis is zero", ErrInvalidUTXO)
+	}
+	return nil
+}
+
+// NewConsolidationGetter returns a UTXOGetterFunc, for use with tx.Fund(...), which
+// supplies as many of the provided utxos as possible, up to maxInputs, rather than
+// only enough to cover the deficit. A maxInputs of zero or less means no cap.
+//
+// Paired with a single output (or a call to Change after funding), this builds a
+// consolidation tx sweeping many small outputs into one.
+//
+// On its first call the getter returns up to maxInputs utxos in a single batch,
+// regardless of the deficit it is given. Every call after that returns ErrNoUTXO,
+// which ends Fund's deficit loop. If the capped inputs do not cover the outputs
+// and fees, Fund will therefore return ErrInsufficientFunds instead of asking for
+// more inputs; the utxos are still added to the tx.
+func NewConsolidationGetter(utxos []*UTXO, maxInputs int) UTXOGetterFunc {
+	if maxInputs <= 0 || maxInputs > len(utxos) {
+		maxInputs = len(utxos)
+	}
+	remaining := utxos[:maxInputs]
+
+	return func(ctx context.Context, deficit uint64) ([]*UTXO, error) {
+		if len(remaining) == 0 {
+			return nil, ErrNoUTXO
+		}
+		batch := remaining
+		remaining = nil
+		return batch, nil
+	}
+}
+
+// ConsolidationNetValue returns the satoshis left after spending all the utxos
+// into a single P2PKH output, that is their total value minus the estimated fee
+// to do so. The value is negative when the fee exceeds the utxos' value, meaning
+// consolidating them would lose money.
+//
+// The fee is estimated in the same way as Fund, so only P2PKH utxos are supported.
+func ConsolidationNetValue(utxos []*UTXO, fq *FeeQuote) (int64, error) {
+	tx := NewTx()
+	if err := tx.FromUTXOs(utxos...); err != nil {
+		return 0, err
+	}
+
+	s, err := bscript.NewP2PKHFromPubKeyHash(make([]byte, 20))
+	if err != nil {
+		return 0, err
+	}
+	tx.AddOutput(&Output{LockingScript: s})
+
+	fees, err := tx.EstimateFeesPaid(fq)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := tx.TotalInputSatoshisChecked()
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(total) - int64(fees.TotalFeePaid), nil
+}
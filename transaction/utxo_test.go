@@ -0,0 +1,459 @@
+package transaction_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/crypto"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConsolidationGetter(t *testing.T) {
+	txID, err := hex.DecodeString("31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+
+	utxos := make([]*transaction.UTXO, 10)
+	for i := range utxos {
+		utxos[i] = &transaction.UTXO{TxID: txID, Vout: uint32(i), LockingScript: script, Satoshis: 1000}
+	}
+
+	t.Run("stops at the cap", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 500))
+
+		assert.NoError(t, tx.Fund(context.Background(), transaction.NewFeeQuote(), transaction.NewConsolidationGetter(utxos, 4)))
+		assert.Equal(t, 4, tx.InputCount())
+	})
+
+	t.Run("no cap uses all utxos", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 500))
+
+		assert.NoError(t, tx.Fund(context.Background(), transaction.NewFeeQuote(), transaction.NewConsolidationGetter(utxos, 0)))
+		assert.Equal(t, 10, tx.InputCount())
+	})
+
+	t.Run("insufficient funds at the cap", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 5000))
+
+		err := tx.Fund(context.Background(), transaction.NewFeeQuote(), transaction.NewConsolidationGetter(utxos, 2))
+		assert.ErrorIs(t, err, transaction.ErrInsufficientFunds)
+		assert.Equal(t, 2, tx.InputCount())
+
+		var fundsErr *transaction.InsufficientFundsError
+		assert.ErrorAs(t, err, &fundsErr)
+		assert.Equal(t, uint64(2000), fundsErr.Available)
+		assert.Greater(t, fundsErr.Required, uint64(5000))
+		assert.Equal(t, fundsErr.Required-2000, fundsErr.Shortfall())
+		assert.Contains(t, err.Error(), "more needed")
+	})
+}
+
+func TestConsolidationNetValue(t *testing.T) {
+	txID, err := hex.DecodeString("31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+
+	newUTXOs := func(n int, sats uint64) []*transaction.UTXO {
+		utxos := make([]*transaction.UTXO, n)
+		for i := range utxos {
+			utxos[i] = &transaction.UTXO{TxID: txID, Vout: uint32(i), LockingScript: script, Satoshis: sats}
+		}
+		return utxos
+	}
+
+	t.Run("worthwhile", func(t *testing.T) {
+		utxos := newUTXOs(10, 1000)
+		net, err := transaction.ConsolidationNetValue(utxos, transaction.NewFeeQuote())
+		assert.NoError(t, err)
+		assert.Greater(t, net, int64(0))
+		assert.Less(t, net, int64(10000))
+
+		// the net value should be exactly what a consolidation tx can pay out
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.FromUTXOs(utxos...))
+		assert.NoError(t, tx.PayTo(script, uint64(net)))
+		ok, err := tx.EstimateIsFeePaidEnough(transaction.NewFeeQuote())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("dust loses money", func(t *testing.T) {
+		net, err := transaction.ConsolidationNetValue(newUTXOs(10, 1), transaction.NewFeeQuote())
+		assert.NoError(t, err)
+		assert.Less(t, net, int64(0))
+	})
+
+	t.Run("unsupported script", func(t *testing.T) {
+		utxos := newUTXOs(1, 1000)
+		utxos[0].LockingScript = bscript.NewFromBytes([]byte{bscript.OpTRUE})
+		_, err := transaction.ConsolidationNetValue(utxos, transaction.NewFeeQuote())
+		assert.ErrorIs(t, err, transaction.ErrUnsupportedScript)
+	})
+}
+
+func TestTx_SweepTo(t *testing.T) {
+	txID, err := hex.DecodeString("31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+
+	newUTXOs := func(n int, satoshis uint64) []*transaction.UTXO {
+		utxos := make([]*transaction.UTXO, n)
+		for i := range utxos {
+			utxos[i] = &transaction.UTXO{TxID: txID, Vout: uint32(i), LockingScript: script, Satoshis: satoshis}
+		}
+		return utxos
+	}
+
+	t.Run("sweeps all utxos into one output", func(t *testing.T) {
+		tx := transaction.NewTx()
+		err := tx.SweepTo(context.Background(), "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd",
+			transaction.NewFeeQuote(), transaction.NewConsolidationGetter(newUTXOs(3, 1000), 0))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, tx.InputCount())
+		assert.Equal(t, 1, tx.OutputCount())
+		swept := tx.Outputs[0].Satoshis
+
+		size, err := tx.EstimateSize()
+		assert.NoError(t, err)
+		fee := uint64(3000) - swept
+		assert.GreaterOrEqual(t, fee, uint64(size)*5/100)
+		assert.Less(t, fee, uint64(size))
+	})
+
+	t.Run("dust remainder", func(t *testing.T) {
+		tx := transaction.NewTx()
+		err := tx.SweepTo(context.Background(), "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd",
+			transaction.NewFeeQuote(), transaction.NewConsolidationGetter(newUTXOs(1, 20), 0))
+		assert.ErrorIs(t, err, transaction.ErrInsufficientFunds)
+		assert.Equal(t, 1, tx.InputCount())
+		assert.Equal(t, 0, tx.OutputCount())
+	})
+
+	t.Run("getter error", func(t *testing.T) {
+		tx := transaction.NewTx()
+		err := tx.SweepTo(context.Background(), "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd", transaction.NewFeeQuote(),
+			func(context.Context, uint64) ([]*transaction.UTXO, error) {
+				return nil, context.DeadlineExceeded
+			})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestTx_Fund_Cancelled(t *testing.T) {
+	txID, err := hex.DecodeString("31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+
+	t.Run("cancelled between calls", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 5000))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		calls := 0
+		err := tx.Fund(ctx, transaction.NewFeeQuote(), func(context.Context, uint64) ([]*transaction.UTXO, error) {
+			calls++
+			cancel()
+			return []*transaction.UTXO{{TxID: txID, Vout: uint32(calls), LockingScript: script, Satoshis: 1000}}, nil
+		})
+		assert.ErrorIs(t, err, transaction.ErrFundCancelled)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 1, tx.InputCount())
+	})
+
+	t.Run("getter returns context error", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 5000))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := tx.Fund(ctx, transaction.NewFeeQuote(), func(ctx context.Context, _ uint64) ([]*transaction.UTXO, error) {
+			cancel()
+			return nil, ctx.Err()
+		})
+		assert.ErrorIs(t, err, transaction.ErrFundCancelled)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, tx.InputCount())
+	})
+}
+
+func TestTx_Fund_AutoChange(t *testing.T) {
+	txID, err := hex.DecodeString("31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+	changeScript, err := bscript.NewP2PKHFromAddress("mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd")
+	assert.NoError(t, err)
+
+	utxos := make([]*transaction.UTXO, 10)
+	for i := range utxos {
+		utxos[i] = &transaction.UTXO{TxID: txID, Vout: uint32(i), LockingScript: script, Satoshis: 1000}
+	}
+
+	t.Run("adds change once funded", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 1500))
+
+		fq := transaction.NewFeeQuote()
+		assert.NoError(t, tx.Fund(context.Background(), fq, transaction.NewConsolidationGetter(utxos, 0), transaction.FundOptions{
+			AutoChange:   true,
+			ChangeScript: changeScript,
+		}))
+		assert.Equal(t, 2, tx.OutputCount())
+		assert.Equal(t, changeScript, tx.Outputs[1].LockingScript)
+
+		fees, err := tx.EstimateFeesPaid(fq)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, tx.TotalInputSatoshis()-tx.TotalOutputSatoshis(), fees.TotalFeePaid)
+	})
+
+	t.Run("fee covers a non p2pkh change script", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 1500))
+
+		large := bscript.NewFromBytes(bytes.Repeat([]byte{bscript.OpNOP}, 1000))
+		fq := transaction.NewFeeQuote()
+		assert.NoError(t, tx.Fund(context.Background(), fq, transaction.NewConsolidationGetter(utxos, 0), transaction.FundOptions{
+			AutoChange:   true,
+			ChangeScript: large,
+		}))
+		assert.Equal(t, 2, tx.OutputCount())
+		assert.Equal(t, large, tx.Outputs[1].LockingScript)
+
+		ok, err := tx.EstimateIsFeePaidEnough(fq)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("no change by default", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 1500))
+
+		assert.NoError(t, tx.Fund(context.Background(), transaction.NewFeeQuote(), transaction.NewConsolidationGetter(utxos, 0)))
+		assert.Equal(t, 1, tx.OutputCount())
+	})
+
+	t.Run("change script required", func(t *testing.T) {
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 1500))
+
+		err := tx.Fund(context.Background(), transaction.NewFeeQuote(), transaction.NewConsolidationGetter(utxos, 0), transaction.FundOptions{
+			AutoChange: true,
+		})
+		assert.ErrorIs(t, err, transaction.ErrNoChangeScript)
+		assert.Equal(t, 0, tx.InputCount())
+	})
+}
+
+func TestTx_TopUp(t *testing.T) {
+	txID, err := hex.DecodeString("31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+
+	utxos := make([]*transaction.UTXO, 10)
+	for i := range utxos {
+		utxos[i] = &transaction.UTXO{TxID: txID, Vout: uint32(i), LockingScript: script, Satoshis: 1000}
+	}
+
+	tx := transaction.NewTx()
+	assert.NoError(t, tx.FromUTXOs(utxos[0]))
+	assert.NoError(t, tx.PayTo(script, 500))
+
+	calls := 0
+	next := 1
+	oneAtATime := func(context.Context, uint64) ([]*transaction.UTXO, error) {
+		calls++
+		if next == len(utxos) {
+			return nil, transaction.ErrNoUTXO
+		}
+		next++
+		return utxos[next-1 : next], nil
+	}
+
+	t.Run("already funded", func(t *testing.T) {
+		n, err := tx.TopUp(context.Background(), transaction.NewFeeQuote(), oneAtATime)
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Zero(t, calls)
+		assert.Equal(t, 1, tx.InputCount())
+	})
+
+	t.Run("underfunded after edit", func(t *testing.T) {
+		tx.Outputs[0].Satoshis = 2500
+
+		n, err := tx.TopUp(context.Background(), transaction.NewFeeQuote(), oneAtATime)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, 3, tx.InputCount())
+		assert.Equal(t, utxos[0].Vout, tx.Inputs[0].PreviousTxOutIndex)
+		assert.Equal(t, 1, tx.OutputCount())
+
+		ok, err := tx.EstimateIsFeePaidEnough(transaction.NewFeeQuote())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		tx.Outputs[0].Satoshis = 20000
+
+		n, err := tx.TopUp(context.Background(), transaction.NewFeeQuote(), oneAtATime)
+		assert.ErrorIs(t, err, transaction.ErrInsufficientFunds)
+		assert.Equal(t, 7, n)
+	})
+}
+
+type mapSpentChecker struct {
+	spent   map[string]bool
+	err     error
+	checked []string
+}
+
+func (m *mapSpentChecker) IsSpent(_ context.Context, outpoint string) (bool, error) {
+	m.checked = append(m.checked, outpoint)
+	return m.spent[outpoint], m.err
+}
+
+func TestTx_Fund_SpentChecker(t *testing.T) {
+	txID, err := hex.DecodeString("31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+
+	utxos := make([]*transaction.UTXO, 4)
+	for i := range utxos {
+		utxos[i] = &transaction.UTXO{TxID: txID, Vout: uint32(i), LockingScript: script, Satoshis: 1000}
+	}
+	oneAtATime := func() transaction.UTXOGetterFunc {
+		next := 0
+		return func(context.Context, uint64) ([]*transaction.UTXO, error) {
+			if next == len(utxos) {
+				return nil, transaction.ErrNoUTXO
+			}
+			next++
+			return utxos[next-1 : next], nil
+		}
+	}
+
+	t.Run("skips spent utxos", func(t *testing.T) {
+		sc := &mapSpentChecker{spent: map[string]bool{
+			utxos[0].Outpoint(): true,
+			utxos[2].Outpoint(): true,
+		}}
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 1500))
+
+		assert.NoError(t, tx.Fund(context.Background(), transaction.NewFeeQuote(), oneAtATime(), transaction.FundOptions{SpentChecker: sc}))
+		assert.Equal(t, 2, tx.InputCount())
+		assert.Equal(t, uint32(1), tx.Inputs[0].PreviousTxOutIndex)
+		assert.Equal(t, uint32(3), tx.Inputs[1].PreviousTxOutIndex)
+		assert.Len(t, sc.checked, 4)
+	})
+
+	t.Run("all spent", func(t *testing.T) {
+		sc := &mapSpentChecker{spent: map[string]bool{}}
+		for _, u := range utxos {
+			sc.spent[u.Outpoint()] = true
+		}
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 500))
+
+		err := tx.Fund(context.Background(), transaction.NewFeeQuote(), oneAtATime(), transaction.FundOptions{SpentChecker: sc})
+		assert.ErrorIs(t, err, transaction.ErrInsufficientFunds)
+		assert.Equal(t, 0, tx.InputCount())
+	})
+
+	t.Run("checker error", func(t *testing.T) {
+		sc := &mapSpentChecker{err: context.DeadlineExceeded}
+		tx := transaction.NewTx()
+		assert.NoError(t, tx.PayTo(script, 500))
+
+		err := tx.Fund(context.Background(), transaction.NewFeeQuote(), oneAtATime(), transaction.FundOptions{SpentChecker: sc})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), utxos[0].Outpoint())
+		assert.Equal(t, 0, tx.InputCount())
+	})
+}
+
+func TestIndexOutputs(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*ec.PrivateKey, 4)
+	scripts := make([]*bscript.Script, len(keys))
+	for i := range keys {
+		k, err := ec.NewPrivateKey()
+		assert.NoError(t, err)
+		keys[i] = k
+		scripts[i], err = bscript.NewP2PKHFromPubKeyEC(k.PubKey())
+		assert.NoError(t, err)
+	}
+	p2pk := &bscript.Script{}
+	assert.NoError(t, p2pk.AppendPushData(keys[1].PubKey().SerialiseCompressed()))
+	assert.NoError(t, p2pk.AppendOpcodes(bscript.OpCHECKSIG))
+
+	// 20 txs of 250 outputs, cycling through the keys and a data output, with
+	// keys 0 and 1 ours and key 1 also paid by P2PK every tenth output.
+	txs := make([]*transaction.Tx, 20)
+	var expected int
+	for i := range txs {
+		tx := transaction.NewTx()
+		for j := 0; j < 250; j++ {
+			switch {
+			case j%10 == 9:
+				tx.AddOutput(&transaction.Output{LockingScript: p2pk, Satoshis: uint64(j + 1)})
+				expected++
+			case j%5 == 4:
+				assert.NoError(t, tx.AddOpReturnOutput([]byte("data")))
+			default:
+				assert.NoError(t, tx.PayTo(scripts[j%len(scripts)], uint64(j+1)))
+				if j%len(scripts) < 2 {
+					expected++
+				}
+			}
+		}
+		txs[i] = tx
+	}
+	txs = append(txs, nil)
+
+	ours := [][]byte{
+		crypto.Hash160(keys[0].PubKey().SerialiseCompressed()),
+		crypto.Hash160(keys[1].PubKey().SerialiseCompressed()),
+	}
+	utxos := transaction.IndexOutputs(txs, ours)
+	assert.Len(t, utxos, expected)
+
+	byID := make(map[string]*transaction.Tx, 20)
+	for _, tx := range txs[:20] {
+		byID[tx.TxID()] = tx
+	}
+	for _, u := range utxos {
+		tx, ok := byID[u.TxIDStr()]
+		if !assert.True(t, ok) {
+			continue
+		}
+		o := tx.Outputs[u.Vout]
+		assert.Equal(t, o.Satoshis, u.Satoshis)
+		assert.Equal(t, o.LockingScript.String(), u.LockingScript.String())
+		assert.NotEqual(t, scripts[2].String(), u.LockingScript.String())
+		assert.NotEqual(t, scripts[3].String(), u.LockingScript.String())
+	}
+
+	// the utxos can be spent
+	spend := transaction.NewTx()
+	assert.NoError(t, spend.FromUTXOs(utxos[:3]...))
+
+	assert.Empty(t, transaction.IndexOutputs(txs, nil))
+}
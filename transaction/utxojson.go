@@ -14,10 +14,12 @@ type nodeUTXOWrapper struct {
 type nodeUTXOsWrapper UTXOs
 
 type utxoJSON struct {
-	TxID          string `json:"txid"`
-	Vout          uint32 `json:"vout"`
-	LockingScript string `json:"lockingScript"`
-	Satoshis      uint64 `json:"satoshis"`
+	TxID         string `json:"txid"`
+	Vout         uint32 `json:"vout"`
+	Satoshis     uint64 `json:"satoshis"`
+	ScriptPubKey string `json:"scriptPubKey"`
+	// LockingScript duplicates ScriptPubKey for readers of the previous format.
+	LockingScript string `json:"lockingScript,omitempty"`
 }
 
 type utxoNodeJSON struct {
@@ -28,6 +30,11 @@ type utxoNodeJSON struct {
 }
 
 // UnmarshalJSON will convert a json serialised utxo to a bt.UTXO.
+//
+// The locking script is read from `scriptPubKey`, as in the common
+// `{txid, vout, satoshis, scriptPubKey}` shape, or from `lockingScript` as
+// written by earlier versions. The utxo is validated after unmarshalling, see
+// UTXO.Validate.
 func (u *UTXO) UnmarshalJSON(body []byte) error {
 	var j utxoJSON
 	if err := json.Unmarshal(body, &j); err != nil {
@@ -39,26 +46,44 @@ func (u *UTXO) UnmarshalJSON(body []byte) error {
 		return err
 	}
 
-	lscript, err := bscript.NewFromHex(j.LockingScript)
+	script := j.ScriptPubKey
+	if script == "" {
+		script = j.LockingScript
+	}
+	lscript, err := bscript.NewFromHex(script)
 	if err != nil {
 		return err
 	}
 
-	u.TxID = txID
-	u.LockingScript = lscript
-	u.Vout = j.Vout
-	u.Satoshis = j.Satoshis
+	utxo := UTXO{
+		TxID:          txID,
+		LockingScript: lscript,
+		Vout:          j.Vout,
+		Satoshis:      j.Satoshis,
+	}
+	if err = utxo.Validate(); err != nil {
+		return err
+	}
+
+	u.TxID = utxo.TxID
+	u.LockingScript = utxo.LockingScript
+	u.Vout = utxo.Vout
+	u.Satoshis = utxo.Satoshis
 
 	return nil
 }
 
-// MarshalJSON will serialise a utxo to json.
+// MarshalJSON will serialise a utxo to json in the `{txid, vout, satoshis,
+// scriptPubKey}` shape. The script is also written as `lockingScript`, the field
+// used by earlier versions.
 func (u *UTXO) MarshalJSON() ([]byte, error) {
+	script := u.LockingScriptHex()
 	return json.Marshal(utxoJSON{
 		TxID:          u.TxIDStr(),
-		Satoshis:      u.Satoshis,
 		Vout:          u.Vout,
-		LockingScript: u.LockingScriptHex(),
+		Satoshis:      u.Satoshis,
+		ScriptPubKey:  script,
+		LockingScript: script,
 	})
 }
 
@@ -66,8 +66,9 @@ func TestUTXO_MarshalJSON(t *testing.T) {
 			exp: `{
     "txid": "31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7",
     "vout": 0,
-    "lockingScript": "2102076ad7c107f82ae973fbdaa1d84532c8d69e3838bcbee1570efe0fa30b3cb25bac",
-    "satoshis": 1250000000
+    "satoshis": 1250000000,
+    "scriptPubKey": "2102076ad7c107f82ae973fbdaa1d84532c8d69e3838bcbee1570efe0fa30b3cb25bac",
+    "lockingScript": "2102076ad7c107f82ae973fbdaa1d84532c8d69e3838bcbee1570efe0fa30b3cb25bac"
 }`,
 		},
 	}
@@ -188,3 +189,71 @@ func TestUTXO_Node_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestUTXO_UnmarshalJSON(t *testing.T) {
+	tests := map[string]struct {
+		utxoJSON string
+		expErr   error
+	}{
+		"scriptPubKey can be unmarshalled": {
+			utxoJSON: `{
+    "txid": "31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7",
+    "vout": 1,
+    "scriptPubKey": "2102076ad7c107f82ae973fbdaa1d84532c8d69e3838bcbee1570efe0fa30b3cb25bac",
+    "satoshis": 1000
+}`,
+		},
+		"lockingScript can be unmarshalled": {
+			utxoJSON: `{
+    "txid": "31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7",
+    "vout": 1,
+    "lockingScript": "2102076ad7c107f82ae973fbdaa1d84532c8d69e3838bcbee1570efe0fa30b3cb25bac",
+    "satoshis": 1000
+}`,
+		},
+		"short txid is rejected": {
+			utxoJSON: `{
+    "txid": "31ad4b5e",
+    "vout": 1,
+    "scriptPubKey": "2102076ad7c107f82ae973fbdaa1d84532c8d69e3838bcbee1570efe0fa30b3cb25bac",
+    "satoshis": 1000
+}`,
+			expErr: transaction.ErrInvalidUTXO,
+		},
+		"zero satoshis is rejected": {
+			utxoJSON: `{
+    "txid": "31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7",
+    "vout": 1,
+    "lockingScript": "2102076ad7c107f82ae973fbdaa1d84532c8d69e3838bcbee1570efe0fa30b3cb25bac",
+    "satoshis": 0
+}`,
+			expErr: transaction.ErrInvalidUTXO,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var utxo transaction.UTXO
+			err := json.Unmarshal([]byte(test.utxoJSON), &utxo)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7:1", utxo.Outpoint())
+			assert.Equal(t, "2102076ad7c107f82ae973fbdaa1d84532c8d69e3838bcbee1570efe0fa30b3cb25bac", utxo.LockingScriptHex())
+		})
+	}
+}
+
+func TestUTXO_Validate(t *testing.T) {
+	txID, err := hex.DecodeString("31ad4b5ef1d0d48340e063087cbfa6a3f3dea3cd5d34c983e0028c18daf3d2a7")
+	assert.NoError(t, err)
+	script, err := bscript.NewFromHex("76a9148bf10d323ac757268eb715e613cb8e8e1d1793aa88ac")
+	assert.NoError(t, err)
+
+	assert.NoError(t, (&transaction.UTXO{TxID: txID, LockingScript: script, Satoshis: 1}).Validate())
+	assert.ErrorIs(t, (&transaction.UTXO{TxID: txID[:31], LockingScript: script, Satoshis: 1}).Validate(), transaction.ErrInvalidUTXO)
+	assert.ErrorIs(t, (&transaction.UTXO{TxID: txID, Satoshis: 1}).Validate(), transaction.ErrInvalidUTXO)
+	assert.ErrorIs(t, (&transaction.UTXO{TxID: txID, LockingScript: script}).Validate(), transaction.ErrInvalidUTXO)
+}
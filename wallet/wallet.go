@@ -0,0 +1,172 @@
+// Package wallet ties a private key, a source of utxos and a fee quote together
+// to build ready to broadcast transactions in a single call.
+//
+// It is a thin layer over the transaction package, composing tx.Fund, tx.ChangeToAddress
+// and tx.FillAllInputs. Anything it does not cover can be done with those directly.
+package wallet
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bitcoin-sv/go-sdk/bip32"
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/unlocker"
+)
+
+// UTXOStore supplies the utxos a Wallet spends from.
+type UTXOStore interface {
+	// UTXOs behaves as a transaction.UTXOGetterFunc, returning utxos to cover the
+	// deficit and transaction.ErrNoUTXO once none remain.
+	UTXOs(ctx context.Context, deficit uint64) ([]*transaction.UTXO, error)
+}
+
+// UTXOReleaser is implemented by a UTXOStore that can take back utxos it handed
+// out, so those of a Send which failed can be spent again.
+type UTXOReleaser interface {
+	// Release returns utxos previously returned by UTXOs to the store.
+	Release(utxos ...*transaction.UTXO)
+}
+
+// Wallet spends the utxos of a UTXOStore, locked to its private key, paying
+// fees from its FeeQuote and sending change back to its own address.
+type Wallet struct {
+	privKey  *ec.PrivateKey
+	address  string
+	store    UTXOStore
+	feeQuote *transaction.FeeQuote
+}
+
+// New returns a Wallet for the private key. Change is sent to the P2PKH address
+// of the key on mainnet, or testnet if mainnet is false.
+func New(privKey *ec.PrivateKey, store UTXOStore, fq *transaction.FeeQuote, mainnet bool) (*Wallet, error) {
+	addr, err := bscript.NewAddressFromPublicKey(privKey.PubKey(), mainnet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		privKey:  privKey,
+		address:  addr.AddressString,
+		store:    store,
+		feeQuote: fq,
+	}, nil
+}
+
+// NewFromExtendedKey returns a Wallet for the private key of an HD key, see New.
+func NewFromExtendedKey(key *bip32.ExtendedKey, store UTXOStore, fq *transaction.FeeQuote, mainnet bool) (*Wallet, error) {
+	privKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(privKey, store, fq, mainnet)
+}
+
+// Address returns the address the Wallet sends change to.
+func (w *Wallet) Address() string {
+	return w.address
+}
+
+// Send builds a signed transaction paying amount satoshis to addr. The tx is funded
+// from the Wallet's UTXOStore, with any change returned to the Wallet's address.
+//
+// The tx is not broadcast. If funding fails, transaction.ErrInsufficientFunds is returned.
+// If the Send fails and the store is a UTXOReleaser, the utxos taken from it are
+// released back to it.
+func (w *Wallet) Send(ctx context.Context, addr string, amount uint64) (*transaction.Tx, error) {
+	var taken []*transaction.UTXO
+	tx, err := w.send(ctx, addr, amount, func(ctx context.Context, deficit uint64) ([]*transaction.UTXO, error) {
+		utxos, err := w.store.UTXOs(ctx, deficit)
+		taken = append(taken, utxos...)
+		return utxos, err
+	})
+	if err != nil {
+		if r, ok := w.store.(UTXOReleaser); ok && len(taken) > 0 {
+			r.Release(taken...)
+		}
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (w *Wallet) send(ctx context.Context, addr string, amount uint64, next transaction.UTXOGetterFunc) (*transaction.Tx, error) {
+	tx := transaction.NewTx()
+	if err := tx.PayToAddress(addr, amount); err != nil {
+		return nil, err
+	}
+	if err := tx.Fund(ctx, w.feeQuote, next); err != nil {
+		return nil, err
+	}
+	if err := tx.ChangeToAddress(w.address, w.feeQuote); err != nil {
+		return nil, err
+	}
+	if err := tx.FillAllInputs(ctx, &unlocker.Getter{PrivateKey: w.privKey}); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// MemoryStore is an in memory UTXOStore, useful for tests and simple programs.
+// Each utxo is handed out once; a utxo returned from UTXOs is no longer held
+// unless it is given back with Release.
+type MemoryStore struct {
+	mu    sync.Mutex
+	utxos []*transaction.UTXO
+}
+
+// NewMemoryStore returns a MemoryStore holding the utxos.
+func NewMemoryStore(utxos ...*transaction.UTXO) *MemoryStore {
+	return &MemoryStore{utxos: utxos}
+}
+
+// Add adds utxos to the store.
+func (m *MemoryStore) Add(utxos ...*transaction.UTXO) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.utxos = append(m.utxos, utxos...)
+}
+
+// Release returns utxos handed out by UTXOs to the store, ahead of those still
+// held, as after a failed Send.
+func (m *MemoryStore) Release(utxos ...*transaction.UTXO) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.utxos = append(append(make([]*transaction.UTXO, 0, len(utxos)+len(m.utxos)), utxos...), m.utxos...)
+}
+
+// Balance returns the total value of the utxos held.
+func (m *MemoryStore) Balance() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var total uint64
+	for _, u := range m.utxos {
+		total += u.Satoshis
+	}
+	return total
+}
+
+// UTXOs returns held utxos, in the order they were added, until their value
+// covers the deficit. transaction.ErrNoUTXO is returned once the store is empty.
+func (m *MemoryStore) UTXOs(ctx context.Context, deficit uint64) ([]*transaction.UTXO, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.utxos) == 0 {
+		return nil, transaction.ErrNoUTXO
+	}
+
+	var total uint64
+	n := 0
+	for n < len(m.utxos) && total < deficit {
+		total += m.utxos[n].Satoshis
+		n++
+	}
+	utxos := m.utxos[:n:n]
+	m.utxos = m.utxos[n:]
+
+	return utxos, nil
+}
@@ -0,0 +1,74 @@
+package wallet_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/bscript"
+	"github.com/bitcoin-sv/go-sdk/ec/wif"
+	"github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWallet_Send(t *testing.T) {
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	script, err := bscript.NewP2PKHFromPubKeyEC(w.PrivKey.PubKey())
+	assert.NoError(t, err)
+	txID, err := hex.DecodeString("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5")
+	assert.NoError(t, err)
+
+	store := wallet.NewMemoryStore(
+		&transaction.UTXO{TxID: txID, Vout: 0, LockingScript: script, Satoshis: 1000},
+		&transaction.UTXO{TxID: txID, Vout: 1, LockingScript: script, Satoshis: 5000},
+	)
+	wlt, err := wallet.New(w.PrivKey, store, transaction.NewFeeQuote(), false)
+	assert.NoError(t, err)
+
+	t.Run("funds, adds change and signs", func(t *testing.T) {
+		tx, err := wlt.Send(context.Background(), "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd", 4000)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, tx.InputCount())
+		assert.Equal(t, 2, tx.OutputCount())
+		assert.Equal(t, uint64(4000), tx.Outputs[0].Satoshis)
+
+		change, err := bscript.NewP2PKHFromAddress(wlt.Address())
+		assert.NoError(t, err)
+		assert.Equal(t, change, tx.Outputs[1].LockingScript)
+		assert.NoError(t, tx.VerifyInputSignatures())
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		_, err := wlt.Send(context.Background(), "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd", 4000)
+		assert.ErrorIs(t, err, transaction.ErrInsufficientFunds)
+	})
+
+	t.Run("failed send releases utxos", func(t *testing.T) {
+		store := wallet.NewMemoryStore(
+			&transaction.UTXO{TxID: txID, Vout: 2, LockingScript: script, Satoshis: 1000},
+			&transaction.UTXO{TxID: txID, Vout: 3, LockingScript: script, Satoshis: 2000},
+		)
+		wlt, err := wallet.New(w.PrivKey, store, transaction.NewFeeQuote(), false)
+		assert.NoError(t, err)
+
+		_, err = wlt.Send(context.Background(), "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd", 4000)
+		assert.ErrorIs(t, err, transaction.ErrInsufficientFunds)
+		assert.Equal(t, uint64(3000), store.Balance())
+
+		tx, err := wlt.Send(context.Background(), "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd", 2500)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, tx.InputCount())
+		assert.Zero(t, store.Balance())
+
+		// funding succeeds, but the wallet cannot sign for the utxo
+		store = wallet.NewMemoryStore(&transaction.UTXO{TxID: txID, Vout: 4,
+			LockingScript: bscript.NewFromBytes([]byte{bscript.Op1}), Satoshis: 5000})
+		wlt, err = wallet.New(w.PrivKey, store, transaction.NewFeeQuote(), false)
+		assert.NoError(t, err)
+		_, err = wlt.Send(context.Background(), "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd", 1000)
+		assert.Error(t, err)
+		assert.Equal(t, uint64(5000), store.Balance())
+	})
+}